@@ -11,10 +11,17 @@ import (
 	"time"
 
 	"github.com/0DayMonxrch/project-management-system/internal/config"
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/events"
 	"github.com/0DayMonxrch/project-management-system/internal/handler"
+	"github.com/0DayMonxrch/project-management-system/internal/jwtkeys"
 	"github.com/0DayMonxrch/project-management-system/internal/middleware"
+	"github.com/0DayMonxrch/project-management-system/internal/oidc"
 	"github.com/0DayMonxrch/project-management-system/internal/repository"
 	"github.com/0DayMonxrch/project-management-system/internal/service"
+	"github.com/0DayMonxrch/project-management-system/internal/storage"
+	"github.com/0DayMonxrch/project-management-system/internal/throttle"
+	"github.com/0DayMonxrch/project-management-system/internal/webhook"
 	"github.com/0DayMonxrch/project-management-system/migrations"
 	"github.com/0DayMonxrch/project-management-system/pkg/logger"
 	"github.com/joho/godotenv"
@@ -54,32 +61,118 @@ func main() {
 		log.Error("failed to run migrations", "error", err)
 		os.Exit(1)
 	}
+	if err := migrations.BackfillWorkflows(db, log); err != nil {
+		log.Error("failed to run migrations", "error", err)
+		os.Exit(1)
+	}
+	if err := migrations.BackfillRoles(db, log); err != nil {
+		log.Error("failed to run migrations", "error", err)
+		os.Exit(1)
+	}
 
 	// Repositories
 	userRepo := repository.NewUserRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
 	projectRepo := repository.NewProjectRepository(db)
 	taskRepo := repository.NewTaskRepository(db)
 	noteRepo := repository.NewNoteRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	activityRepo := repository.NewActivityRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	deliveryRepo := repository.NewWebhookDeliveryRepository(db)
+	accessTokenRepo := repository.NewAccessTokenRepository(db)
+	invitationRepo := repository.NewInvitationRepository(db)
+	joinLinkRepo := repository.NewJoinLinkRepository(db)
+
+	// Storage
+	blobStore, err := storage.NewLocalStorage(cfg.Upload.Dir)
+	if err != nil {
+		log.Error("failed to initialise upload storage", "error", err)
+		os.Exit(1)
+	}
+
+	// Event bus, with every event also queued for matching webhooks
+	webhookSvc := service.NewWebhookService(webhookRepo, deliveryRepo, projectRepo, auditRepo)
+	eventBus := events.Tee(events.NewMemoryBus(cfg.Events.HistorySize), func(ctx context.Context, e domain.ProjectEvent) {
+		if err := webhookSvc.Enqueue(ctx, e); err != nil {
+			log.Error("failed to queue webhook deliveries", "event", e.Type, "error", err)
+		}
+	})
+
+	// Login throttling
+	var attemptStore domain.AttemptStore
+	switch cfg.Auth.Throttle.Store {
+	case "", "memory":
+		attemptStore = throttle.NewMemoryStore()
+	case "mongo":
+		attemptStore = repository.NewAttemptStore(db)
+	default:
+		log.Error("unknown throttle store", "store", cfg.Auth.Throttle.Store)
+		os.Exit(1)
+	}
+
+	// Access token signing keys
+	jwtKeys, err := jwtkeys.Load(cfg.JWT)
+	if err != nil {
+		log.Error("failed to load jwt keys", "error", err)
+		os.Exit(1)
+	}
 
 	// Services
 	emailSvc := service.NewEmailService(cfg.SMTP)
-	authSvc := service.NewAuthService(userRepo, emailSvc, cfg.JWT)
-	projectSvc := service.NewProjectService(projectRepo, userRepo)
-	taskSvc := service.NewTaskService(taskRepo, projectRepo)
-	noteSvc := service.NewNoteService(noteRepo, projectRepo)
+	var identityProviders []domain.IdentityProvider
+	for _, p := range cfg.Auth.OIDCProviders {
+		identityProviders = append(identityProviders, oidc.NewProvider(p))
+	}
+	tokenVersions := service.NewTokenVersionCache(userRepo, time.Duration(cfg.JWT.VersionCacheSeconds)*time.Second)
+	sessionCache := service.NewSessionCache(sessionRepo, time.Duration(cfg.JWT.VersionCacheSeconds)*time.Second)
+	invitationSvc := service.NewInvitationService(invitationRepo, projectRepo, userRepo, auditRepo, emailSvc, eventBus)
+	authSvc := service.NewAuthService(userRepo, sessionRepo, auditRepo, emailSvc, tokenVersions, sessionCache, attemptStore, invitationSvc, identityProviders, jwtKeys, cfg.JWT, cfg.Auth, log)
+	projectSvc := service.NewProjectService(projectRepo, userRepo, taskRepo, invitationRepo, joinLinkRepo, auditRepo, eventBus)
+	joinLinkSvc := service.NewJoinLinkService(joinLinkRepo, projectRepo, auditRepo, eventBus, log)
+	taskSvc := service.NewTaskService(taskRepo, projectRepo, activityRepo, commentRepo, blobStore, eventBus, log)
+	noteSvc := service.NewNoteService(noteRepo, projectRepo, auditRepo, eventBus)
+	attachmentSvc := service.NewAttachmentService(taskRepo, projectRepo, activityRepo, blobStore, cfg.Upload, eventBus, log)
+	commentSvc := service.NewCommentService(commentRepo, taskRepo, projectRepo, userRepo)
+	auditSvc := service.NewAuditService(auditRepo, projectRepo, userRepo)
+	searchSvc := service.NewSearchService(projectRepo, taskRepo, noteRepo)
+	eventSvc := service.NewEventService(projectRepo, eventBus)
+	accessTokenSvc := service.NewAccessTokenService(accessTokenRepo, projectRepo, auditRepo)
+	accountSvc := service.NewAccountService(userRepo, sessionRepo, accessTokenRepo, projectRepo, taskRepo, noteRepo, commentRepo, activityRepo, invitationRepo, joinLinkRepo, webhookRepo, auditRepo, tokenVersions, eventBus, log)
 
 	// Handlers
 	authHandler := handler.NewAuthHandler(authSvc)
+	accountHandler := handler.NewAccountHandler(accountSvc)
 	projectHandler := handler.NewProjectHandler(projectSvc)
+	invitationHandler := handler.NewInvitationHandler(invitationSvc)
+	joinLinkHandler := handler.NewJoinLinkHandler(joinLinkSvc)
 	taskHandler := handler.NewTaskHandler(taskSvc)
 	noteHandler := handler.NewNoteHandler(noteSvc)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentSvc, cfg.Upload.MaxSizeMB)
+	commentHandler := handler.NewCommentHandler(commentSvc)
+	auditHandler := handler.NewAuditHandler(auditSvc)
+	searchHandler := handler.NewSearchHandler(searchSvc)
+	eventHandler := handler.NewEventHandler(eventSvc)
+	webhookHandler := handler.NewWebhookHandler(webhookSvc)
+	accessTokenHandler := handler.NewAccessTokenHandler(accessTokenSvc)
 
 	// Router
 	mux := http.NewServeMux()
-	handler.RegisterRoutes(mux, authHandler, projectHandler, taskHandler, noteHandler, cfg.JWT.AccessSecret)
+	handler.RegisterRoutes(mux, authHandler, accountHandler, projectHandler, invitationHandler, joinLinkHandler, taskHandler, noteHandler, attachmentHandler, commentHandler, auditHandler, searchHandler, eventHandler, webhookHandler, accessTokenHandler, accessTokenSvc, tokenVersions, sessionCache, projectRepo, jwtKeys)
 
-	// Global middleware chain: recovery → logger → router
-	chain := middleware.Recovery(log)(middleware.Logger(log)(mux))
+	// Global middleware chain: recovery → real IP → logger → rate limit → router
+	realIP, err := middleware.RealIP(cfg.App.TrustedProxies)
+	if err != nil {
+		log.Error("invalid trusted proxies", "error", err)
+		os.Exit(1)
+	}
+	rateLimit, err := middleware.RateLimit(cfg.RateLimit, jwtKeys)
+	if err != nil {
+		log.Error("invalid rate limit config", "error", err)
+		os.Exit(1)
+	}
+	chain := middleware.Recovery(log)(realIP(middleware.Logger(log)(rateLimit(mux))))
 
 	// Server
 	srv := &http.Server{
@@ -90,6 +183,14 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// Webhook delivery workers
+	dispatchCtx, stopDispatch := context.WithCancel(context.Background())
+	dispatchDone := make(chan struct{})
+	go func() {
+		webhook.NewDispatcher(webhookRepo, deliveryRepo, cfg.Webhooks, log).Run(dispatchCtx)
+		close(dispatchDone)
+	}()
+
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -113,5 +214,8 @@ func main() {
 		os.Exit(1)
 	}
 
+	stopDispatch()
+	<-dispatchDone
+
 	log.Info("server stopped")
 }
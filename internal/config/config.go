@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -8,17 +9,24 @@ import (
 )
 
 type Config struct {
-	App    AppConfig
-	DB     DBConfig
-	JWT    JWTConfig
-	SMTP   SMTPConfig
-	Upload UploadConfig
+	App       AppConfig
+	DB        DBConfig
+	JWT       JWTConfig
+	Auth      AuthConfig
+	SMTP      SMTPConfig
+	Upload    UploadConfig
+	Events    EventsConfig
+	Webhooks  WebhookConfig
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
 }
 
 type AppConfig struct {
 	Name string
 	Env  string
 	Port int
+	// TrustedProxies are the addresses or CIDR prefixes of reverse proxies
+	// whose X-Forwarded-For header names the client
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 type DBConfig struct {
@@ -31,6 +39,46 @@ type JWTConfig struct {
 	RefreshSecret       string `mapstructure:"refresh_secret"`
 	AccessExpiryMinutes int    `mapstructure:"access_expiry_minutes"`
 	RefreshExpiryDays   int    `mapstructure:"refresh_expiry_days"`
+	VersionCacheSeconds int    `mapstructure:"version_cache_seconds"`
+	// SigningKey is the ID of the key in Keys that signs access tokens
+	SigningKey string         `mapstructure:"signing_key"`
+	Keys       []JWTKeyConfig `mapstructure:"keys"`
+}
+
+type JWTKeyConfig struct {
+	ID   string
+	File string
+}
+
+type AuthConfig struct {
+	DisablePasswordLogin bool                 `mapstructure:"disable_password_login"`
+	OIDCProviders        []OIDCProviderConfig `mapstructure:"oidc_providers"`
+	Throttle             ThrottleConfig
+}
+
+type ThrottleConfig struct {
+	// Store is "memory" or "mongo"
+	Store            string
+	WindowMinutes    int `mapstructure:"window_minutes"`
+	FreeAttempts     int `mapstructure:"free_attempts"`
+	BaseDelaySeconds int `mapstructure:"base_delay_seconds"`
+	MaxFailures      int `mapstructure:"max_failures"`
+	IPMaxFailures    int `mapstructure:"ip_max_failures"`
+	LockoutMinutes   int `mapstructure:"lockout_minutes"`
+	MaxEmails        int `mapstructure:"max_emails"`
+}
+
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string `mapstructure:"client_id"`
+	ClientSecret string `mapstructure:"client_secret"`
+	RedirectURL  string `mapstructure:"redirect_url"`
+	Scopes       []string
+	// LinkByEmail lets a first sign-in take over the existing account with
+	// the same verified email. Only for providers that own their users'
+	// addresses, such as the company's own directory.
+	LinkByEmail bool `mapstructure:"link_by_email"`
 }
 
 type SMTPConfig struct {
@@ -42,8 +90,52 @@ type SMTPConfig struct {
 }
 
 type UploadConfig struct {
-	Dir       string
-	MaxSizeMB int `mapstructure:"max_size_mb"`
+	Dir          string
+	MaxSizeMB    int      `mapstructure:"max_size_mb"`
+	AllowedTypes []string `mapstructure:"allowed_types"`
+}
+
+type EventsConfig struct {
+	HistorySize int `mapstructure:"history_size"`
+}
+
+type WebhookConfig struct {
+	Workers             int
+	TimeoutSeconds      int `mapstructure:"timeout_seconds"`
+	MaxAttempts         int `mapstructure:"max_attempts"`
+	PollIntervalSeconds int `mapstructure:"poll_interval_seconds"`
+}
+
+// validate rejects settings the dispatcher can't run with: a zero poll
+// interval panics its ticker and a zero timeout leaves requests, and the
+// lease that covers them, unbounded.
+func (c WebhookConfig) validate() error {
+	switch {
+	case c.Workers < 1:
+		return errors.New("workers must be at least 1")
+	case c.TimeoutSeconds < 1:
+		return errors.New("timeout_seconds must be at least 1")
+	case c.MaxAttempts < 1:
+		return errors.New("max_attempts must be at least 1")
+	case c.PollIntervalSeconds < 1:
+		return errors.New("poll_interval_seconds must be at least 1")
+	}
+	return nil
+}
+
+type RateLimitConfig struct {
+	Enabled bool
+	Default RateLimitPolicy
+	// Routes are tried in order; the first whose pattern matches applies
+	Routes []RateLimitPolicy
+}
+
+type RateLimitPolicy struct {
+	// Pattern is "[METHOD ]/path". A trailing slash matches everything below
+	// the path and a {name} segment matches any one segment.
+	Pattern           string
+	RequestsPerMinute int `mapstructure:"requests_per_minute"`
+	Burst             int
 }
 
 func Load() (*Config, error) {
@@ -67,6 +159,7 @@ func Load() (*Config, error) {
 	viper.BindEnv("smtp.username", "SMTP_USERNAME")
 	viper.BindEnv("smtp.password", "SMTP_PASSWORD")
 	viper.BindEnv("smtp.from", "SMTP_FROM")
+	viper.BindEnv("upload.dir", "UPLOAD_DIR")
 
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("failed to read config: %w", err)
@@ -76,6 +169,9 @@ func Load() (*Config, error) {
 	if err := viper.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	if err := cfg.Webhooks.validate(); err != nil {
+		return nil, fmt.Errorf("invalid webhooks config: %w", err)
+	}
 
 	return &cfg, nil
 }
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// AccessTokenPrefix marks personal access tokens so they can be told apart
+// from JWTs in an Authorization header.
+const AccessTokenPrefix = "pat_"
+
+type TokenScope string
+
+const (
+	ScopeRead      TokenScope = "read"
+	ScopeReadWrite TokenScope = "read_write"
+)
+
+// AccessToken is a personal access token for scripts and CI. Only a hash of
+// the token is stored; Prefix is kept so users can recognise it. An empty
+// ProjectIDs means the token works for every project the user belongs to.
+type AccessToken struct {
+	ID         bson.ObjectID   `bson:"_id,omitempty"          json:"id"`
+	UserID     bson.ObjectID   `bson:"user_id"                json:"user_id"`
+	Name       string          `bson:"name"                   json:"name"`
+	Prefix     string          `bson:"prefix"                 json:"prefix"`
+	TokenHash  string          `bson:"token_hash"             json:"-"`
+	Scope      TokenScope      `bson:"scope"                  json:"scope"`
+	ProjectIDs []bson.ObjectID `bson:"project_ids"            json:"project_ids"`
+	ExpiresAt  time.Time       `bson:"expires_at"             json:"expires_at"`
+	LastUsedAt *time.Time      `bson:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+	CreatedAt  time.Time       `bson:"created_at"             json:"created_at"`
+}
+
+// AllowsProject reports whether the token may be used on projectID.
+func (t *AccessToken) AllowsProject(projectID string) bool {
+	if len(t.ProjectIDs) == 0 {
+		return true
+	}
+	for _, id := range t.ProjectIDs {
+		if id.Hex() == projectID {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// AccountExport is everything stored about a user, for data portability
+// requests. Tasks are those the user created or is assigned.
+type AccountExport struct {
+	ExportedAt   time.Time     `json:"exported_at"`
+	User         *User         `json:"user"`
+	Sessions     []Session     `json:"sessions"`
+	AccessTokens []AccessToken `json:"access_tokens"`
+	Projects     []Project     `json:"projects"`
+	Tasks        []Task        `json:"tasks"`
+	Notes        []Note        `json:"notes"`
+	Comments     []Comment     `json:"comments"`
+}
\ No newline at end of file
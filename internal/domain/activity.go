@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type ActivityAction string
+
+const (
+	ActivityTaskCreated       ActivityAction = "task.created"
+	ActivityTaskUpdated       ActivityAction = "task.updated"
+	ActivityTaskDeleted       ActivityAction = "task.deleted"
+	ActivitySubTaskCreated    ActivityAction = "subtask.created"
+	ActivitySubTaskUpdated    ActivityAction = "subtask.updated"
+	ActivitySubTaskDeleted    ActivityAction = "subtask.deleted"
+	ActivityAttachmentAdded   ActivityAction = "attachment.added"
+	ActivityAttachmentRemoved ActivityAction = "attachment.removed"
+)
+
+// FieldChange records one field's value before and after a mutation.
+// Values are rendered as strings; an empty string means "unset".
+type FieldChange struct {
+	Field string `bson:"field" json:"field"`
+	From  string `bson:"from"  json:"from"`
+	To    string `bson:"to"    json:"to"`
+}
+
+// TaskActivity is one entry in a task's change log. TargetID identifies the
+// subtask or attachment the action applied to, if any.
+type TaskActivity struct {
+	ID        bson.ObjectID  `bson:"_id,omitempty"       json:"id"`
+	ProjectID bson.ObjectID  `bson:"project_id"          json:"project_id"`
+	TaskID    bson.ObjectID  `bson:"task_id"             json:"task_id"`
+	TargetID  bson.ObjectID  `bson:"target_id,omitempty" json:"target_id,omitzero"`
+	ActorID   bson.ObjectID  `bson:"actor_id"            json:"actor_id"`
+	Action    ActivityAction `bson:"action"              json:"action"`
+	Changes   []FieldChange  `bson:"changes"             json:"changes"`
+	CreatedAt time.Time      `bson:"created_at"          json:"created_at"`
+}
\ No newline at end of file
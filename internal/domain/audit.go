@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type AuditAction string
+
+const (
+	AuditProjectCreated         AuditAction = "project.created"
+	AuditProjectUpdated         AuditAction = "project.updated"
+	AuditProjectDeleted         AuditAction = "project.deleted"
+	AuditWorkflowUpdated        AuditAction = "project.workflow_updated"
+	AuditOwnershipTransferred   AuditAction = "project.ownership_transferred"
+	AuditProjectRecovered       AuditAction = "project.recovered"
+	AuditMemberAdded            AuditAction = "member.added"
+	AuditMemberRoleChanged      AuditAction = "member.role_changed"
+	AuditMemberRemoved          AuditAction = "member.removed"
+	AuditRoleCreated            AuditAction = "role.created"
+	AuditRoleUpdated            AuditAction = "role.updated"
+	AuditRoleDeleted            AuditAction = "role.deleted"
+	AuditNoteCreated            AuditAction = "note.created"
+	AuditNoteUpdated            AuditAction = "note.updated"
+	AuditNoteDeleted            AuditAction = "note.deleted"
+	AuditInvitationCreated      AuditAction = "invitation.created"
+	AuditInvitationRevoked      AuditAction = "invitation.revoked"
+	AuditInvitationAccepted     AuditAction = "invitation.accepted"
+	AuditJoinLinkCreated        AuditAction = "join_link.created"
+	AuditJoinLinkRevoked        AuditAction = "join_link.revoked"
+	AuditWebhookCreated         AuditAction = "webhook.created"
+	AuditWebhookUpdated         AuditAction = "webhook.updated"
+	AuditWebhookDeleted         AuditAction = "webhook.deleted"
+	AuditLogin                  AuditAction = "auth.login"
+	AuditLoginFailed            AuditAction = "auth.login_failed"
+	AuditLogout                 AuditAction = "auth.logout"
+	AuditPasswordChanged        AuditAction = "auth.password_changed"
+	AuditPasswordResetRequested AuditAction = "auth.password_reset_requested"
+	AuditPasswordReset          AuditAction = "auth.password_reset"
+	AuditEmailChangeRequested   AuditAction = "auth.email_change_requested"
+	AuditEmailChanged           AuditAction = "auth.email_changed"
+	AuditAccountDeleted         AuditAction = "auth.account_deleted"
+	AuditIdentityLinked         AuditAction = "auth.identity_linked"
+	AuditAccountLocked          AuditAction = "auth.account_locked"
+	AuditAccountUnlocked        AuditAction = "auth.account_unlocked"
+	AuditMFAFailed              AuditAction = "auth.mfa_failed"
+	AuditTOTPEnabled            AuditAction = "auth.totp_enabled"
+	AuditTOTPDisabled           AuditAction = "auth.totp_disabled"
+	AuditRecoveryCodeUsed       AuditAction = "auth.recovery_code_used"
+	AuditRecoveryCodesReset     AuditAction = "auth.recovery_codes_regenerated"
+	AuditSessionRevoked         AuditAction = "auth.session_revoked"
+	AuditRefreshTokenReused     AuditAction = "auth.refresh_token_reused"
+	AuditTokenCreated           AuditAction = "auth.token_created"
+	AuditTokenRevoked           AuditAction = "auth.token_revoked"
+)
+
+// AuditEntry is an append-only record of a security- or compliance-relevant
+// action. ProjectID is unset for account-level events such as logins.
+type AuditEntry struct {
+	ID         bson.ObjectID `bson:"_id,omitempty"        json:"id"`
+	ProjectID  bson.ObjectID `bson:"project_id,omitempty" json:"project_id,omitzero"`
+	ActorID    bson.ObjectID `bson:"actor_id"             json:"actor_id"`
+	Action     AuditAction   `bson:"action"               json:"action"`
+	TargetType string        `bson:"target_type"          json:"target_type"`
+	TargetID   string        `bson:"target_id"            json:"target_id"`
+	Changes    []FieldChange `bson:"changes"              json:"changes"`
+	CreatedAt  time.Time     `bson:"created_at"           json:"created_at"`
+}
+
+// AuditFilter selects audit entries. Zero values mean "no constraint".
+// Results are newest first; Cursor is the ID of the last entry of the
+// previous page.
+type AuditFilter struct {
+	ProjectID string
+	ActorID   string
+	Action    AuditAction
+	TargetID  string
+	Since     time.Time
+	Until     time.Time
+	Cursor    string
+	Limit     int
+}
+
+type AuditPage struct {
+	Entries    []AuditEntry `json:"entries"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
\ No newline at end of file
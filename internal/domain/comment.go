@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type Comment struct {
+	ID        bson.ObjectID   `bson:"_id,omitempty"       json:"id"`
+	ProjectID bson.ObjectID   `bson:"project_id"          json:"project_id"`
+	TaskID    bson.ObjectID   `bson:"task_id"             json:"task_id"`
+	ParentID  bson.ObjectID   `bson:"parent_id,omitempty" json:"parent_id,omitzero"`
+	Content   string          `bson:"content"             json:"content"`
+	Mentions  []bson.ObjectID `bson:"mentions"            json:"mentions"`
+	Replies   []Comment       `bson:"-"                   json:"replies,omitempty"`
+	CreatedBy bson.ObjectID   `bson:"created_by"          json:"created_by"`
+	CreatedAt time.Time       `bson:"created_at"          json:"created_at"`
+	UpdatedAt time.Time       `bson:"updated_at"          json:"updated_at"`
+}
\ No newline at end of file
@@ -0,0 +1,20 @@
+package domain
+
+import "context"
+
+type projectContextKey struct{}
+
+// WithProject returns a copy of ctx carrying a project already loaded for
+// the current request, so services needn't load it again.
+func WithProject(ctx context.Context, p *Project) context.Context {
+	return context.WithValue(ctx, projectContextKey{}, p)
+}
+
+// ProjectFromContext returns the project carried by ctx, if it is projectID.
+func ProjectFromContext(ctx context.Context, projectID string) (*Project, bool) {
+	p, ok := ctx.Value(projectContextKey{}).(*Project)
+	if !ok || p.ID.Hex() != projectID {
+		return nil, false
+	}
+	return p, true
+}
\ No newline at end of file
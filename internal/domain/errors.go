@@ -1,14 +1,32 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
-	ErrNotFound         = errors.New("resource not found")
-	ErrConflict         = errors.New("resource already exists")
-	ErrUnauthorized     = errors.New("unauthorized")
-	ErrForbidden        = errors.New("forbidden")
-	ErrInvalidInput     = errors.New("invalid input")
-	ErrTokenExpired     = errors.New("token expired")
-	ErrTokenInvalid     = errors.New("token invalid")
-	ErrEmailNotVerified = errors.New("email not verified")
-)
\ No newline at end of file
+	ErrNotFound          = errors.New("resource not found")
+	ErrConflict          = errors.New("resource already exists")
+	ErrUnauthorized      = errors.New("unauthorized")
+	ErrForbidden         = errors.New("forbidden")
+	ErrInvalidInput      = errors.New("invalid input")
+	ErrTokenExpired      = errors.New("token expired")
+	ErrTokenInvalid      = errors.New("token invalid")
+	ErrEmailNotVerified  = errors.New("email not verified")
+	ErrFileTooLarge      = errors.New("file too large")
+	ErrUnsupportedMedia  = errors.New("unsupported file type")
+	ErrInvalidTransition = errors.New("invalid status transition")
+	ErrAccountLocked     = errors.New("account temporarily locked after too many failed attempts")
+	ErrTooManyAttempts   = errors.New("too many attempts, try again later")
+)
+
+// RetryAfterError tells the client how long to wait before trying again.
+type RetryAfterError struct {
+	Err  error
+	Wait time.Duration
+}
+
+func (e *RetryAfterError) Error() string { return e.Err.Error() }
+
+func (e *RetryAfterError) Unwrap() error { return e.Err }
\ No newline at end of file
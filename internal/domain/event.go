@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type EventType string
+
+const (
+	EventTaskCreated       EventType = "task.created"
+	EventTaskUpdated       EventType = "task.updated"
+	EventTaskDeleted       EventType = "task.deleted"
+	EventSubTaskCreated    EventType = "subtask.created"
+	EventSubTaskUpdated    EventType = "subtask.updated"
+	EventSubTaskDeleted    EventType = "subtask.deleted"
+	EventAttachmentAdded   EventType = "attachment.added"
+	EventAttachmentRemoved EventType = "attachment.removed"
+	EventNoteCreated       EventType = "note.created"
+	EventNoteUpdated       EventType = "note.updated"
+	EventNoteDeleted       EventType = "note.deleted"
+	EventMemberAdded       EventType = "member.added"
+	EventMemberRoleChanged EventType = "member.role_changed"
+	EventMemberRemoved     EventType = "member.removed"
+
+	// EventStreamReset tells a resuming subscriber that some events were lost
+	// and it should reload its state.
+	EventStreamReset EventType = "stream.reset"
+)
+
+// Permission returns the permission a member needs to see events of the
+// type.
+func (t EventType) Permission() Permission {
+	switch t {
+	case EventTaskCreated, EventTaskUpdated, EventTaskStatusChanged, EventTaskDeleted,
+		EventSubTaskCreated, EventSubTaskUpdated, EventSubTaskDeleted,
+		EventAttachmentAdded, EventAttachmentRemoved:
+		return PermTaskView
+	case EventNoteCreated, EventNoteUpdated, EventNoteDeleted:
+		return PermNoteView
+	default:
+		return PermProjectView
+	}
+}
+
+// ProjectEvent announces a change inside a project. TargetID is the task,
+// subtask, attachment, note or user that changed, and Changes lists what was modified. Payload carries the new state: the
+// task for task and subtask events, the attachment for attachment events, the
+// note for note events and the member for member events. It is nil for
+// deletions and removals.
+type ProjectEvent struct {
+	ID        string        `json:"id"`
+	ProjectID bson.ObjectID `json:"project_id"`
+	Type      EventType     `json:"type"`
+	ActorID   bson.ObjectID `json:"actor_id,omitzero"`
+	TargetID  bson.ObjectID `json:"target_id,omitzero"`
+	Changes   []FieldChange `json:"changes,omitempty"`
+	Payload   any           `json:"payload,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+}
\ No newline at end of file
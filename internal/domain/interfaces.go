@@ -1,6 +1,12 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
 
 // --- Repository Interfaces ---
 
@@ -10,80 +16,447 @@ type UserRepository interface {
 	FindByEmail(ctx context.Context, email string) (*User, error)
 	FindByVerificationToken(ctx context.Context, token string) (*User, error)
 	FindByResetToken(ctx context.Context, token string) (*User, error)
+	FindByUnlockToken(ctx context.Context, token string) (*User, error)
+	FindByEmailChangeToken(ctx context.Context, token string) (*User, error)
+	FindByIdentity(ctx context.Context, provider, subject string) (*User, error)
+	FindByIDs(ctx context.Context, ids []string) ([]User, error)
+	// Update saves the user, except for TokenVersion, RecoveryCodes and
+	// TOTPLastStep, which only change through the methods below.
 	Update(ctx context.Context, user *User) error
+	IncrementTokenVersion(ctx context.Context, id string) error
+	// ClaimTOTPStep records step as the last TOTP step used, failing with
+	// ErrNotFound if it isn't newer than the one stored.
+	ClaimTOTPStep(ctx context.Context, id string, step int64) error
+	// UseRecoveryCode removes the hashed recovery code, failing with
+	// ErrNotFound if it was already used.
+	UseRecoveryCode(ctx context.Context, id, hash string) error
+	// SetRecoveryCodes replaces the hashed recovery codes.
+	SetRecoveryCodes(ctx context.Context, id string, hashes []string) error
+	Delete(ctx context.Context, id string) error
 }
 
 type ProjectRepository interface {
 	Create(ctx context.Context, project *Project) error
 	FindByID(ctx context.Context, id string) (*Project, error)
-	FindByUserID(ctx context.Context, userID string) ([]Project, error)
+	FindByUserID(ctx context.Context, userID string, page PageRequest) (*Page[Project], error)
+	// FindWithoutAdmin returns projects that no member can administer.
+	FindWithoutAdmin(ctx context.Context, page PageRequest) (*Page[Project], error)
+	// Update saves the project's fields; members and the owner are left as
+	// stored.
 	Update(ctx context.Context, project *Project) error
+	// SetOwner makes userID the owner, returning ErrConflict unless they
+	// are still an admin member.
+	SetOwner(ctx context.Context, projectID, userID bson.ObjectID) error
+	// AddMember adds member unless the user is already one, in which case
+	// it returns ErrConflict.
+	AddMember(ctx context.Context, projectID bson.ObjectID, member ProjectMember) error
+	// UpdateMemberRole and RemoveMember return ErrNotFound if the user is
+	// no longer a member, and ErrConflict if the write would take away the
+	// project's last admin.
+	UpdateMemberRole(ctx context.Context, projectID, userID bson.ObjectID, role Role) error
+	RemoveMember(ctx context.Context, projectID, userID bson.ObjectID) error
 	Delete(ctx context.Context, id string) error
+	// AnonymizeUser replaces the user's ID with the zero ID as the owner.
+	AnonymizeUser(ctx context.Context, userID string) error
 }
 
 type TaskRepository interface {
 	Create(ctx context.Context, task *Task) error
 	FindByID(ctx context.Context, id string) (*Task, error)
-	FindByProjectID(ctx context.Context, projectID string) ([]Task, error)
+	// FindBySubTaskID returns the task in the project that holds the subtask.
+	FindBySubTaskID(ctx context.Context, projectID, subTaskID string) (*Task, error)
+	FindByProjectID(ctx context.Context, projectID string, filter TaskFilter, page PageRequest) (*Page[Task], error)
+	Search(ctx context.Context, projectIDs []string, text string, limit int) ([]TextMatch[Task], error)
+	CountByStatus(ctx context.Context, projectID string, statuses []TaskStatus) (int64, error)
+	// FindByUserID returns the tasks the user created or is assigned.
+	FindByUserID(ctx context.Context, userID string) ([]Task, error)
+	// Update saves the task's fields; attachments are left as stored.
 	Update(ctx context.Context, task *Task) error
+	// AddAttachment and RemoveAttachment change a single attachment in
+	// place; RemoveAttachment returns ErrNotFound if it is already gone.
+	AddAttachment(ctx context.Context, taskID bson.ObjectID, a Attachment) error
+	RemoveAttachment(ctx context.Context, taskID, attachmentID bson.ObjectID) error
 	Delete(ctx context.Context, id string) error
+	// AnonymizeUser replaces the user's ID with the zero ID wherever it
+	// appears, unassigning their tasks.
+	AnonymizeUser(ctx context.Context, userID string) error
 }
 
 type NoteRepository interface {
 	Create(ctx context.Context, note *Note) error
 	FindByID(ctx context.Context, id string) (*Note, error)
-	FindByProjectID(ctx context.Context, projectID string) ([]Note, error)
+	FindByProjectID(ctx context.Context, projectID string, page PageRequest) (*Page[Note], error)
+	Search(ctx context.Context, projectIDs []string, text string, limit int) ([]TextMatch[Note], error)
+	FindByCreator(ctx context.Context, userID string) ([]Note, error)
 	Update(ctx context.Context, note *Note) error
 	Delete(ctx context.Context, id string) error
+	AnonymizeUser(ctx context.Context, userID string) error
+}
+
+type CommentRepository interface {
+	Create(ctx context.Context, comment *Comment) error
+	FindByID(ctx context.Context, id string) (*Comment, error)
+	FindByTaskID(ctx context.Context, taskID string) ([]Comment, error)
+	FindByCreator(ctx context.Context, userID string) ([]Comment, error)
+	Update(ctx context.Context, comment *Comment) error
+	Delete(ctx context.Context, id string) error
+	DeleteReplies(ctx context.Context, parentID string) error
+	DeleteByTaskID(ctx context.Context, taskID string) error
+	// AnonymizeUser clears the user as author and drops their mentions.
+	AnonymizeUser(ctx context.Context, userID string) error
+}
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *Session) error
+	// FindByTokenHash matches either the current or a previous refresh token.
+	FindByTokenHash(ctx context.Context, hash string) (*Session, error)
+	FindByID(ctx context.Context, id string) (*Session, error)
+	FindByUserID(ctx context.Context, userID string) ([]Session, error)
+	// Rotate replaces the session's refresh token, failing with ErrNotFound
+	// if oldHash is no longer current.
+	Rotate(ctx context.Context, id bson.ObjectID, oldHash, newHash string, client ClientInfo, expiresAt time.Time) error
+	Delete(ctx context.Context, userID, id string) error
+	DeleteByUserID(ctx context.Context, userID string) error
+	// DeleteOthers deletes every session of the user except keepID.
+	DeleteOthers(ctx context.Context, userID, keepID string) error
+}
+
+type AccessTokenRepository interface {
+	Create(ctx context.Context, token *AccessToken) error
+	FindByHash(ctx context.Context, hash string) (*AccessToken, error)
+	FindByUserID(ctx context.Context, userID string) ([]AccessToken, error)
+	// TouchLastUsed sets LastUsedAt to now unless it is already newer than
+	// now minus within, to avoid a write on every request.
+	TouchLastUsed(ctx context.Context, id bson.ObjectID, now time.Time, within time.Duration) error
+	Delete(ctx context.Context, id string) error
+	DeleteByUserID(ctx context.Context, userID string) error
+}
+
+type ActivityRepository interface {
+	Create(ctx context.Context, activity *TaskActivity) error
+	FindByTaskID(ctx context.Context, taskID string) ([]TaskActivity, error)
+	// AnonymizeUser replaces the user's ID with the zero ID as actor and in
+	// recorded changes, such as a task's assignee.
+	AnonymizeUser(ctx context.Context, userID string) error
+}
+
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *Invitation) error
+	FindByID(ctx context.Context, id string) (*Invitation, error)
+	FindByTokenHash(ctx context.Context, hash string) (*Invitation, error)
+	// FindPendingByProjectID and FindPendingByEmail skip expired invitations.
+	FindPendingByProjectID(ctx context.Context, projectID string, now time.Time) ([]Invitation, error)
+	FindPendingByEmail(ctx context.Context, email string, now time.Time) ([]Invitation, error)
+	Update(ctx context.Context, invitation *Invitation) error
+	Delete(ctx context.Context, id string) error
+	// AnonymizeUser replaces the user's ID with the zero ID as inviter and
+	// as the one who accepted.
+	AnonymizeUser(ctx context.Context, userID string) error
+}
+
+type JoinLinkRepository interface {
+	Create(ctx context.Context, link *JoinLink) error
+	FindByID(ctx context.Context, id string) (*JoinLink, error)
+	FindByTokenHash(ctx context.Context, hash string) (*JoinLink, error)
+	FindByProjectID(ctx context.Context, projectID string) ([]JoinLink, error)
+	// ClaimUse counts one use of the link, failing with ErrNotFound if it
+	// is revoked, expired or used up.
+	ClaimUse(ctx context.Context, id bson.ObjectID, now time.Time) error
+	// ReleaseUse gives back a use claimed for a join that then failed.
+	ReleaseUse(ctx context.Context, id bson.ObjectID) error
+	Revoke(ctx context.Context, id bson.ObjectID, now time.Time) error
+	CreateUse(ctx context.Context, use *JoinLinkUse) error
+	FindUses(ctx context.Context, linkID string) ([]JoinLinkUse, error)
+	// AnonymizeUser replaces the user's ID with the zero ID as the creator
+	// of links and in the uses they made.
+	AnonymizeUser(ctx context.Context, userID string) error
+}
+
+// AuditRepository is append-only: entries can never be changed or removed.
+type AuditRepository interface {
+	Create(ctx context.Context, entry *AuditEntry) error
+	Find(ctx context.Context, filter AuditFilter) (*AuditPage, error)
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, hook *Webhook) error
+	FindByID(ctx context.Context, id string) (*Webhook, error)
+	FindByProjectID(ctx context.Context, projectID string) ([]Webhook, error)
+	FindSubscribed(ctx context.Context, projectID string, eventTypes []EventType) ([]Webhook, error)
+	Update(ctx context.Context, hook *Webhook) error
+	Delete(ctx context.Context, id string) error
+	AnonymizeUser(ctx context.Context, userID string) error
+}
+
+// WebhookDeliveryRepository doubles as the persistent delivery queue.
+type WebhookDeliveryRepository interface {
+	Create(ctx context.Context, delivery *WebhookDelivery) error
+	FindByID(ctx context.Context, id string) (*WebhookDelivery, error)
+	FindByWebhookID(ctx context.Context, webhookID string, page PageRequest) (*Page[WebhookDelivery], error)
+	// ClaimDue locks the oldest pending delivery that is due for lease and
+	// returns it, or ErrNotFound if there is none.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*WebhookDelivery, error)
+	Update(ctx context.Context, delivery *WebhookDelivery) error
+	DeleteByWebhookID(ctx context.Context, webhookID string) error
+}
+
+// --- Storage Interfaces ---
+
+// BlobStorage stores opaque file contents under a caller-chosen key.
+type BlobStorage interface {
+	Save(ctx context.Context, key string, content io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// AttemptStore tracks failed attempts for brute-force protection.
+type AttemptStore interface {
+	// Get returns the key's record, or an empty one if it has none.
+	Get(ctx context.Context, key string) (*AttemptRecord, error)
+	// Fail records a failure and returns the updated record. Failures are
+	// forgotten once window passes without another.
+	Fail(ctx context.Context, key string, window time.Duration) (*AttemptRecord, error)
+	// Refund takes back one failure counted by Fail, for an attempt that
+	// turned out not to fail.
+	Refund(ctx context.Context, key string) error
+	// Lock rejects the key until the given time and clears its failures.
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+}
+
+// --- Identity Interfaces ---
+
+// IdentityProvider is an external OpenID Connect provider.
+type IdentityProvider interface {
+	Name() string
+	// AuthCodeURL is where to send the user to sign in at the provider.
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange redeems an authorization code and verifies the ID token.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*ExternalIdentity, error)
+}
+
+// --- Messaging Interfaces ---
+
+// EventBus fans project events out to subscribers. Event IDs are assigned by
+// the bus and increase monotonically.
+type EventBus interface {
+	// Publish is best-effort and never blocks on slow subscribers.
+	Publish(ctx context.Context, event ProjectEvent)
+	// Subscribe streams events for one project until ctx is done. If
+	// lastEventID is set, newer events still held by the bus are replayed
+	// first, or an EventStreamReset is sent if some are gone. The channel is
+	// closed when ctx is done or the subscriber falls too far behind.
+	Subscribe(ctx context.Context, projectID, lastEventID string) (<-chan ProjectEvent, error)
 }
 
 // --- Service Interfaces ---
 
+// TokenVersionCache serves users' current token versions to the auth
+// middleware without a database read on every request.
+type TokenVersionCache interface {
+	// Version returns ErrNotFound if the user no longer exists.
+	Version(ctx context.Context, userID string) (int, error)
+	// Invalidate drops a cached version after it has been bumped.
+	Invalidate(userID string)
+}
+
+// SessionCache tells the auth middleware whether the session an access
+// token was issued for is still signed in, without a database read on
+// every request.
+type SessionCache interface {
+	// Active reports whether the session exists, belongs to userID and
+	// hasn't expired.
+	Active(ctx context.Context, userID, sessionID string) (bool, error)
+	// Invalidate drops a cached session after it has been deleted.
+	Invalidate(sessionID string)
+	// InvalidateUser drops every cached session of the user.
+	InvalidateUser(userID string)
+}
+
 type AuthService interface {
 	Register(ctx context.Context, name, email, password string) error
-	Login(ctx context.Context, email, password string) (accessToken, refreshToken string, err error)
-	Logout(ctx context.Context, userID string) error
+	// Login checks the password. Users with 2FA must then call VerifyMFA.
+	Login(ctx context.Context, email, password string, client ClientInfo) (*LoginResult, error)
+	// StartOIDCLogin returns the provider's sign-in URL and a flow token the
+	// caller must hand back, unchanged, to CompleteOIDCLogin.
+	StartOIDCLogin(ctx context.Context, provider string) (authURL, flowToken string, err error)
+	// StartOIDCLink is StartOIDCLogin for a signed-in user who wants to link
+	// their account at the provider.
+	StartOIDCLink(ctx context.Context, provider, userID string) (authURL, flowToken string, err error)
+	// CompleteOIDCLogin signs in the user the provider vouches for, creating
+	// an account by verified email if there's none. Link flows attach the
+	// provider account to the user who started them first.
+	CompleteOIDCLogin(ctx context.Context, provider, code, state, flowToken string, client ClientInfo) (*LoginResult, error)
+	LoginMethods() LoginMethods
+	// VerifyMFA completes a login with a TOTP or recovery code.
+	VerifyMFA(ctx context.Context, mfaToken, code string, client ClientInfo) (*LoginResult, error)
+	Logout(ctx context.Context, userID, sessionID string) error
 	VerifyEmail(ctx context.Context, token string) error
-	RefreshToken(ctx context.Context, refreshToken string) (accessToken string, err error)
-	ChangePassword(ctx context.Context, userID, oldPassword, newPassword string) error
-	ForgotPassword(ctx context.Context, email string) error
+	// RefreshToken rotates the refresh token. Reusing a rotated token revokes
+	// its session.
+	RefreshToken(ctx context.Context, refreshToken string, client ClientInfo) (accessToken, newRefreshToken string, err error)
+	// ChangePassword signs out every session but sessionID, the one making
+	// the change.
+	ChangePassword(ctx context.Context, userID, sessionID, oldPassword, newPassword string) error
+	ForgotPassword(ctx context.Context, email string, client ClientInfo) error
 	ResetPassword(ctx context.Context, token, newPassword string) error
-	ResendVerificationEmail(ctx context.Context, userID string) error
+	// RequestEmailChange sends a confirmation link to newEmail. The address
+	// only changes once ConfirmEmailChange is called with its token.
+	RequestEmailChange(ctx context.Context, userID, password, newEmail string, client ClientInfo) error
+	ConfirmEmailChange(ctx context.Context, token string) error
+	ResendVerificationEmail(ctx context.Context, userID string, client ClientInfo) error
+	// UnlockAccount lifts a lockout with the token from the lockout email.
+	UnlockAccount(ctx context.Context, token string) error
+	// AdminUnlockAccount lifts a user's lockout; global admins only.
+	AdminUnlockAccount(ctx context.Context, requesterID, userID string) error
 	GetCurrentUser(ctx context.Context, userID string) (*User, error)
+	EnrollTOTP(ctx context.Context, userID string) (*TOTPEnrollment, error)
+	// ConfirmTOTP turns 2FA on and returns the recovery codes, shown once.
+	ConfirmTOTP(ctx context.Context, userID, code string) (recoveryCodes []string, err error)
+	DisableTOTP(ctx context.Context, userID, password, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error)
+	ListSessions(ctx context.Context, userID, currentSessionID string) ([]Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+}
+
+type AccountService interface {
+	ExportData(ctx context.Context, userID string) (*AccountExport, error)
+	// DeleteAccount removes the user and anonymizes what they created. It
+	// needs the password, or a fresh sign-in from sessionID for accounts
+	// without one, plus a two-factor code if 2FA is enabled. It fails while
+	// they are the only admin of a project.
+	DeleteAccount(ctx context.Context, userID, sessionID, password, code string) error
+}
+
+type AccessTokenService interface {
+	CreateToken(ctx context.Context, userID, name string, scope TokenScope, projectIDs []string, expiresAt time.Time) (token *AccessToken, raw string, err error)
+	ListTokens(ctx context.Context, userID string) ([]AccessToken, error)
+	RevokeToken(ctx context.Context, userID, tokenID string) error
+	// Authenticate resolves a raw token, rejecting unknown and expired ones.
+	Authenticate(ctx context.Context, raw string) (*AccessToken, error)
 }
 
 type ProjectService interface {
 	CreateProject(ctx context.Context, userID, name, description string) (*Project, error)
 	GetProject(ctx context.Context, projectID, userID string) (*Project, error)
-	ListProjects(ctx context.Context, userID string) ([]Project, error)
+	ListProjects(ctx context.Context, userID string, page PageRequest) (*Page[Project], error)
 	UpdateProject(ctx context.Context, projectID, userID, name, description string) (*Project, error)
 	DeleteProject(ctx context.Context, projectID, userID string) error
 	AddMember(ctx context.Context, projectID, requesterID, email string, role Role) error
-	ListMembers(ctx context.Context, projectID string) ([]ProjectMember, error)
+	ListMembers(ctx context.Context, projectID, requesterID string) ([]ProjectMember, error)
+	// UpdateMemberRole and RemoveMember refuse to leave a project without an
+	// admin, and to touch members whose role carries permissions the
+	// requester lacks.
 	UpdateMemberRole(ctx context.Context, projectID, requesterID, targetUserID string, role Role) error
 	RemoveMember(ctx context.Context, projectID, requesterID, targetUserID string) error
+	// TransferOwnership makes a member the project's owner, promoting them
+	// to admin if need be. The previous owner keeps their role.
+	TransferOwnership(ctx context.Context, projectID, requesterID, newOwnerID string) (*Project, error)
+	// ListOrphanedProjects and RecoverProject are for global admins, to
+	// hand projects left without an admin to a new one.
+	ListOrphanedProjects(ctx context.Context, requesterID string, page PageRequest) (*Page[Project], error)
+	RecoverProject(ctx context.Context, projectID, requesterID, newAdminID string) (*Project, error)
+	GetWorkflow(ctx context.Context, projectID, userID string) (*Workflow, error)
+	UpdateWorkflow(ctx context.Context, projectID, userID string, workflow Workflow) (*Workflow, error)
+	// ListRoles returns the built-in roles followed by the project's own.
+	ListRoles(ctx context.Context, projectID, requesterID string) ([]ProjectRole, error)
+	// SaveRole creates or replaces a custom role. Built-in roles can't be
+	// changed, and nobody can put a permission they lack into a role.
+	SaveRole(ctx context.Context, projectID, requesterID string, name Role, permissions []Permission) (*ProjectRole, error)
+	// DeleteRole refuses while any member still holds the role.
+	DeleteRole(ctx context.Context, projectID, requesterID string, name Role) error
+}
+
+type InvitationService interface {
+	// Invite emails an invitation to join the project with role. The
+	// invitee doesn't need an account yet.
+	Invite(ctx context.Context, projectID, requesterID, email string, role Role) (*Invitation, error)
+	ListInvitations(ctx context.Context, projectID, requesterID string) ([]Invitation, error)
+	RevokeInvitation(ctx context.Context, projectID, invitationID, requesterID string) error
+	// GetInvitation describes a pending invitation to whoever holds its token.
+	GetInvitation(ctx context.Context, token string) (*InvitationPreview, error)
+	// AcceptInvitation adds the user to the project. The user's email must
+	// be the one the invitation was sent to.
+	AcceptInvitation(ctx context.Context, token, userID string) (*Project, error)
+	// AcceptPending accepts every pending invitation sent to the user's
+	// email. It runs whenever the user proves they own that email.
+	AcceptPending(ctx context.Context, user *User) error
+}
+
+type JoinLinkService interface {
+	// CreateJoinLink returns the link and its raw token, which is shown
+	// only once. maxUses of zero and a nil expiresAt mean no limit.
+	CreateJoinLink(ctx context.Context, projectID, requesterID string, role Role, maxUses int, expiresAt *time.Time) (link *JoinLink, token string, err error)
+	ListJoinLinks(ctx context.Context, projectID, requesterID string) ([]JoinLink, error)
+	RevokeJoinLink(ctx context.Context, projectID, linkID, requesterID string) error
+	ListJoinLinkUses(ctx context.Context, projectID, linkID, requesterID string) ([]JoinLinkUse, error)
+	// Join adds the user to the link's project with the link's role.
+	Join(ctx context.Context, token, userID string) (*Project, error)
 }
 
 type TaskService interface {
 	CreateTask(ctx context.Context, projectID, requesterID, title, description, assigneeID string) (*Task, error)
-	GetTask(ctx context.Context, projectID, taskID string) (*Task, error)
-	ListTasks(ctx context.Context, projectID string) ([]Task, error)
-	UpdateTask(ctx context.Context, taskID, requesterID string, updates map[string]any) (*Task, error)
-	DeleteTask(ctx context.Context, taskID, requesterID string) error
-	CreateSubTask(ctx context.Context, taskID, requesterID, title string) (*Task, error)
-	UpdateSubTask(ctx context.Context, taskID, subTaskID, requesterID string, isCompleted bool) (*Task, error)
-	DeleteSubTask(ctx context.Context, taskID, subTaskID, requesterID string) error
+	GetTask(ctx context.Context, projectID, taskID, requesterID string) (*Task, error)
+	ListTasks(ctx context.Context, projectID, requesterID string, query TaskQuery, page PageRequest) (*Page[Task], error)
+	UpdateTask(ctx context.Context, projectID, taskID, requesterID string, updates map[string]any) (*Task, error)
+	DeleteTask(ctx context.Context, projectID, taskID, requesterID string) error
+	CreateSubTask(ctx context.Context, projectID, taskID, requesterID, title string) (*Task, error)
+	UpdateSubTask(ctx context.Context, projectID, subTaskID, requesterID string, updates map[string]any) (*Task, error)
+	DeleteSubTask(ctx context.Context, projectID, subTaskID, requesterID string) error
+	ListActivity(ctx context.Context, projectID, taskID, requesterID string) ([]TaskActivity, error)
 }
 
 type NoteService interface {
 	CreateNote(ctx context.Context, projectID, requesterID, title, content string) (*Note, error)
-	GetNote(ctx context.Context, projectID, noteID string) (*Note, error)
-	ListNotes(ctx context.Context, projectID string) ([]Note, error)
-	UpdateNote(ctx context.Context, noteID, requesterID, title, content string) (*Note, error)
-	DeleteNote(ctx context.Context, noteID, requesterID string) error
+	GetNote(ctx context.Context, projectID, noteID, requesterID string) (*Note, error)
+	ListNotes(ctx context.Context, projectID, requesterID string, page PageRequest) (*Page[Note], error)
+	UpdateNote(ctx context.Context, projectID, noteID, requesterID, title, content string) (*Note, error)
+	DeleteNote(ctx context.Context, projectID, noteID, requesterID string) error
+}
+
+type CommentService interface {
+	CreateComment(ctx context.Context, projectID, taskID, requesterID, parentID, content string) (*Comment, error)
+	ListComments(ctx context.Context, projectID, taskID, requesterID string) ([]Comment, error)
+	UpdateComment(ctx context.Context, projectID, taskID, commentID, requesterID, content string) (*Comment, error)
+	DeleteComment(ctx context.Context, projectID, taskID, commentID, requesterID string) error
+}
+
+type AttachmentService interface {
+	UploadAttachment(ctx context.Context, projectID, taskID, requesterID, filename string, content io.Reader) (*Attachment, error)
+	DownloadAttachment(ctx context.Context, projectID, taskID, attachmentID, requesterID string) (*Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, projectID, taskID, attachmentID, requesterID string) error
+}
+
+type AuditService interface {
+	ListProjectAudit(ctx context.Context, projectID, requesterID string, filter AuditFilter) (*AuditPage, error)
+	ListAudit(ctx context.Context, requesterID string, filter AuditFilter) (*AuditPage, error)
+}
+
+type EventService interface {
+	Subscribe(ctx context.Context, projectID, userID, lastEventID string) (<-chan ProjectEvent, error)
+}
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, projectID, requesterID, url string, events []EventType) (hook *Webhook, secret string, err error)
+	ListWebhooks(ctx context.Context, projectID, requesterID string) ([]Webhook, error)
+	GetWebhook(ctx context.Context, projectID, webhookID, requesterID string) (*Webhook, error)
+	UpdateWebhook(ctx context.Context, projectID, webhookID, requesterID string, update WebhookUpdate) (*Webhook, error)
+	DeleteWebhook(ctx context.Context, projectID, webhookID, requesterID string) error
+	ListDeliveries(ctx context.Context, projectID, webhookID, requesterID string, page PageRequest) (*Page[WebhookDelivery], error)
+	Redeliver(ctx context.Context, projectID, webhookID, deliveryID, requesterID string) (*WebhookDelivery, error)
+	// Enqueue queues a delivery of event to every webhook subscribed to it.
+	Enqueue(ctx context.Context, event ProjectEvent) error
+}
+
+type SearchService interface {
+	Search(ctx context.Context, userID, query string, limit int) ([]SearchHit, error)
 }
 
 type EmailService interface {
 	SendVerificationEmail(to, token string) error
 	SendPasswordResetEmail(to, token string) error
+	SendAccountLockedEmail(to, token string) error
+	SendEmailChangeEmail(to, token string) error
+	SendEmailChangeNotice(to, newEmail string) error
+	SendInvitationEmail(to, projectName, token string) error
 }
\ No newline at end of file
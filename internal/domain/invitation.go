@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationRevoked  InvitationStatus = "revoked"
+)
+
+// Invitation asks someone, who may not have an account yet, to join a
+// project. Email is stored lower-cased. Only a hash of the emailed token is
+// kept. The invitee becomes a member once they accept.
+type Invitation struct {
+	ID         bson.ObjectID    `bson:"_id,omitempty"         json:"id"`
+	ProjectID  bson.ObjectID    `bson:"project_id"            json:"project_id"`
+	Email      string           `bson:"email"                 json:"email"`
+	Role       Role             `bson:"role"                  json:"role"`
+	TokenHash  string           `bson:"token_hash"            json:"-"`
+	Status     InvitationStatus `bson:"status"                json:"status"`
+	InvitedBy  bson.ObjectID    `bson:"invited_by"            json:"invited_by"`
+	AcceptedBy bson.ObjectID    `bson:"accepted_by,omitempty" json:"accepted_by,omitzero"`
+	ExpiresAt  time.Time        `bson:"expires_at"            json:"expires_at"`
+	AcceptedAt *time.Time       `bson:"accepted_at,omitempty" json:"accepted_at,omitempty"`
+	CreatedAt  time.Time        `bson:"created_at"            json:"created_at"`
+}
+
+// Expired reports whether a pending invitation can no longer be accepted.
+func (i *Invitation) Expired(now time.Time) bool {
+	return !now.Before(i.ExpiresAt)
+}
+
+// InvitationPreview is what the holder of an invitation link may see before
+// accepting it.
+type InvitationPreview struct {
+	ProjectID   bson.ObjectID `json:"project_id"`
+	ProjectName string        `json:"project_name"`
+	Email       string        `json:"email"`
+	Role        Role          `json:"role"`
+	ExpiresAt   time.Time     `json:"expires_at"`
+}
\ No newline at end of file
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// JoinLink lets any signed-in user who has the link join a project with
+// Role. Only a hash of the token is stored; Prefix is kept so admins can
+// recognise it. MaxUses of zero and a nil ExpiresAt mean no limit.
+type JoinLink struct {
+	ID        bson.ObjectID `bson:"_id,omitempty"        json:"id"`
+	ProjectID bson.ObjectID `bson:"project_id"           json:"project_id"`
+	Role      Role          `bson:"role"                 json:"role"`
+	Prefix    string        `bson:"prefix"               json:"prefix"`
+	TokenHash string        `bson:"token_hash"           json:"-"`
+	MaxUses   int           `bson:"max_uses"             json:"max_uses"`
+	Uses      int           `bson:"uses"                 json:"uses"`
+	ExpiresAt *time.Time    `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	RevokedAt *time.Time    `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	CreatedBy bson.ObjectID `bson:"created_by"           json:"created_by"`
+	CreatedAt time.Time     `bson:"created_at"           json:"created_at"`
+}
+
+// Usable reports whether the link can still be used to join.
+func (l *JoinLink) Usable(now time.Time) bool {
+	return l.RevokedAt == nil && (l.ExpiresAt == nil || now.Before(*l.ExpiresAt)) && (l.MaxUses == 0 || l.Uses < l.MaxUses)
+}
+
+// JoinLinkUse records a user joining a project through a link.
+type JoinLinkUse struct {
+	ID        bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	LinkID    bson.ObjectID `bson:"link_id"       json:"link_id"`
+	ProjectID bson.ObjectID `bson:"project_id"    json:"project_id"`
+	UserID    bson.ObjectID `bson:"user_id"       json:"user_id"`
+	CreatedAt time.Time     `bson:"created_at"    json:"created_at"`
+}
\ No newline at end of file
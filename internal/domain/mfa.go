@@ -0,0 +1,24 @@
+package domain
+
+// LoginResult is the outcome of a successful password check. Users with
+// two-factor authentication get a short-lived MFA token to exchange, along
+// with a code, for the token pair.
+type LoginResult struct {
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	MFARequired  bool   `json:"mfa_required,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`
+}
+
+// LoginMethods lists the ways users can sign in.
+type LoginMethods struct {
+	Password      bool     `json:"password"`
+	OIDCProviders []string `json:"oidc_providers"`
+}
+
+// TOTPEnrollment is a pending authenticator secret, shown once so the user
+// can add it to their app.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
\ No newline at end of file
@@ -0,0 +1,14 @@
+package domain
+
+// PageRequest asks for one page of a listing. Sort names a field, prefixed
+// with "-" for descending order; Cursor is the NextCursor of the previous page.
+type PageRequest struct {
+	Cursor string
+	Limit  int
+	Sort   string
+}
+
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
\ No newline at end of file
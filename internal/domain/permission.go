@@ -0,0 +1,95 @@
+package domain
+
+import "slices"
+
+// Permission is something a project role allows its members to do.
+type Permission string
+
+const (
+	PermProjectView   Permission = "project.view"   // project, members, roles, workflow, event stream
+	PermProjectUpdate Permission = "project.update" // name, description, workflow
+	PermProjectDelete Permission = "project.delete"
+	PermMemberManage  Permission = "member.manage" // members, invitations, join links, ownership
+	PermRoleManage    Permission = "role.manage"   // custom roles
+	PermAuditView     Permission = "audit.view"
+	PermWebhookManage Permission = "webhook.manage"
+
+	PermTaskView         Permission = "task.view" // tasks, subtasks, activity, comments, attachments
+	PermTaskCreate       Permission = "task.create"
+	PermTaskUpdateAny    Permission = "task.update.any"    // every field of any task or subtask
+	PermTaskUpdateStatus Permission = "task.update.status" // task status and subtask completion only
+	PermTaskDelete       Permission = "task.delete"
+
+	PermNoteView  Permission = "note.view"
+	PermNoteWrite Permission = "note.write"
+
+	PermCommentWrite        Permission = "comment.write" // post, and edit or delete one's own
+	PermCommentDeleteAny    Permission = "comment.delete.any"
+	PermAttachmentWrite     Permission = "attachment.write" // upload, and delete one's own
+	PermAttachmentDeleteAny Permission = "attachment.delete.any"
+)
+
+// Permissions lists every permission, in a stable order.
+var Permissions = []Permission{
+	PermProjectView, PermProjectUpdate, PermProjectDelete, PermMemberManage, PermRoleManage, PermAuditView, PermWebhookManage,
+	PermTaskView, PermTaskCreate, PermTaskUpdateAny, PermTaskUpdateStatus, PermTaskDelete,
+	PermNoteView, PermNoteWrite,
+	PermCommentWrite, PermCommentDeleteAny, PermAttachmentWrite, PermAttachmentDeleteAny,
+}
+
+// BuiltinRoles are the roles every project has. Their permissions can't be
+// changed.
+var BuiltinRoles = []ProjectRole{
+	{Name: RoleAdmin, Permissions: Permissions, BuiltIn: true},
+	{Name: RoleProjectAdmin, BuiltIn: true, Permissions: []Permission{
+		PermProjectView, PermWebhookManage,
+		PermTaskView, PermTaskCreate, PermTaskUpdateAny, PermTaskUpdateStatus, PermTaskDelete,
+		PermNoteView,
+		PermCommentWrite, PermCommentDeleteAny, PermAttachmentWrite, PermAttachmentDeleteAny,
+	}},
+	{Name: RoleMember, BuiltIn: true, Permissions: []Permission{
+		PermProjectView,
+		PermTaskView, PermTaskUpdateStatus,
+		PermNoteView,
+		PermCommentWrite, PermAttachmentWrite,
+	}},
+}
+
+// ProjectRole names a set of permissions. Projects can define their own
+// roles next to the built-in ones.
+type ProjectRole struct {
+	Name        Role         `bson:"name"        json:"name"`
+	Permissions []Permission `bson:"permissions" json:"permissions"`
+	BuiltIn     bool         `bson:"-"           json:"built_in"`
+}
+
+// FindRole looks up a built-in or custom role by name.
+func (p *Project) FindRole(name Role) (*ProjectRole, bool) {
+	for _, roles := range [][]ProjectRole{BuiltinRoles, p.Roles} {
+		if i := slices.IndexFunc(roles, func(r ProjectRole) bool { return r.Name == name }); i >= 0 {
+			return &roles[i], true
+		}
+	}
+	return nil, false
+}
+
+// MemberRole returns userID's role, and whether they are a member at all.
+func (p *Project) MemberRole(userID string) (Role, bool) {
+	for _, m := range p.Members {
+		if m.UserID.Hex() == userID {
+			return m.Role, true
+		}
+	}
+	return "", false
+}
+
+// Can reports whether userID's role grants perm. Non-members can do nothing,
+// and neither can members whose custom role no longer exists.
+func (p *Project) Can(userID string, perm Permission) bool {
+	name, ok := p.MemberRole(userID)
+	if !ok {
+		return false
+	}
+	role, ok := p.FindRole(name)
+	return ok && slices.Contains(role.Permissions, perm)
+}
\ No newline at end of file
@@ -11,12 +11,15 @@ type ProjectMember struct {
 	Role   Role               `bson:"role"    json:"role"`
 }
 
+// A project's Roles are its custom roles; every project also has BuiltinRoles.
 type Project struct {
-	ID          bson.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name        string             `bson:"name"          json:"name"`
-	Description string             `bson:"description"   json:"description"`
-	Members     []ProjectMember    `bson:"members"       json:"members"`
-	CreatedBy   bson.ObjectID `bson:"created_by"    json:"created_by"`
-	CreatedAt   time.Time          `bson:"created_at"    json:"created_at"`
-	UpdatedAt   time.Time          `bson:"updated_at"    json:"updated_at"`
+	ID          bson.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Name        string          `bson:"name"          json:"name"`
+	Description string          `bson:"description"   json:"description"`
+	Members     []ProjectMember `bson:"members"       json:"members"`
+	Workflow    Workflow        `bson:"workflow"      json:"workflow"`
+	Roles       []ProjectRole   `bson:"roles"         json:"roles"`
+	CreatedBy   bson.ObjectID   `bson:"created_by"    json:"created_by"`
+	CreatedAt   time.Time       `bson:"created_at"    json:"created_at"`
+	UpdatedAt   time.Time       `bson:"updated_at"    json:"updated_at"`
 }
\ No newline at end of file
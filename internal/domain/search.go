@@ -0,0 +1,29 @@
+package domain
+
+import "go.mongodb.org/mongo-driver/v2/bson"
+
+type SearchHitType string
+
+const (
+	SearchHitTask    SearchHitType = "task"
+	SearchHitSubTask SearchHitType = "subtask"
+	SearchHitNote    SearchHitType = "note"
+)
+
+// SearchHit is one search result. TaskID is set for subtasks only. Snippet
+// is HTML-escaped text with matched words wrapped in <mark>.
+type SearchHit struct {
+	Type      SearchHitType `json:"type"`
+	ID        bson.ObjectID `json:"id"`
+	ProjectID bson.ObjectID `json:"project_id"`
+	TaskID    bson.ObjectID `json:"task_id,omitzero"`
+	Title     string        `json:"title"`
+	Snippet   string        `json:"snippet"`
+	Score     float64       `json:"score"`
+}
+
+// TextMatch is a document returned by a full-text query with its relevance.
+type TextMatch[T any] struct {
+	Item  T
+	Score float64
+}
\ No newline at end of file
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ClientInfo describes the device a request came from.
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
+// Session is one signed-in device. Its refresh token is rotated on every
+// use; the hashes of earlier tokens are kept so that replaying one can be
+// detected and the session revoked.
+type Session struct {
+	ID             bson.ObjectID `bson:"_id,omitempty"   json:"id"`
+	UserID         bson.ObjectID `bson:"user_id"         json:"user_id"`
+	TokenHash      string        `bson:"token_hash"      json:"-"`
+	PreviousHashes []string      `bson:"previous_hashes" json:"-"`
+	UserAgent      string        `bson:"user_agent"      json:"user_agent"`
+	IP             string        `bson:"ip"              json:"ip"`
+	CreatedAt      time.Time     `bson:"created_at"      json:"created_at"`
+	LastSeenAt     time.Time     `bson:"last_seen_at"    json:"last_seen_at"`
+	ExpiresAt      time.Time     `bson:"expires_at"      json:"expires_at"`
+	// Current marks the session the request was made from
+	Current bool `bson:"-" json:"current"`
+}
\ No newline at end of file
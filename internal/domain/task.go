@@ -15,28 +15,64 @@ const (
 )
 
 type Attachment struct {
-	URL      string `bson:"url"       json:"url"`
-	MimeType string `bson:"mime_type" json:"mime_type"`
-	Size     int64  `bson:"size"      json:"size"`
+	ID         bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name       string        `bson:"name"          json:"name"`
+	URL        string        `bson:"url"           json:"url"`
+	MimeType   string        `bson:"mime_type"     json:"mime_type"`
+	Size       int64         `bson:"size"          json:"size"`
+	StorageKey string        `bson:"storage_key"   json:"-"`
+	UploadedBy bson.ObjectID `bson:"uploaded_by"   json:"uploaded_by"`
+	UploadedAt time.Time     `bson:"uploaded_at"   json:"uploaded_at"`
 }
 
 type SubTask struct {
-	ID          bson.ObjectID `bson:"_id,omitempty" json:"id"`
-	Title       string             `bson:"title"         json:"title"`
-	IsCompleted bool               `bson:"is_completed"  json:"is_completed"`
-	CreatedAt   time.Time          `bson:"created_at"    json:"created_at"`
+	ID          bson.ObjectID `bson:"_id,omitempty"        json:"id"`
+	Title       string        `bson:"title"                json:"title"`
+	IsCompleted bool          `bson:"is_completed"         json:"is_completed"`
+	StartDate   *time.Time    `bson:"start_date,omitempty" json:"start_date,omitempty"`
+	DueDate     *time.Time    `bson:"due_date,omitempty"   json:"due_date,omitempty"`
+	CreatedAt   time.Time     `bson:"created_at"           json:"created_at"`
 }
 
 type Task struct {
-	ID          bson.ObjectID `bson:"_id,omitempty" json:"id"`
-	ProjectID   bson.ObjectID `bson:"project_id"    json:"project_id"`
-	Title       string             `bson:"title"         json:"title"`
-	Description string             `bson:"description"   json:"description"`
-	Status      TaskStatus         `bson:"status"        json:"status"`
-	AssignedTo  bson.ObjectID `bson:"assigned_to"   json:"assigned_to"`
-	Attachments []Attachment       `bson:"attachments"   json:"attachments"`
-	SubTasks    []SubTask          `bson:"subtasks"      json:"subtasks"`
-	CreatedBy   bson.ObjectID `bson:"created_by"    json:"created_by"`
-	CreatedAt   time.Time          `bson:"created_at"    json:"created_at"`
-	UpdatedAt   time.Time          `bson:"updated_at"    json:"updated_at"`
+	ID          bson.ObjectID `bson:"_id,omitempty"        json:"id"`
+	ProjectID   bson.ObjectID `bson:"project_id"           json:"project_id"`
+	Title       string        `bson:"title"                json:"title"`
+	Description string        `bson:"description"          json:"description"`
+	Status      TaskStatus    `bson:"status"               json:"status"`
+	AssignedTo  bson.ObjectID `bson:"assigned_to"          json:"assigned_to"`
+	Attachments []Attachment  `bson:"attachments"          json:"attachments"`
+	SubTasks    []SubTask     `bson:"subtasks"             json:"subtasks"`
+	StartDate   *time.Time    `bson:"start_date,omitempty" json:"start_date,omitempty"`
+	DueDate     *time.Time    `bson:"due_date,omitempty"   json:"due_date,omitempty"`
+	CreatedBy   bson.ObjectID `bson:"created_by"           json:"created_by"`
+	CreatedAt   time.Time     `bson:"created_at"           json:"created_at"`
+	UpdatedAt   time.Time     `bson:"updated_at"           json:"updated_at"`
+}
+
+type DueWindow string
+
+const (
+	DueOverdue  DueWindow = "overdue"
+	DueThisWeek DueWindow = "this_week"
+)
+
+// TaskQuery is what callers ask for when listing tasks. DueBefore is exclusive.
+type TaskQuery struct {
+	Status     TaskStatus
+	AssignedTo string
+	CreatedBy  string
+	Due        DueWindow
+	DueFrom    time.Time
+	DueBefore  time.Time
+}
+
+// TaskFilter narrows a task listing. Zero values mean "no constraint".
+type TaskFilter struct {
+	Status          TaskStatus
+	AssignedTo      string
+	CreatedBy       string
+	DueFrom         time.Time
+	DueBefore       time.Time
+	ExcludeStatuses []TaskStatus
 }
\ No newline at end of file
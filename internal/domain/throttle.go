@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// AttemptRecord counts recent failures for one throttling key, such as an
+// account or a client IP. A record is forgotten once ExpiresAt passes.
+type AttemptRecord struct {
+	Key         string    `bson:"_id"             json:"key"`
+	Failures    int       `bson:"failures"        json:"failures"`
+	LastFailure time.Time `bson:"last_failure_at" json:"last_failure_at"`
+	LockedUntil time.Time `bson:"locked_until"    json:"locked_until"`
+	ExpiresAt   time.Time `bson:"expires_at"      json:"expires_at"`
+}
+
+// Locked reports whether the key is locked out at now.
+func (r *AttemptRecord) Locked(now time.Time) bool {
+	return now.Before(r.LockedUntil)
+}
\ No newline at end of file
@@ -24,7 +24,33 @@ type User struct {
 	VerificationToken string             `bson:"verification_token"   json:"-"`
 	ResetToken        string             `bson:"reset_token"          json:"-"`
 	ResetTokenExpiry  time.Time          `bson:"reset_token_expiry"   json:"-"`
-	RefreshToken      string             `bson:"refresh_token"        json:"-"`
+	UnlockToken       string             `bson:"unlock_token"         json:"-"`
+	UnlockTokenExpiry time.Time          `bson:"unlock_token_expiry"  json:"-"`
+	PendingEmail      string             `bson:"pending_email"        json:"pending_email,omitempty"`
+	EmailChangeToken  string             `bson:"email_change_token"   json:"-"`
+	EmailChangeExpiry time.Time          `bson:"email_change_expiry"  json:"-"`
+	TokenVersion      int                `bson:"token_version"        json:"-"`
+	TOTPEnabled       bool               `bson:"totp_enabled"         json:"totp_enabled"`
+	TOTPSecret        string             `bson:"totp_secret"          json:"-"`
+	TOTPLastStep      int64              `bson:"totp_last_step"       json:"-"`
+	RecoveryCodes     []string           `bson:"recovery_codes"       json:"-"`
+	Identities        []IdentityLink     `bson:"identities,omitempty" json:"identities,omitempty"`
 	CreatedAt         time.Time          `bson:"created_at"           json:"created_at"`
 	UpdatedAt         time.Time          `bson:"updated_at"           json:"updated_at"`
+}
+
+// IdentityLink ties a user to an account at an external identity provider.
+type IdentityLink struct {
+	Provider string    `bson:"provider"  json:"provider"`
+	Subject  string    `bson:"subject"   json:"-"`
+	LinkedAt time.Time `bson:"linked_at" json:"linked_at"`
+}
+
+// ExternalIdentity is a user as vouched for by an identity provider.
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
 }
\ No newline at end of file
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// EventTaskStatusChanged is only delivered to webhooks. It accompanies a
+// task.updated event whose changes include the status.
+const EventTaskStatusChanged EventType = "task.status_changed"
+
+// WebhookEventTypes lists the event types a webhook can subscribe to.
+var WebhookEventTypes = []EventType{
+	EventTaskCreated, EventTaskUpdated, EventTaskStatusChanged, EventTaskDeleted,
+	EventSubTaskCreated, EventSubTaskUpdated, EventSubTaskDeleted,
+	EventAttachmentAdded, EventAttachmentRemoved,
+	EventNoteCreated, EventNoteUpdated, EventNoteDeleted,
+	EventMemberAdded, EventMemberRoleChanged, EventMemberRemoved,
+}
+
+// Webhook posts a project's events to an external URL. Secret signs each
+// delivery and is only returned when the webhook is created.
+type Webhook struct {
+	ID        bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	ProjectID bson.ObjectID `bson:"project_id"    json:"project_id"`
+	URL       string        `bson:"url"           json:"url"`
+	Events    []EventType   `bson:"events"        json:"events"`
+	Secret    string        `bson:"secret"        json:"-"`
+	Active    bool          `bson:"active"        json:"active"`
+	CreatedBy bson.ObjectID `bson:"created_by"    json:"created_by"`
+	CreatedAt time.Time     `bson:"created_at"    json:"created_at"`
+	UpdatedAt time.Time     `bson:"updated_at"    json:"updated_at"`
+}
+
+// WebhookUpdate holds the fields to change on a webhook; nil means unchanged.
+type WebhookUpdate struct {
+	URL    *string
+	Events []EventType
+	Active *bool
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// WebhookDelivery is one event queued for, or sent to, a webhook. Pending
+// deliveries are retried with backoff until they succeed or run out of
+// attempts.
+type WebhookDelivery struct {
+	ID             bson.ObjectID   `bson:"_id,omitempty"              json:"id"`
+	WebhookID      bson.ObjectID   `bson:"webhook_id"                 json:"webhook_id"`
+	ProjectID      bson.ObjectID   `bson:"project_id"                 json:"project_id"`
+	EventID        string          `bson:"event_id"                   json:"event_id"`
+	EventType      EventType       `bson:"event_type"                 json:"event_type"`
+	Payload        json.RawMessage `bson:"payload"                    json:"payload"`
+	Status         DeliveryStatus  `bson:"status"                     json:"status"`
+	Attempts       int             `bson:"attempts"                   json:"attempts"`
+	NextAttemptAt  time.Time       `bson:"next_attempt_at"            json:"next_attempt_at"`
+	LockedUntil    time.Time       `bson:"locked_until"               json:"-"`
+	LastAttemptAt  *time.Time      `bson:"last_attempt_at,omitempty"  json:"last_attempt_at,omitempty"`
+	LastStatusCode int             `bson:"last_status_code,omitempty" json:"last_status_code,omitempty"`
+	LastError      string          `bson:"last_error,omitempty"       json:"last_error,omitempty"`
+	RedeliveryOf   bson.ObjectID   `bson:"redelivery_of,omitempty"    json:"redelivery_of,omitzero"`
+	CreatedAt      time.Time       `bson:"created_at"                 json:"created_at"`
+}
\ No newline at end of file
@@ -0,0 +1,24 @@
+package domain
+
+// Workflow is a project's ordered set of task statuses and the moves allowed
+// between them. The first status is where new tasks start; Closed statuses
+// count as finished (e.g. for overdue detection).
+type Workflow struct {
+	Statuses    []TaskStatus                `bson:"statuses"    json:"statuses"`
+	Transitions map[TaskStatus][]TaskStatus `bson:"transitions" json:"transitions"`
+	Closed      []TaskStatus                `bson:"closed"      json:"closed"`
+}
+
+// DefaultWorkflow is the original todo → in_progress → done flow, with every
+// move between the three statuses allowed.
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		Statuses: []TaskStatus{StatusTodo, StatusInProgress, StatusDone},
+		Transitions: map[TaskStatus][]TaskStatus{
+			StatusTodo:       {StatusInProgress, StatusDone},
+			StatusInProgress: {StatusTodo, StatusDone},
+			StatusDone:       {StatusTodo, StatusInProgress},
+		},
+		Closed: []TaskStatus{StatusDone},
+	}
+}
\ No newline at end of file
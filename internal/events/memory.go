@@ -0,0 +1,131 @@
+package events
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// subscriberBuffer is how many undelivered events a subscriber may have
+// queued before it is dropped.
+const subscriberBuffer = 64
+
+type subscriber struct {
+	ch chan domain.ProjectEvent
+}
+
+type memoryBus struct {
+	mu          sync.Mutex
+	seq         uint64
+	historySize int
+	history     map[string][]domain.ProjectEvent
+	// evicted is the ID of the newest event dropped from each project's history
+	evicted map[string]uint64
+	subs    map[string]map[*subscriber]struct{}
+}
+
+// NewMemoryBus returns an EventBus that lives in this process and keeps the
+// last historySize events of each project for replay.
+func NewMemoryBus(historySize int) domain.EventBus {
+	return &memoryBus{
+		historySize: historySize,
+		history:     make(map[string][]domain.ProjectEvent),
+		evicted:     make(map[string]uint64),
+		subs:        make(map[string]map[*subscriber]struct{}),
+	}
+}
+
+func (b *memoryBus) Publish(ctx context.Context, event domain.ProjectEvent) {
+	projectID := event.ProjectID.Hex()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	event.ID = strconv.FormatUint(b.seq, 10)
+
+	history := append(b.history[projectID], event)
+	if len(history) > b.historySize {
+		dropped := history[len(history)-b.historySize-1]
+		b.evicted[projectID], _ = strconv.ParseUint(dropped.ID, 10, 64)
+		history = history[len(history)-b.historySize:]
+	}
+	b.history[projectID] = history
+
+	for sub := range b.subs[projectID] {
+		select {
+		case sub.ch <- event:
+		default:
+			// Too far behind; the client will reconnect with Last-Event-ID
+			b.remove(projectID, sub)
+		}
+	}
+}
+
+func (b *memoryBus) Subscribe(ctx context.Context, projectID, lastEventID string) (<-chan domain.ProjectEvent, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	replay := b.replay(projectID, lastEventID)
+	sub := &subscriber{ch: make(chan domain.ProjectEvent, len(replay)+subscriberBuffer)}
+	for _, e := range replay {
+		sub.ch <- e
+	}
+
+	if b.subs[projectID] == nil {
+		b.subs[projectID] = make(map[*subscriber]struct{})
+	}
+	b.subs[projectID][sub] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		b.remove(projectID, sub)
+		b.mu.Unlock()
+	}()
+	return sub.ch, nil
+}
+
+// replay returns the events a subscriber resuming after lastEventID missed.
+// The caller must hold b.mu.
+func (b *memoryBus) replay(projectID, lastEventID string) []domain.ProjectEvent {
+	if lastEventID == "" {
+		return nil
+	}
+	// The reset carries the current ID so the client resumes from here
+	projectOID, _ := bson.ObjectIDFromHex(projectID)
+	reset := []domain.ProjectEvent{{ID: strconv.FormatUint(b.seq, 10), ProjectID: projectOID, Type: domain.EventStreamReset, CreatedAt: time.Now()}}
+
+	last, err := strconv.ParseUint(lastEventID, 10, 64)
+	// An ID from the future means the bus was restarted
+	if err != nil || last > b.seq {
+		return reset
+	}
+	if last < b.evicted[projectID] {
+		return reset
+	}
+
+	var missed []domain.ProjectEvent
+	for _, e := range b.history[projectID] {
+		if id, _ := strconv.ParseUint(e.ID, 10, 64); id > last {
+			missed = append(missed, e)
+		}
+	}
+	return missed
+}
+
+// remove unregisters sub and closes its channel. The caller must hold b.mu.
+func (b *memoryBus) remove(projectID string, sub *subscriber) {
+	if _, ok := b.subs[projectID][sub]; !ok {
+		return
+	}
+	delete(b.subs[projectID], sub)
+	if len(b.subs[projectID]) == 0 {
+		delete(b.subs, projectID)
+	}
+	close(sub.ch)
+}
\ No newline at end of file
@@ -0,0 +1,23 @@
+package events
+
+import (
+	"context"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+)
+
+type teeBus struct {
+	domain.EventBus
+	sink func(ctx context.Context, event domain.ProjectEvent)
+}
+
+// Tee returns a bus that publishes to bus and also hands every event to
+// sink. Subscriptions are served by bus alone.
+func Tee(bus domain.EventBus, sink func(ctx context.Context, event domain.ProjectEvent)) domain.EventBus {
+	return &teeBus{EventBus: bus, sink: sink}
+}
+
+func (b *teeBus) Publish(ctx context.Context, event domain.ProjectEvent) {
+	b.EventBus.Publish(ctx, event)
+	b.sink(ctx, event)
+}
\ No newline at end of file
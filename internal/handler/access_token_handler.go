@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/middleware"
+	"github.com/0DayMonxrch/project-management-system/pkg/validator"
+)
+
+type AccessTokenHandler struct {
+	svc domain.AccessTokenService
+}
+
+func NewAccessTokenHandler(svc domain.AccessTokenService) *AccessTokenHandler {
+	return &AccessTokenHandler{svc: svc}
+}
+
+func (h *AccessTokenHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name       string            `json:"name"`
+		Scope      domain.TokenScope `json:"scope"`
+		ProjectIDs []string          `json:"project_ids"`
+		ExpiresAt  time.Time         `json:"expires_at"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	if err := validator.New().
+		Required("name", body.Name).
+		MaxLength("name", body.Name, 100).
+		Required("scope", string(body.Scope)).
+		OneOf("scope", string(body.Scope), string(domain.ScopeRead), string(domain.ScopeReadWrite)).
+		Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r)
+	token, raw, err := h.svc.CreateToken(r.Context(), userID, body.Name, body.Scope, body.ProjectIDs, body.ExpiresAt)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// The raw token is only ever shown here
+	writeJSON(w, http.StatusCreated, struct {
+		*domain.AccessToken
+		Token string `json:"token"`
+	}{token, raw})
+}
+
+func (h *AccessTokenHandler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	tokens, err := h.svc.ListTokens(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tokens)
+}
+
+func (h *AccessTokenHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	tokenID := r.PathValue("tokenId")
+
+	if err := h.svc.RevokeToken(r.Context(), userID, tokenID); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "token revoked successfully"})
+}
\ No newline at end of file
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/middleware"
+)
+
+type AccountHandler struct {
+	svc domain.AccountService
+}
+
+func NewAccountHandler(svc domain.AccountService) *AccountHandler {
+	return &AccountHandler{svc: svc}
+}
+
+func (h *AccountHandler) ExportData(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	export, err := h.svc.ExportData(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	filename := "project-camp-export-" + export.ExportedAt.Format("2006-01-02") + ".json"
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	writeJSON(w, http.StatusOK, export)
+}
+
+func (h *AccountHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Password string `json:"password"`
+		Code     string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r)
+	if err := h.svc.DeleteAccount(r.Context(), userID, middleware.GetSessionID(r), body.Password, body.Code); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "account deleted successfully"})
+}
\ No newline at end of file
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/middleware"
+)
+
+const (
+	// multipartOverhead is what an upload's body may hold besides the file:
+	// boundaries, part headers and small form fields
+	multipartOverhead = 64 << 10
+	// transferTimeout replaces the server's read and write timeouts for
+	// uploads and downloads, which may take longer near the size limit
+	transferTimeout = 5 * time.Minute
+)
+
+type AttachmentHandler struct {
+	svc      domain.AttachmentService
+	maxBytes int64
+}
+
+// NewAttachmentHandler caps upload bodies at maxSizeMB plus room for the
+// rest of the multipart form.
+func NewAttachmentHandler(svc domain.AttachmentService, maxSizeMB int) *AttachmentHandler {
+	return &AttachmentHandler{svc: svc, maxBytes: int64(maxSizeMB)<<20 + multipartOverhead}
+}
+
+func (h *AttachmentHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	_ = rc.SetReadDeadline(time.Now().Add(transferTimeout))
+	_ = rc.SetWriteDeadline(time.Now().Add(transferTimeout))
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBytes)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "expected multipart/form-data body"})
+		return
+	}
+
+	// Hand the first "file" part to the service, skipping the rest of the
+	// form; the service holds the file in memory to check its size and type
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if tooLarge(err) {
+				writeError(w, fmt.Errorf("%w: request body is too large", domain.ErrFileTooLarge))
+				return
+			}
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid multipart body"})
+			return
+		}
+		if part.FormName() != "file" || part.FileName() == "" {
+			part.Close()
+			continue
+		}
+
+		userID, _ := middleware.GetUserID(r)
+		projectID := r.PathValue("projectId")
+		taskID := r.PathValue("taskId")
+
+		attachment, err := h.svc.UploadAttachment(r.Context(), projectID, taskID, userID, part.FileName(), part)
+		part.Close()
+		if tooLarge(err) {
+			err = fmt.Errorf("%w: request body is too large", domain.ErrFileTooLarge)
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, attachment)
+		return
+	}
+
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": "file: is required"})
+}
+
+func (h *AttachmentHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+	taskID := r.PathValue("taskId")
+	attachmentID := r.PathValue("attachmentId")
+
+	attachment, content, err := h.svc.DownloadAttachment(r.Context(), projectID, taskID, attachmentID, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer content.Close()
+
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(transferTimeout))
+	w.Header().Set("Content-Type", attachment.MimeType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, content)
+}
+
+func (h *AttachmentHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+	taskID := r.PathValue("taskId")
+	attachmentID := r.PathValue("attachmentId")
+
+	if err := h.svc.DeleteAttachment(r.Context(), projectID, taskID, attachmentID, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "attachment deleted successfully"})
+}
+
+func tooLarge(err error) bool {
+	var maxErr *http.MaxBytesError
+	return errors.As(err, &maxErr)
+}
\ No newline at end of file
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/middleware"
+)
+
+type fakeAttachmentService struct {
+	err      error
+	got      []string
+	uploaded string
+}
+
+func (f *fakeAttachmentService) UploadAttachment(ctx context.Context, projectID, taskID, requesterID, filename string, content io.Reader) (*domain.Attachment, error) {
+	f.got = []string{projectID, taskID, requesterID, filename}
+	data, _ := io.ReadAll(content)
+	f.uploaded = string(data)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &domain.Attachment{Name: filename, Size: int64(len(data))}, nil
+}
+
+func (f *fakeAttachmentService) DownloadAttachment(ctx context.Context, projectID, taskID, attachmentID, requesterID string) (*domain.Attachment, io.ReadCloser, error) {
+	f.got = []string{projectID, taskID, attachmentID, requesterID}
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	a := &domain.Attachment{Name: "report \"q1\".pdf", MimeType: "application/pdf", Size: 4}
+	return a, io.NopCloser(strings.NewReader("%PDF")), nil
+}
+
+func (f *fakeAttachmentService) DeleteAttachment(ctx context.Context, projectID, taskID, attachmentID, requesterID string) error {
+	f.got = []string{projectID, taskID, attachmentID, requesterID}
+	return f.err
+}
+
+func attachmentMux(svc domain.AttachmentService) http.Handler {
+	h := NewAttachmentHandler(svc, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/tasks/{projectId}/t/{taskId}/attachments", h.UploadAttachment)
+	mux.HandleFunc("GET /api/v1/tasks/{projectId}/t/{taskId}/attachments/{attachmentId}", h.DownloadAttachment)
+	mux.HandleFunc("DELETE /api/v1/tasks/{projectId}/t/{taskId}/attachments/{attachmentId}", h.DeleteAttachment)
+	return mux
+}
+
+func withUser(r *http.Request, userID string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), middleware.UserIDKey, userID))
+}
+
+func multipartBody(t *testing.T, field, filename, content string) (*bytes.Buffer, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	mw.WriteField("comment", "ignored")
+	if field != "" {
+		fw, err := mw.CreateFormFile(field, filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write([]byte(content))
+	}
+	mw.Close()
+	return &buf, mw.FormDataContentType()
+}
+
+func TestUploadAttachment(t *testing.T) {
+	tests := []struct {
+		name       string
+		field      string
+		filename   string
+		svcErr     error
+		rawBody    bool
+		wantStatus int
+	}{
+		{name: "created", field: "file", filename: "a.png", wantStatus: http.StatusCreated},
+		{name: "not multipart", rawBody: true, wantStatus: http.StatusBadRequest},
+		{name: "no file part", wantStatus: http.StatusBadRequest},
+		{name: "file part without filename", field: "file", wantStatus: http.StatusBadRequest},
+		{name: "wrong field name", field: "upload", filename: "a.png", wantStatus: http.StatusBadRequest},
+		{name: "too large", field: "file", filename: "a.png", svcErr: domain.ErrFileTooLarge, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "unsupported type", field: "file", filename: "a.exe", svcErr: domain.ErrUnsupportedMedia, wantStatus: http.StatusUnsupportedMediaType},
+		{name: "forbidden", field: "file", filename: "a.png", svcErr: domain.ErrForbidden, wantStatus: http.StatusForbidden},
+		{name: "task not found", field: "file", filename: "a.png", svcErr: domain.ErrNotFound, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeAttachmentService{err: tt.svcErr}
+			var req *http.Request
+			if tt.rawBody {
+				req = httptest.NewRequest(http.MethodPost, "/api/v1/tasks/p1/t/t1/attachments", strings.NewReader("{}"))
+				req.Header.Set("Content-Type", "application/json")
+			} else {
+				body, ct := multipartBody(t, tt.field, tt.filename, "data")
+				req = httptest.NewRequest(http.MethodPost, "/api/v1/tasks/p1/t/t1/attachments", body)
+				req.Header.Set("Content-Type", ct)
+			}
+			rec := httptest.NewRecorder()
+			attachmentMux(svc).ServeHTTP(rec, withUser(req, "u1"))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus == http.StatusCreated {
+				if want := "p1 t1 u1 a.png"; strings.Join(svc.got, " ") != want {
+					t.Errorf("service called with %v, want %s", svc.got, want)
+				}
+				if svc.uploaded != "data" {
+					t.Errorf("service read %q, want the file part", svc.uploaded)
+				}
+			}
+		})
+	}
+}
+
+func TestUploadAttachmentBoundsTheBody(t *testing.T) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	mw.WriteField("padding", strings.Repeat("x", 2<<20))
+	fw, _ := mw.CreateFormFile("file", "a.png")
+	fw.Write([]byte("data"))
+	mw.Close()
+
+	svc := &fakeAttachmentService{}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/p1/t/t1/attachments", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	rec := httptest.NewRecorder()
+	attachmentMux(svc).ServeHTTP(rec, withUser(req, "u1"))
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want 413 (body %s)", rec.Code, rec.Body)
+	}
+	if svc.got != nil {
+		t.Errorf("service called with %v, want no call", svc.got)
+	}
+}
+
+func TestDownloadAttachment(t *testing.T) {
+	svc := &fakeAttachmentService{}
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/p1/t/t1/attachments/a1", nil)
+	rec := httptest.NewRecorder()
+	attachmentMux(svc).ServeHTTP(rec, withUser(req, "u1"))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if want := "p1 t1 a1 u1"; strings.Join(svc.got, " ") != want {
+		t.Errorf("service called with %v, want %s", svc.got, want)
+	}
+	for header, want := range map[string]string{
+		"Content-Type":           "application/pdf",
+		"Content-Length":         "4",
+		"Content-Disposition":    `attachment; filename="report \"q1\".pdf"`,
+		"X-Content-Type-Options": "nosniff",
+	} {
+		if got := rec.Header().Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+	if rec.Body.String() != "%PDF" {
+		t.Errorf("body = %q", rec.Body)
+	}
+}
+
+func TestAttachmentErrors(t *testing.T) {
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		for err, want := range map[error]int{
+			domain.ErrNotFound:                            http.StatusNotFound,
+			domain.ErrForbidden:                           http.StatusForbidden,
+			fmt.Errorf("wrapped: %w", domain.ErrNotFound): http.StatusNotFound,
+			errors.New("disk on fire"):                    http.StatusInternalServerError,
+		} {
+			svc := &fakeAttachmentService{err: err}
+			req := httptest.NewRequest(method, "/api/v1/tasks/p1/t/t1/attachments/a1", nil)
+			rec := httptest.NewRecorder()
+			attachmentMux(svc).ServeHTTP(rec, withUser(req, "u1"))
+			if rec.Code != want {
+				t.Errorf("%s with %v: status = %d, want %d", method, err, rec.Code, want)
+			}
+		}
+	}
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	svc := &fakeAttachmentService{}
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/tasks/p1/t/t1/attachments/a1", nil)
+	rec := httptest.NewRecorder()
+	attachmentMux(svc).ServeHTTP(rec, withUser(req, "u1"))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if want := "p1 t1 a1 u1"; strings.Join(svc.got, " ") != want {
+		t.Errorf("service called with %v, want %s", svc.got, want)
+	}
+	var body map[string]string
+	json.NewDecoder(rec.Body).Decode(&body)
+	if body["message"] == "" {
+		t.Errorf("missing message in %v", body)
+	}
+}
\ No newline at end of file
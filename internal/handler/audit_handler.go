@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/middleware"
+	"github.com/0DayMonxrch/project-management-system/pkg/validator"
+)
+
+type AuditHandler struct {
+	svc domain.AuditService
+}
+
+func NewAuditHandler(svc domain.AuditService) *AuditHandler {
+	return &AuditHandler{svc: svc}
+}
+
+func (h *AuditHandler) ListProjectAudit(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+
+	page, err := h.svc.ListProjectAudit(r.Context(), projectID, userID, filter)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (h *AuditHandler) ListAudit(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	filter.ProjectID = r.URL.Query().Get("project_id")
+
+	userID, _ := middleware.GetUserID(r)
+	page, err := h.svc.ListAudit(r.Context(), userID, filter)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// --- helpers ---
+
+func parseAuditFilter(r *http.Request) (domain.AuditFilter, error) {
+	q := r.URL.Query()
+	filter := domain.AuditFilter{
+		ActorID:  q.Get("actor_id"),
+		Action:   domain.AuditAction(q.Get("action")),
+		TargetID: q.Get("target_id"),
+		Cursor:   q.Get("cursor"),
+	}
+
+	var err error
+	if filter.Since, _, err = parseDateParam(q.Get("since")); err != nil {
+		return filter, &validator.ValidationError{Field: "since", Message: "must be a date (YYYY-MM-DD) or RFC 3339 timestamp"}
+	}
+	if filter.Until, _, err = parseDateParam(q.Get("until")); err != nil {
+		return filter, &validator.ValidationError{Field: "until", Message: "must be a date (YYYY-MM-DD) or RFC 3339 timestamp"}
+	}
+	if filter.Limit, err = parseLimit(q.Get("limit")); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
\ No newline at end of file
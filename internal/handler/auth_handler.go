@@ -2,7 +2,9 @@ package handler
 
 import (
 	"encoding/json"
+	"net"
 	"net/http"
+	"time"
 
 	"github.com/0DayMonxrch/project-management-system/internal/domain"
 	"github.com/0DayMonxrch/project-management-system/internal/middleware"
@@ -65,20 +67,106 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	accessToken, refreshToken, err := h.svc.Login(r.Context(), body.Email, body.Password)
+	result, err := h.svc.Login(r.Context(), body.Email, body.Password, clientInfo(r))
 	if err != nil {
 		writeError(w, err)
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]string{
-		"access_token":  accessToken,
-		"refresh_token": refreshToken,
-	})
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *AuthHandler) LoginMethods(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.svc.LoginMethods())
+}
+
+func (h *AuthHandler) StartOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	provider := r.PathValue("provider")
+	authURL, flowToken, err := h.svc.StartOIDCLogin(r.Context(), provider)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	setOIDCFlowCookie(w, r, provider, flowToken)
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// StartOIDCLink is called by the signed-in app, which can't follow a
+// redirect with the bearer token, so it returns the URL to navigate to.
+func (h *AuthHandler) StartOIDCLink(w http.ResponseWriter, r *http.Request) {
+	provider := r.PathValue("provider")
+	userID, _ := middleware.GetUserID(r)
+	authURL, flowToken, err := h.svc.StartOIDCLink(r.Context(), provider, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	setOIDCFlowCookie(w, r, provider, flowToken)
+	writeJSON(w, http.StatusOK, map[string]string{"auth_url": authURL})
+}
+
+func (h *AuthHandler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	provider := r.PathValue("provider")
+	q := r.URL.Query()
+	if e := q.Get("error"); e != "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "sign-in was not completed: " + e})
+		return
+	}
+
+	if err := validator.New().
+		Required("code", q.Get("code")).
+		Required("state", q.Get("state")).
+		Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	cookie, err := r.Cookie(oidcFlowCookie)
+	if err != nil {
+		writeError(w, domain.ErrTokenInvalid)
+		return
+	}
+	// The flow is single-use whatever the outcome
+	http.SetCookie(w, &http.Cookie{Name: oidcFlowCookie, Path: "/api/v1/auth/oidc/" + provider, MaxAge: -1})
+
+	result, err := h.svc.CompleteOIDCLogin(r.Context(), provider, q.Get("code"), q.Get("state"), cookie.Value, clientInfo(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *AuthHandler) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		MFAToken string `json:"mfa_token"`
+		Code     string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	if err := validator.New().
+		Required("mfa_token", body.MFAToken).
+		Required("code", body.Code).
+		Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	result, err := h.svc.VerifyMFA(r.Context(), body.MFAToken, body.Code, clientInfo(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
 }
 
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	userID, _ := middleware.GetUserID(r)
-	if err := h.svc.Logout(r.Context(), userID); err != nil {
+	if err := h.svc.Logout(r.Context(), userID, middleware.GetSessionID(r)); err != nil {
 		writeError(w, err)
 		return
 	}
@@ -115,13 +203,49 @@ func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userID, _ := middleware.GetUserID(r)
-	if err := h.svc.ChangePassword(r.Context(), userID, body.OldPassword, body.NewPassword); err != nil {
+	if err := h.svc.ChangePassword(r.Context(), userID, middleware.GetSessionID(r), body.OldPassword, body.NewPassword); err != nil {
 		writeError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, map[string]string{"message": "password changed successfully"})
 }
 
+func (h *AuthHandler) RequestEmailChange(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Password string `json:"password"`
+		NewEmail string `json:"new_email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	if err := validator.New().
+		Required("password", body.Password).
+		Required("new_email", body.NewEmail).
+		Email("new_email", body.NewEmail).
+		Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r)
+	if err := h.svc.RequestEmailChange(r.Context(), userID, body.Password, body.NewEmail, clientInfo(r)); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "confirmation link sent to the new email"})
+}
+
+func (h *AuthHandler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	token := r.PathValue("emailChangeToken")
+	if err := h.svc.ConfirmEmailChange(r.Context(), token); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "email changed successfully"})
+}
+
 func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	var body struct {
 		RefreshToken string `json:"refresh_token"`
@@ -131,12 +255,22 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	accessToken, err := h.svc.RefreshToken(r.Context(), body.RefreshToken)
+	if err := validator.New().
+		Required("refresh_token", body.RefreshToken).
+		Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	accessToken, refreshToken, err := h.svc.RefreshToken(r.Context(), body.RefreshToken, clientInfo(r))
 	if err != nil {
 		writeError(w, err)
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]string{"access_token": accessToken})
+	writeJSON(w, http.StatusOK, map[string]string{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+	})
 }
 
 func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
@@ -148,6 +282,26 @@ func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"message": "email verified successfully"})
 }
 
+func (h *AuthHandler) UnlockAccount(w http.ResponseWriter, r *http.Request) {
+	token := r.PathValue("unlockToken")
+	if err := h.svc.UnlockAccount(r.Context(), token); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "account unlocked successfully"})
+}
+
+func (h *AuthHandler) AdminUnlockAccount(w http.ResponseWriter, r *http.Request) {
+	requesterID, _ := middleware.GetUserID(r)
+	userID := r.PathValue("userId")
+
+	if err := h.svc.AdminUnlockAccount(r.Context(), requesterID, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "account unlocked successfully"})
+}
+
 func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 	var body struct {
 		Email string `json:"email"`
@@ -165,7 +319,7 @@ func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.svc.ForgotPassword(r.Context(), body.Email); err != nil {
+	if err := h.svc.ForgotPassword(r.Context(), body.Email, clientInfo(r)); err != nil {
 		writeError(w, err)
 		return
 	}
@@ -199,9 +353,149 @@ func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 
 func (h *AuthHandler) ResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
 	userID, _ := middleware.GetUserID(r)
-	if err := h.svc.ResendVerificationEmail(r.Context(), userID); err != nil {
+	if err := h.svc.ResendVerificationEmail(r.Context(), userID, clientInfo(r)); err != nil {
 		writeError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, map[string]string{"message": "verification email sent"})
+}
+
+func (h *AuthHandler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	enrollment, err := h.svc.EnrollTOTP(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, enrollment)
+}
+
+func (h *AuthHandler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	if err := validator.New().
+		Required("code", body.Code).
+		Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r)
+	codes, err := h.svc.ConfirmTOTP(r.Context(), userID, body.Code)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]string{"recovery_codes": codes})
+}
+
+func (h *AuthHandler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Password string `json:"password"`
+		Code     string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	if err := validator.New().
+		Required("password", body.Password).
+		Required("code", body.Code).
+		Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r)
+	if err := h.svc.DisableTOTP(r.Context(), userID, body.Password, body.Code); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "two-factor authentication disabled successfully"})
+}
+
+func (h *AuthHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	if err := validator.New().
+		Required("code", body.Code).
+		Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r)
+	codes, err := h.svc.RegenerateRecoveryCodes(r.Context(), userID, body.Code)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]string{"recovery_codes": codes})
+}
+
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	sessions, err := h.svc.ListSessions(r.Context(), userID, middleware.GetSessionID(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sessions)
+}
+
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	sessionID := r.PathValue("sessionId")
+
+	if err := h.svc.RevokeSession(r.Context(), userID, sessionID); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "session revoked successfully"})
+}
+
+// --- helpers ---
+
+const oidcFlowCookie = "oidc_flow"
+
+// isHTTPS reports whether the client connected over TLS, directly or
+// through a proxy.
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
+
+func clientInfo(r *http.Request) domain.ClientInfo {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	return domain.ClientInfo{UserAgent: r.UserAgent(), IP: ip}
+}
+
+// setOIDCFlowCookie hands the flow token to the callback. It holds the PKCE
+// verifier, so it stays in the browser rather than travelling through the
+// provider.
+func setOIDCFlowCookie(w http.ResponseWriter, r *http.Request, provider, flowToken string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcFlowCookie,
+		Value:    flowToken,
+		Path:     "/api/v1/auth/oidc/" + provider,
+		MaxAge:   int((10 * time.Minute).Seconds()),
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
 }
\ No newline at end of file
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/middleware"
+	"github.com/0DayMonxrch/project-management-system/pkg/validator"
+)
+
+type CommentHandler struct {
+	svc domain.CommentService
+}
+
+func NewCommentHandler(svc domain.CommentService) *CommentHandler {
+	return &CommentHandler{svc: svc}
+}
+
+func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Content  string `json:"content"`
+		ParentID string `json:"parent_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	if err := validator.New().
+		Required("content", body.Content).
+		MaxLength("content", body.Content, 5000).
+		Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+	taskID := r.PathValue("taskId")
+
+	comment, err := h.svc.CreateComment(r.Context(), projectID, taskID, userID, body.ParentID, body.Content)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, comment)
+}
+
+func (h *CommentHandler) ListComments(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+	taskID := r.PathValue("taskId")
+
+	comments, err := h.svc.ListComments(r.Context(), projectID, taskID, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, comments)
+}
+
+func (h *CommentHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Content string `json:"content"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	if err := validator.New().
+		Required("content", body.Content).
+		MaxLength("content", body.Content, 5000).
+		Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+	taskID := r.PathValue("taskId")
+	commentID := r.PathValue("commentId")
+
+	comment, err := h.svc.UpdateComment(r.Context(), projectID, taskID, commentID, userID, body.Content)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, comment)
+}
+
+func (h *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+	taskID := r.PathValue("taskId")
+	commentID := r.PathValue("commentId")
+
+	if err := h.svc.DeleteComment(r.Context(), projectID, taskID, commentID, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "comment deleted successfully"})
+}
\ No newline at end of file
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/middleware"
+)
+
+const (
+	// heartbeatInterval keeps idle streams alive through proxies
+	heartbeatInterval = 15 * time.Second
+	// reconnectDelay is the retry hint sent to clients, in milliseconds
+	reconnectDelay = 3000
+)
+
+type EventHandler struct {
+	svc domain.EventService
+}
+
+func NewEventHandler(svc domain.EventService) *EventHandler {
+	return &EventHandler{svc: svc}
+}
+
+// StreamEvents sends project events as Server-Sent Events until the client
+// disconnects. Clients resume with the Last-Event-ID header, or the
+// last_event_id query parameter where headers can't be set.
+func (h *EventHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	events, err := h.svc.Subscribe(r.Context(), projectID, userID, lastEventID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// The stream outlives the server's write timeout
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", reconnectDelay)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// --- helpers ---
+
+func writeEvent(w io.Writer, event domain.ProjectEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
\ No newline at end of file
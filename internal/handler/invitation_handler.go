@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/middleware"
+	"github.com/0DayMonxrch/project-management-system/pkg/validator"
+)
+
+type InvitationHandler struct {
+	svc domain.InvitationService
+}
+
+func NewInvitationHandler(svc domain.InvitationService) *InvitationHandler {
+	return &InvitationHandler{svc: svc}
+}
+
+func (h *InvitationHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Email string      `json:"email"`
+		Role  domain.Role `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	if err := validator.New().
+		Required("email", body.Email).
+		Email("email", body.Email).
+		Required("role", string(body.Role)).
+		MaxLength("role", string(body.Role), 32).
+		Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+
+	invitation, err := h.svc.Invite(r.Context(), projectID, userID, body.Email, body.Role)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, invitation)
+}
+
+func (h *InvitationHandler) ListInvitations(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+
+	invitations, err := h.svc.ListInvitations(r.Context(), projectID, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, invitations)
+}
+
+func (h *InvitationHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+	invitationID := r.PathValue("invitationId")
+
+	if err := h.svc.RevokeInvitation(r.Context(), projectID, invitationID, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "invitation revoked successfully"})
+}
+
+func (h *InvitationHandler) GetInvitation(w http.ResponseWriter, r *http.Request) {
+	token := r.PathValue("invitationToken")
+
+	preview, err := h.svc.GetInvitation(r.Context(), token)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, preview)
+}
+
+func (h *InvitationHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	token := r.PathValue("invitationToken")
+
+	project, err := h.svc.AcceptInvitation(r.Context(), token, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, project)
+}
\ No newline at end of file
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/middleware"
+	"github.com/0DayMonxrch/project-management-system/pkg/validator"
+)
+
+type JoinLinkHandler struct {
+	svc domain.JoinLinkService
+}
+
+func NewJoinLinkHandler(svc domain.JoinLinkService) *JoinLinkHandler {
+	return &JoinLinkHandler{svc: svc}
+}
+
+func (h *JoinLinkHandler) CreateJoinLink(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Role      domain.Role `json:"role"`
+		MaxUses   int         `json:"max_uses"`
+		ExpiresAt *time.Time  `json:"expires_at"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	if err := validator.New().
+		Required("role", string(body.Role)).
+		MaxLength("role", string(body.Role), 32).
+		Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+
+	link, token, err := h.svc.CreateJoinLink(r.Context(), projectID, userID, body.Role, body.MaxUses, body.ExpiresAt)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// The token is only ever shown here
+	writeJSON(w, http.StatusCreated, struct {
+		*domain.JoinLink
+		Token string `json:"token"`
+	}{link, token})
+}
+
+func (h *JoinLinkHandler) ListJoinLinks(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+
+	links, err := h.svc.ListJoinLinks(r.Context(), projectID, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, links)
+}
+
+func (h *JoinLinkHandler) RevokeJoinLink(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+	linkID := r.PathValue("linkId")
+
+	if err := h.svc.RevokeJoinLink(r.Context(), projectID, linkID, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "join link revoked successfully"})
+}
+
+func (h *JoinLinkHandler) ListJoinLinkUses(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+	linkID := r.PathValue("linkId")
+
+	uses, err := h.svc.ListJoinLinkUses(r.Context(), projectID, linkID, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, uses)
+}
+
+func (h *JoinLinkHandler) Join(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	token := r.PathValue("joinToken")
+
+	project, err := h.svc.Join(r.Context(), token, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, project)
+}
\ No newline at end of file
@@ -48,8 +48,15 @@ func (h *NoteHandler) CreateNote(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *NoteHandler) ListNotes(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePageRequest(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r)
 	projectID := r.PathValue("projectId")
-	notes, err := h.svc.ListNotes(r.Context(), projectID)
+	notes, err := h.svc.ListNotes(r.Context(), projectID, userID, page)
 	if err != nil {
 		writeError(w, err)
 		return
@@ -58,10 +65,11 @@ func (h *NoteHandler) ListNotes(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *NoteHandler) GetNote(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
 	projectID := r.PathValue("projectId")
 	noteID := r.PathValue("noteId")
 
-	note, err := h.svc.GetNote(r.Context(), projectID, noteID)
+	note, err := h.svc.GetNote(r.Context(), projectID, noteID, userID)
 	if err != nil {
 		writeError(w, err)
 		return
@@ -88,9 +96,10 @@ func (h *NoteHandler) UpdateNote(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
 	noteID := r.PathValue("noteId")
 
-	note, err := h.svc.UpdateNote(r.Context(), noteID, userID, body.Title, body.Content)
+	note, err := h.svc.UpdateNote(r.Context(), projectID, noteID, userID, body.Title, body.Content)
 	if err != nil {
 		writeError(w, err)
 		return
@@ -100,9 +109,10 @@ func (h *NoteHandler) UpdateNote(w http.ResponseWriter, r *http.Request) {
 
 func (h *NoteHandler) DeleteNote(w http.ResponseWriter, r *http.Request) {
 	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
 	noteID := r.PathValue("noteId")
 
-	if err := h.svc.DeleteNote(r.Context(), noteID, userID); err != nil {
+	if err := h.svc.DeleteNote(r.Context(), projectID, noteID, userID); err != nil {
 		writeError(w, err)
 		return
 	}
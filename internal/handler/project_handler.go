@@ -46,8 +46,14 @@ func (h *ProjectHandler) CreateProject(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *ProjectHandler) ListProjects(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePageRequest(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
 	userID, _ := middleware.GetUserID(r)
-	projects, err := h.svc.ListProjects(r.Context(), userID)
+	projects, err := h.svc.ListProjects(r.Context(), userID, page)
 	if err != nil {
 		writeError(w, err)
 		return
@@ -113,7 +119,7 @@ func (h *ProjectHandler) AddMember(w http.ResponseWriter, r *http.Request) {
 		Required("email", body.Email).
 		Email("email", body.Email).
 		Required("role", string(body.Role)).
-		OneOf("role", string(body.Role), "admin", "project_admin", "member").
+		MaxLength("role", string(body.Role), 32).
 		Validate(); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
 		return
@@ -130,8 +136,10 @@ func (h *ProjectHandler) AddMember(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *ProjectHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
 	projectID := r.PathValue("projectId")
-	members, err := h.svc.ListMembers(r.Context(), projectID)
+
+	members, err := h.svc.ListMembers(r.Context(), projectID, userID)
 	if err != nil {
 		writeError(w, err)
 		return
@@ -150,7 +158,7 @@ func (h *ProjectHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request
 
 	if err := validator.New().
 		Required("role", string(body.Role)).
-		OneOf("role", string(body.Role), "admin", "project_admin", "member").
+		MaxLength("role", string(body.Role), 32).
 		Validate(); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
 		return
@@ -177,4 +185,149 @@ func (h *ProjectHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	writeJSON(w, http.StatusOK, map[string]string{"message": "member removed successfully"})
+}
+
+func (h *ProjectHandler) TransferOwnership(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		UserID string `json:"user_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	if err := validator.New().
+		Required("user_id", body.UserID).
+		Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+
+	project, err := h.svc.TransferOwnership(r.Context(), projectID, userID, body.UserID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, project)
+}
+
+func (h *ProjectHandler) ListOrphanedProjects(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePageRequest(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r)
+	projects, err := h.svc.ListOrphanedProjects(r.Context(), userID, page)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, projects)
+}
+
+func (h *ProjectHandler) RecoverProject(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		UserID string `json:"user_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	if err := validator.New().
+		Required("user_id", body.UserID).
+		Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+
+	project, err := h.svc.RecoverProject(r.Context(), projectID, userID, body.UserID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, project)
+}
+
+func (h *ProjectHandler) GetWorkflow(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+
+	workflow, err := h.svc.GetWorkflow(r.Context(), projectID, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, workflow)
+}
+
+func (h *ProjectHandler) UpdateWorkflow(w http.ResponseWriter, r *http.Request) {
+	var body domain.Workflow
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+
+	workflow, err := h.svc.UpdateWorkflow(r.Context(), projectID, userID, body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, workflow)
+}
+
+func (h *ProjectHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+
+	roles, err := h.svc.ListRoles(r.Context(), projectID, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, roles)
+}
+
+func (h *ProjectHandler) SaveRole(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Permissions []domain.Permission `json:"permissions"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+	name := domain.Role(r.PathValue("role"))
+
+	role, err := h.svc.SaveRole(r.Context(), projectID, userID, name, body.Permissions)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, role)
+}
+
+func (h *ProjectHandler) DeleteRole(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+	name := domain.Role(r.PathValue("role"))
+
+	if err := h.svc.DeleteRole(r.Context(), projectID, userID, name); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "role deleted successfully"})
 }
\ No newline at end of file
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/pkg/validator"
+)
+
+// parsePageRequest reads the cursor, limit and sort query parameters.
+func parsePageRequest(r *http.Request) (domain.PageRequest, error) {
+	q := r.URL.Query()
+	limit, err := parseLimit(q.Get("limit"))
+	if err != nil {
+		return domain.PageRequest{}, err
+	}
+	return domain.PageRequest{
+		Cursor: q.Get("cursor"),
+		Limit:  limit,
+		Sort:   q.Get("sort"),
+	}, nil
+}
+
+func parseLimit(v string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(v)
+	if err != nil || limit < 1 {
+		return 0, &validator.ValidationError{Field: "limit", Message: "must be a positive integer"}
+	}
+	return limit, nil
+}
+
+// parseDateParam accepts YYYY-MM-DD or RFC 3339 and reports which one it got.
+// An empty value yields the zero time.
+func parseDateParam(v string) (time.Time, bool, error) {
+	if v == "" {
+		return time.Time{}, false, nil
+	}
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	return t, false, err
+}
\ No newline at end of file
@@ -0,0 +1,455 @@
+package handler
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// In-memory repositories, enough of them for the services behind the
+// project routes to run as they would against MongoDB.
+
+type rows[T any] map[bson.ObjectID]*T
+
+func (m rows[T]) find(id string) (*T, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+	row, ok := m[oid]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *row
+	return &copied, nil
+}
+
+func (m rows[T]) where(keep func(*T) bool) []T {
+	var found []T
+	for _, row := range m {
+		if keep(row) {
+			found = append(found, *row)
+		}
+	}
+	return found
+}
+
+func (m rows[T]) remove(id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	delete(m, oid)
+	return nil
+}
+
+type memUsers struct {
+	domain.UserRepository
+	rows rows[domain.User]
+}
+
+func (r *memUsers) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	return r.rows.find(id)
+}
+
+func (r *memUsers) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	found := r.rows.where(func(u *domain.User) bool { return u.Email == email })
+	if len(found) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return &found[0], nil
+}
+
+func (r *memUsers) FindByIDs(ctx context.Context, ids []string) ([]domain.User, error) {
+	return r.rows.where(func(u *domain.User) bool { return slices.Contains(ids, u.ID.Hex()) }), nil
+}
+
+type memProjects struct {
+	domain.ProjectRepository
+	rows rows[domain.Project]
+}
+
+func (r *memProjects) FindByID(ctx context.Context, id string) (*domain.Project, error) {
+	p, err := r.rows.find(id)
+	if err != nil {
+		return nil, err
+	}
+	p.Members = slices.Clone(p.Members)
+	p.Roles = slices.Clone(p.Roles)
+	return p, nil
+}
+
+func (r *memProjects) Update(ctx context.Context, project *domain.Project) error {
+	copied := *project
+	// Like the repository, a save leaves the members and owner as stored
+	if stored, ok := r.rows[project.ID]; ok {
+		copied.Members, copied.CreatedBy = stored.Members, stored.CreatedBy
+	}
+	r.rows[project.ID] = &copied
+	return nil
+}
+
+func (r *memProjects) AddMember(ctx context.Context, projectID bson.ObjectID, member domain.ProjectMember) error {
+	p, ok := r.rows[projectID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if _, ok := p.MemberRole(member.UserID.Hex()); ok {
+		return domain.ErrConflict
+	}
+	p.Members = append(slices.Clone(p.Members), member)
+	return nil
+}
+
+func (r *memProjects) UpdateMemberRole(ctx context.Context, projectID, userID bson.ObjectID, role domain.Role) error {
+	p, ok := r.rows[projectID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	i := slices.IndexFunc(p.Members, func(m domain.ProjectMember) bool { return m.UserID == userID })
+	if i < 0 {
+		return domain.ErrNotFound
+	}
+	p.Members = slices.Clone(p.Members)
+	p.Members[i].Role = role
+	return nil
+}
+
+func (r *memProjects) RemoveMember(ctx context.Context, projectID, userID bson.ObjectID) error {
+	p, ok := r.rows[projectID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	p.Members = slices.DeleteFunc(slices.Clone(p.Members), func(m domain.ProjectMember) bool { return m.UserID == userID })
+	return nil
+}
+
+func (r *memProjects) SetOwner(ctx context.Context, projectID, userID bson.ObjectID) error {
+	p, ok := r.rows[projectID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	p.CreatedBy = userID
+	return nil
+}
+
+func (r *memProjects) Delete(ctx context.Context, id string) error {
+	return r.rows.remove(id)
+}
+
+type memTasks struct {
+	domain.TaskRepository
+	rows rows[domain.Task]
+}
+
+func (r *memTasks) Create(ctx context.Context, task *domain.Task) error {
+	task.ID = bson.NewObjectID()
+	copied := *task
+	r.rows[task.ID] = &copied
+	return nil
+}
+
+func (r *memTasks) FindByID(ctx context.Context, id string) (*domain.Task, error) {
+	return r.rows.find(id)
+}
+
+func (r *memTasks) FindBySubTaskID(ctx context.Context, projectID, subTaskID string) (*domain.Task, error) {
+	found := r.rows.where(func(t *domain.Task) bool {
+		return t.ProjectID.Hex() == projectID && slices.ContainsFunc(t.SubTasks, func(st domain.SubTask) bool { return st.ID.Hex() == subTaskID })
+	})
+	if len(found) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return &found[0], nil
+}
+
+func (r *memTasks) FindByProjectID(ctx context.Context, projectID string, filter domain.TaskFilter, page domain.PageRequest) (*domain.Page[domain.Task], error) {
+	return &domain.Page[domain.Task]{Items: r.rows.where(func(t *domain.Task) bool { return t.ProjectID.Hex() == projectID })}, nil
+}
+
+func (r *memTasks) CountByStatus(ctx context.Context, projectID string, statuses []domain.TaskStatus) (int64, error) {
+	found := r.rows.where(func(t *domain.Task) bool {
+		return t.ProjectID.Hex() == projectID && slices.Contains(statuses, t.Status)
+	})
+	return int64(len(found)), nil
+}
+
+func (r *memTasks) Update(ctx context.Context, task *domain.Task) error {
+	stored, ok := r.rows[task.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	copied := *task
+	copied.Attachments = stored.Attachments
+	r.rows[task.ID] = &copied
+	return nil
+}
+
+func (r *memTasks) AddAttachment(ctx context.Context, taskID bson.ObjectID, a domain.Attachment) error {
+	t, ok := r.rows[taskID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	t.Attachments = append(slices.Clone(t.Attachments), a)
+	return nil
+}
+
+func (r *memTasks) RemoveAttachment(ctx context.Context, taskID, attachmentID bson.ObjectID) error {
+	t, ok := r.rows[taskID]
+	if !ok || !slices.ContainsFunc(t.Attachments, func(a domain.Attachment) bool { return a.ID == attachmentID }) {
+		return domain.ErrNotFound
+	}
+	t.Attachments = slices.DeleteFunc(slices.Clone(t.Attachments), func(a domain.Attachment) bool { return a.ID == attachmentID })
+	return nil
+}
+
+func (r *memTasks) Delete(ctx context.Context, id string) error {
+	return r.rows.remove(id)
+}
+
+type memNotes struct {
+	domain.NoteRepository
+	rows rows[domain.Note]
+}
+
+func (r *memNotes) Create(ctx context.Context, note *domain.Note) error {
+	note.ID = bson.NewObjectID()
+	copied := *note
+	r.rows[note.ID] = &copied
+	return nil
+}
+
+func (r *memNotes) FindByID(ctx context.Context, id string) (*domain.Note, error) {
+	return r.rows.find(id)
+}
+
+func (r *memNotes) FindByProjectID(ctx context.Context, projectID string, page domain.PageRequest) (*domain.Page[domain.Note], error) {
+	return &domain.Page[domain.Note]{Items: r.rows.where(func(n *domain.Note) bool { return n.ProjectID.Hex() == projectID })}, nil
+}
+
+func (r *memNotes) Update(ctx context.Context, note *domain.Note) error {
+	copied := *note
+	r.rows[note.ID] = &copied
+	return nil
+}
+
+func (r *memNotes) Delete(ctx context.Context, id string) error {
+	return r.rows.remove(id)
+}
+
+type memComments struct {
+	domain.CommentRepository
+	rows rows[domain.Comment]
+}
+
+func (r *memComments) Create(ctx context.Context, comment *domain.Comment) error {
+	comment.ID = bson.NewObjectID()
+	copied := *comment
+	r.rows[comment.ID] = &copied
+	return nil
+}
+
+func (r *memComments) FindByID(ctx context.Context, id string) (*domain.Comment, error) {
+	return r.rows.find(id)
+}
+
+func (r *memComments) FindByTaskID(ctx context.Context, taskID string) ([]domain.Comment, error) {
+	return r.rows.where(func(c *domain.Comment) bool { return c.TaskID.Hex() == taskID }), nil
+}
+
+func (r *memComments) Update(ctx context.Context, comment *domain.Comment) error {
+	copied := *comment
+	r.rows[comment.ID] = &copied
+	return nil
+}
+
+func (r *memComments) Delete(ctx context.Context, id string) error {
+	return r.rows.remove(id)
+}
+
+func (r *memComments) DeleteReplies(ctx context.Context, parentID string) error {
+	for id, c := range r.rows {
+		if c.ParentID.Hex() == parentID {
+			delete(r.rows, id)
+		}
+	}
+	return nil
+}
+
+func (r *memComments) DeleteByTaskID(ctx context.Context, taskID string) error {
+	for id, c := range r.rows {
+		if c.TaskID.Hex() == taskID {
+			delete(r.rows, id)
+		}
+	}
+	return nil
+}
+
+type memActivity struct {
+	domain.ActivityRepository
+	rows rows[domain.TaskActivity]
+}
+
+func (r *memActivity) Create(ctx context.Context, activity *domain.TaskActivity) error {
+	activity.ID = bson.NewObjectID()
+	copied := *activity
+	r.rows[activity.ID] = &copied
+	return nil
+}
+
+func (r *memActivity) FindByTaskID(ctx context.Context, taskID string) ([]domain.TaskActivity, error) {
+	return r.rows.where(func(a *domain.TaskActivity) bool { return a.TaskID.Hex() == taskID }), nil
+}
+
+type memAudit struct {
+	rows rows[domain.AuditEntry]
+}
+
+func (r *memAudit) Create(ctx context.Context, entry *domain.AuditEntry) error {
+	entry.ID = bson.NewObjectID()
+	copied := *entry
+	r.rows[entry.ID] = &copied
+	return nil
+}
+
+func (r *memAudit) Find(ctx context.Context, filter domain.AuditFilter) (*domain.AuditPage, error) {
+	entries := r.rows.where(func(e *domain.AuditEntry) bool {
+		return filter.ProjectID == "" || e.ProjectID.Hex() == filter.ProjectID
+	})
+	return &domain.AuditPage{Entries: entries}, nil
+}
+
+type memInvitations struct {
+	domain.InvitationRepository
+	rows rows[domain.Invitation]
+}
+
+func (r *memInvitations) Create(ctx context.Context, invitation *domain.Invitation) error {
+	invitation.ID = bson.NewObjectID()
+	copied := *invitation
+	r.rows[invitation.ID] = &copied
+	return nil
+}
+
+func (r *memInvitations) FindByID(ctx context.Context, id string) (*domain.Invitation, error) {
+	return r.rows.find(id)
+}
+
+func (r *memInvitations) FindPendingByProjectID(ctx context.Context, projectID string, now time.Time) ([]domain.Invitation, error) {
+	return r.rows.where(func(inv *domain.Invitation) bool {
+		return inv.ProjectID.Hex() == projectID && inv.Status == domain.InvitationPending && !inv.Expired(now)
+	}), nil
+}
+
+func (r *memInvitations) FindPendingByEmail(ctx context.Context, email string, now time.Time) ([]domain.Invitation, error) {
+	return r.rows.where(func(inv *domain.Invitation) bool {
+		return inv.Email == email && inv.Status == domain.InvitationPending && !inv.Expired(now)
+	}), nil
+}
+
+func (r *memInvitations) Update(ctx context.Context, invitation *domain.Invitation) error {
+	copied := *invitation
+	r.rows[invitation.ID] = &copied
+	return nil
+}
+
+func (r *memInvitations) Delete(ctx context.Context, id string) error {
+	return r.rows.remove(id)
+}
+
+type memJoinLinks struct {
+	domain.JoinLinkRepository
+	rows rows[domain.JoinLink]
+	uses rows[domain.JoinLinkUse]
+}
+
+func (r *memJoinLinks) Create(ctx context.Context, link *domain.JoinLink) error {
+	link.ID = bson.NewObjectID()
+	copied := *link
+	r.rows[link.ID] = &copied
+	return nil
+}
+
+func (r *memJoinLinks) FindByID(ctx context.Context, id string) (*domain.JoinLink, error) {
+	return r.rows.find(id)
+}
+
+func (r *memJoinLinks) FindByProjectID(ctx context.Context, projectID string) ([]domain.JoinLink, error) {
+	return r.rows.where(func(l *domain.JoinLink) bool { return l.ProjectID.Hex() == projectID }), nil
+}
+
+func (r *memJoinLinks) Revoke(ctx context.Context, id bson.ObjectID, now time.Time) error {
+	l, ok := r.rows[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	l.RevokedAt = &now
+	return nil
+}
+
+func (r *memJoinLinks) FindUses(ctx context.Context, linkID string) ([]domain.JoinLinkUse, error) {
+	return r.uses.where(func(u *domain.JoinLinkUse) bool { return u.LinkID.Hex() == linkID }), nil
+}
+
+type memWebhooks struct {
+	domain.WebhookRepository
+	rows rows[domain.Webhook]
+}
+
+func (r *memWebhooks) Create(ctx context.Context, hook *domain.Webhook) error {
+	hook.ID = bson.NewObjectID()
+	copied := *hook
+	r.rows[hook.ID] = &copied
+	return nil
+}
+
+func (r *memWebhooks) FindByID(ctx context.Context, id string) (*domain.Webhook, error) {
+	return r.rows.find(id)
+}
+
+func (r *memWebhooks) FindByProjectID(ctx context.Context, projectID string) ([]domain.Webhook, error) {
+	return r.rows.where(func(h *domain.Webhook) bool { return h.ProjectID.Hex() == projectID }), nil
+}
+
+func (r *memWebhooks) Update(ctx context.Context, hook *domain.Webhook) error {
+	copied := *hook
+	r.rows[hook.ID] = &copied
+	return nil
+}
+
+func (r *memWebhooks) Delete(ctx context.Context, id string) error {
+	return r.rows.remove(id)
+}
+
+type memDeliveries struct {
+	domain.WebhookDeliveryRepository
+	rows rows[domain.WebhookDelivery]
+}
+
+func (r *memDeliveries) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	delivery.ID = bson.NewObjectID()
+	copied := *delivery
+	r.rows[delivery.ID] = &copied
+	return nil
+}
+
+func (r *memDeliveries) FindByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	return r.rows.find(id)
+}
+
+func (r *memDeliveries) FindByWebhookID(ctx context.Context, webhookID string, page domain.PageRequest) (*domain.Page[domain.WebhookDelivery], error) {
+	items := r.rows.where(func(d *domain.WebhookDelivery) bool { return d.WebhookID.Hex() == webhookID })
+	return &domain.Page[domain.WebhookDelivery]{Items: items}, nil
+}
+
+func (r *memDeliveries) DeleteByWebhookID(ctx context.Context, webhookID string) error {
+	for id, d := range r.rows {
+		if d.WebhookID.Hex() == webhookID {
+			delete(r.rows, id)
+		}
+	}
+	return nil
+}
\ No newline at end of file
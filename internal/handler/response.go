@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/0DayMonxrch/project-management-system/internal/domain"
 )
@@ -15,6 +17,12 @@ func writeJSON(w http.ResponseWriter, status int, data any) {
 }
 
 func writeError(w http.ResponseWriter, err error) {
+	var retry *domain.RetryAfterError
+	if errors.As(err, &retry) {
+		// Round up so a client that waits exactly this long isn't rejected
+		w.Header().Set("Retry-After", strconv.Itoa(int((retry.Wait+time.Second-1)/time.Second)))
+	}
+
 	switch {
 	case errors.Is(err, domain.ErrNotFound):
 		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
@@ -32,6 +40,16 @@ func writeError(w http.ResponseWriter, err error) {
 		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
 	case errors.Is(err, domain.ErrEmailNotVerified):
 		writeJSON(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrFileTooLarge):
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrUnsupportedMedia):
+		writeJSON(w, http.StatusUnsupportedMediaType, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidTransition):
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrAccountLocked):
+		writeJSON(w, http.StatusLocked, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrTooManyAttempts):
+		writeJSON(w, http.StatusTooManyRequests, map[string]string{"error": err.Error()})
 	default:
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
 	}
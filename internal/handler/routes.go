@@ -3,63 +3,180 @@ package handler
 import (
 	"net/http"
 
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/jwtkeys"
 	"github.com/0DayMonxrch/project-management-system/internal/middleware"
 )
 
 func RegisterRoutes(
 	mux *http.ServeMux,
 	auth *AuthHandler,
+	account *AccountHandler,
 	project *ProjectHandler,
+	invitation *InvitationHandler,
+	joinLink *JoinLinkHandler,
 	task *TaskHandler,
 	note *NoteHandler,
-	jwtSecret string,
+	attachment *AttachmentHandler,
+	comment *CommentHandler,
+	audit *AuditHandler,
+	search *SearchHandler,
+	events *EventHandler,
+	webhook *WebhookHandler,
+	tokens *AccessTokenHandler,
+	tokenSvc domain.AccessTokenService,
+	versions domain.TokenVersionCache,
+	sessions domain.SessionCache,
+	projects domain.ProjectRepository,
+	keys *jwtkeys.KeySet,
 ) {
-	protected := middleware.Authenticate(jwtSecret)
+	protected := middleware.Authenticate(keys, tokenSvc, versions, sessions)
+	// session is for routes personal access tokens must not reach
+	session := func(h http.HandlerFunc) http.Handler {
+		return protected(middleware.RejectAccessTokens(h))
+	}
+	// member is for routes under a {projectId}; only its members get through
+	projectAccess := middleware.ProjectAccess(projects)
+	member := func(h http.HandlerFunc) http.Handler {
+		return protected(projectAccess(h))
+	}
+	// scoped is for user-level routes that tokens limited to some projects
+	// may still call; every other route without a {projectId} refuses them
+	scoped := func(h http.HandlerFunc) http.Handler {
+		return middleware.AllowScopedTokens(protected(h))
+	}
 
 	// Health check
 	mux.HandleFunc("GET /api/v1/healthcheck/", func(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 	})
 
+	// Public keys for verifying access tokens
+	mux.HandleFunc("GET /.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		writeJSON(w, http.StatusOK, keys.JWKS())
+	})
+
 	// Auth routes
 	mux.HandleFunc("POST /api/v1/auth/register", auth.Register)
 	mux.HandleFunc("POST /api/v1/auth/login", auth.Login)
+	mux.HandleFunc("POST /api/v1/auth/login/mfa", auth.VerifyMFA)
+	mux.HandleFunc("GET /api/v1/auth/methods", auth.LoginMethods)
+	mux.HandleFunc("GET /api/v1/auth/oidc/{provider}/login", auth.StartOIDCLogin)
+	mux.HandleFunc("GET /api/v1/auth/oidc/{provider}/callback", auth.OIDCCallback)
 	mux.HandleFunc("POST /api/v1/auth/refresh-token", auth.RefreshToken)
 	mux.HandleFunc("GET /api/v1/auth/verify-email/{verificationToken}", auth.VerifyEmail)
 	mux.HandleFunc("POST /api/v1/auth/forgot-password", auth.ForgotPassword)
 	mux.HandleFunc("POST /api/v1/auth/reset-password/{resetToken}", auth.ResetPassword)
+	mux.HandleFunc("GET /api/v1/auth/unlock/{unlockToken}", auth.UnlockAccount)
+	mux.HandleFunc("GET /api/v1/auth/confirm-email-change/{emailChangeToken}", auth.ConfirmEmailChange)
 
 	// Auth routes (protected)
-	mux.Handle("POST /api/v1/auth/logout", protected(http.HandlerFunc(auth.Logout)))
-	mux.Handle("GET /api/v1/auth/current-user", protected(http.HandlerFunc(auth.GetCurrentUser)))
-	mux.Handle("POST /api/v1/auth/change-password", protected(http.HandlerFunc(auth.ChangePassword)))
+	mux.Handle("POST /api/v1/auth/logout", session(auth.Logout))
+	mux.Handle("GET /api/v1/auth/current-user", scoped(auth.GetCurrentUser))
+	mux.Handle("POST /api/v1/auth/change-password", session(auth.ChangePassword))
+	mux.Handle("POST /api/v1/auth/change-email", session(auth.RequestEmailChange))
+	mux.Handle("POST /api/v1/auth/oidc/{provider}/link", session(auth.StartOIDCLink))
 	mux.Handle("POST /api/v1/auth/resend-email-verification", protected(http.HandlerFunc(auth.ResendVerificationEmail)))
 
+	// Two-factor routes (interactive sessions only)
+	mux.Handle("POST /api/v1/auth/2fa/totp", session(auth.EnrollTOTP))
+	mux.Handle("POST /api/v1/auth/2fa/totp/confirm", session(auth.ConfirmTOTP))
+	mux.Handle("POST /api/v1/auth/2fa/totp/disable", session(auth.DisableTOTP))
+	mux.Handle("POST /api/v1/auth/2fa/recovery-codes", session(auth.RegenerateRecoveryCodes))
+
+	// Session routes (interactive sessions only)
+	mux.Handle("GET /api/v1/auth/sessions", session(auth.ListSessions))
+	mux.Handle("DELETE /api/v1/auth/sessions/{sessionId}", session(auth.RevokeSession))
+
+	// Account data routes (interactive sessions only)
+	mux.Handle("GET /api/v1/auth/account/export", session(account.ExportData))
+	mux.Handle("DELETE /api/v1/auth/account", session(account.DeleteAccount))
+
+	// Personal access token routes (interactive sessions only)
+	mux.Handle("GET /api/v1/auth/tokens", session(tokens.ListTokens))
+	mux.Handle("POST /api/v1/auth/tokens", session(tokens.CreateToken))
+	mux.Handle("DELETE /api/v1/auth/tokens/{tokenId}", session(tokens.RevokeToken))
+
 	// Project routes (protected)
 	mux.Handle("GET /api/v1/projects/", protected(http.HandlerFunc(project.ListProjects)))
 	mux.Handle("POST /api/v1/projects/", protected(http.HandlerFunc(project.CreateProject)))
-	mux.Handle("GET /api/v1/projects/{projectId}", protected(http.HandlerFunc(project.GetProject)))
-	mux.Handle("PUT /api/v1/projects/{projectId}", protected(http.HandlerFunc(project.UpdateProject)))
-	mux.Handle("DELETE /api/v1/projects/{projectId}", protected(http.HandlerFunc(project.DeleteProject)))
-	mux.Handle("GET /api/v1/projects/{projectId}/members", protected(http.HandlerFunc(project.ListMembers)))
-	mux.Handle("POST /api/v1/projects/{projectId}/members", protected(http.HandlerFunc(project.AddMember)))
-	mux.Handle("PUT /api/v1/projects/{projectId}/members/{userId}", protected(http.HandlerFunc(project.UpdateMemberRole)))
-	mux.Handle("DELETE /api/v1/projects/{projectId}/members/{userId}", protected(http.HandlerFunc(project.RemoveMember)))
-
-	// Task routes (protected)
-	mux.Handle("GET /api/v1/tasks/{projectId}", protected(http.HandlerFunc(task.ListTasks)))
-	mux.Handle("POST /api/v1/tasks/{projectId}", protected(http.HandlerFunc(task.CreateTask)))
-	mux.Handle("GET /api/v1/tasks/{projectId}/t/{taskId}", protected(http.HandlerFunc(task.GetTask)))
-	mux.Handle("PUT /api/v1/tasks/{projectId}/t/{taskId}", protected(http.HandlerFunc(task.UpdateTask)))
-	mux.Handle("DELETE /api/v1/tasks/{projectId}/t/{taskId}", protected(http.HandlerFunc(task.DeleteTask)))
-	mux.Handle("POST /api/v1/tasks/{projectId}/t/{taskId}/subtasks", protected(http.HandlerFunc(task.CreateSubTask)))
-	mux.Handle("PUT /api/v1/tasks/{projectId}/st/{subTaskId}", protected(http.HandlerFunc(task.UpdateSubTask)))
-	mux.Handle("DELETE /api/v1/tasks/{projectId}/st/{subTaskId}", protected(http.HandlerFunc(task.DeleteSubTask)))
-
-	// Note routes (protected)
-	mux.Handle("GET /api/v1/notes/{projectId}", protected(http.HandlerFunc(note.ListNotes)))
-	mux.Handle("POST /api/v1/notes/{projectId}", protected(http.HandlerFunc(note.CreateNote)))
-	mux.Handle("GET /api/v1/notes/{projectId}/n/{noteId}", protected(http.HandlerFunc(note.GetNote)))
-	mux.Handle("PUT /api/v1/notes/{projectId}/n/{noteId}", protected(http.HandlerFunc(note.UpdateNote)))
-	mux.Handle("DELETE /api/v1/notes/{projectId}/n/{noteId}", protected(http.HandlerFunc(note.DeleteNote)))
+	mux.Handle("GET /api/v1/projects/{projectId}", member(project.GetProject))
+	mux.Handle("PUT /api/v1/projects/{projectId}", member(project.UpdateProject))
+	mux.Handle("DELETE /api/v1/projects/{projectId}", member(project.DeleteProject))
+	mux.Handle("GET /api/v1/projects/{projectId}/members", member(project.ListMembers))
+	mux.Handle("POST /api/v1/projects/{projectId}/members", member(project.AddMember))
+	mux.Handle("PUT /api/v1/projects/{projectId}/members/{userId}", member(project.UpdateMemberRole))
+	mux.Handle("DELETE /api/v1/projects/{projectId}/members/{userId}", member(project.RemoveMember))
+	mux.Handle("POST /api/v1/projects/{projectId}/transfer-ownership", member(project.TransferOwnership))
+	mux.Handle("GET /api/v1/projects/{projectId}/roles", member(project.ListRoles))
+	mux.Handle("PUT /api/v1/projects/{projectId}/roles/{role}", member(project.SaveRole))
+	mux.Handle("DELETE /api/v1/projects/{projectId}/roles/{role}", member(project.DeleteRole))
+	mux.Handle("GET /api/v1/projects/{projectId}/workflow", member(project.GetWorkflow))
+	mux.Handle("PUT /api/v1/projects/{projectId}/workflow", member(project.UpdateWorkflow))
+	mux.Handle("GET /api/v1/projects/{projectId}/audit", member(audit.ListProjectAudit))
+	mux.Handle("GET /api/v1/projects/{projectId}/events", member(events.StreamEvents))
+
+	// Invitation routes (protected)
+	mux.Handle("GET /api/v1/projects/{projectId}/invitations", member(invitation.ListInvitations))
+	mux.Handle("POST /api/v1/projects/{projectId}/invitations", member(invitation.Invite))
+	mux.Handle("DELETE /api/v1/projects/{projectId}/invitations/{invitationId}", member(invitation.RevokeInvitation))
+	mux.HandleFunc("GET /api/v1/invitations/{invitationToken}", invitation.GetInvitation)
+	mux.Handle("POST /api/v1/invitations/{invitationToken}/accept", session(invitation.AcceptInvitation))
+
+	// Join link routes (protected)
+	mux.Handle("GET /api/v1/projects/{projectId}/join-links", member(joinLink.ListJoinLinks))
+	mux.Handle("POST /api/v1/projects/{projectId}/join-links", member(joinLink.CreateJoinLink))
+	mux.Handle("DELETE /api/v1/projects/{projectId}/join-links/{linkId}", member(joinLink.RevokeJoinLink))
+	mux.Handle("GET /api/v1/projects/{projectId}/join-links/{linkId}/uses", member(joinLink.ListJoinLinkUses))
+	mux.Handle("POST /api/v1/join/{joinToken}", session(joinLink.Join))
+
+	// Webhook routes (project members)
+	mux.Handle("GET /api/v1/projects/{projectId}/webhooks", member(webhook.ListWebhooks))
+	mux.Handle("POST /api/v1/projects/{projectId}/webhooks", member(webhook.CreateWebhook))
+	mux.Handle("GET /api/v1/projects/{projectId}/webhooks/{webhookId}", member(webhook.GetWebhook))
+	mux.Handle("PUT /api/v1/projects/{projectId}/webhooks/{webhookId}", member(webhook.UpdateWebhook))
+	mux.Handle("DELETE /api/v1/projects/{projectId}/webhooks/{webhookId}", member(webhook.DeleteWebhook))
+	mux.Handle("GET /api/v1/projects/{projectId}/webhooks/{webhookId}/deliveries", member(webhook.ListDeliveries))
+	mux.Handle("POST /api/v1/projects/{projectId}/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver", member(webhook.Redeliver))
+
+	// Audit routes (protected, global admins only)
+	mux.Handle("GET /api/v1/audit", protected(http.HandlerFunc(audit.ListAudit)))
+
+	// Admin routes (interactive sessions only, global admins only)
+	mux.Handle("POST /api/v1/admin/users/{userId}/unlock", session(auth.AdminUnlockAccount))
+	mux.Handle("GET /api/v1/admin/projects/orphaned", session(project.ListOrphanedProjects))
+	mux.Handle("POST /api/v1/admin/projects/{projectId}/recover", session(project.RecoverProject))
+
+	// Search routes (protected)
+	mux.Handle("GET /api/v1/search", protected(http.HandlerFunc(search.Search)))
+
+	// Task routes (project members)
+	mux.Handle("GET /api/v1/tasks/{projectId}", member(task.ListTasks))
+	mux.Handle("POST /api/v1/tasks/{projectId}", member(task.CreateTask))
+	mux.Handle("GET /api/v1/tasks/{projectId}/t/{taskId}", member(task.GetTask))
+	mux.Handle("PUT /api/v1/tasks/{projectId}/t/{taskId}", member(task.UpdateTask))
+	mux.Handle("DELETE /api/v1/tasks/{projectId}/t/{taskId}", member(task.DeleteTask))
+	mux.Handle("POST /api/v1/tasks/{projectId}/t/{taskId}/subtasks", member(task.CreateSubTask))
+	mux.Handle("GET /api/v1/tasks/{projectId}/t/{taskId}/activity", member(task.ListActivity))
+	mux.Handle("PUT /api/v1/tasks/{projectId}/st/{subTaskId}", member(task.UpdateSubTask))
+	mux.Handle("DELETE /api/v1/tasks/{projectId}/st/{subTaskId}", member(task.DeleteSubTask))
+
+	// Attachment routes (project members)
+	mux.Handle("POST /api/v1/tasks/{projectId}/t/{taskId}/attachments", member(attachment.UploadAttachment))
+	mux.Handle("GET /api/v1/tasks/{projectId}/t/{taskId}/attachments/{attachmentId}", member(attachment.DownloadAttachment))
+	mux.Handle("DELETE /api/v1/tasks/{projectId}/t/{taskId}/attachments/{attachmentId}", member(attachment.DeleteAttachment))
+
+	// Comment routes (project members)
+	mux.Handle("GET /api/v1/tasks/{projectId}/t/{taskId}/comments", member(comment.ListComments))
+	mux.Handle("POST /api/v1/tasks/{projectId}/t/{taskId}/comments", member(comment.CreateComment))
+	mux.Handle("PUT /api/v1/tasks/{projectId}/t/{taskId}/comments/{commentId}", member(comment.UpdateComment))
+	mux.Handle("DELETE /api/v1/tasks/{projectId}/t/{taskId}/comments/{commentId}", member(comment.DeleteComment))
+
+	// Note routes (project members)
+	mux.Handle("GET /api/v1/notes/{projectId}", member(note.ListNotes))
+	mux.Handle("POST /api/v1/notes/{projectId}", member(note.CreateNote))
+	mux.Handle("GET /api/v1/notes/{projectId}/n/{noteId}", member(note.GetNote))
+	mux.Handle("PUT /api/v1/notes/{projectId}/n/{noteId}", member(note.UpdateNote))
+	mux.Handle("DELETE /api/v1/notes/{projectId}/n/{noteId}", member(note.DeleteNote))
 }
\ No newline at end of file
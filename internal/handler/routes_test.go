@@ -0,0 +1,428 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/config"
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/events"
+	"github.com/0DayMonxrch/project-management-system/internal/jwtkeys"
+	"github.com/0DayMonxrch/project-management-system/internal/service"
+	"github.com/0DayMonxrch/project-management-system/internal/storage"
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type nopEmail struct{ domain.EmailService }
+
+func (nopEmail) SendInvitationEmail(to, projectName, token string) error { return nil }
+
+type fixedVersions struct{}
+
+func (fixedVersions) Version(ctx context.Context, userID string) (int, error) { return 0, nil }
+func (fixedVersions) Invalidate(userID string)                                {}
+
+type liveSessions struct{}
+
+func (liveSessions) Active(ctx context.Context, userID, sessionID string) (bool, error) {
+	return true, nil
+}
+func (liveSessions) Invalidate(sessionID string)  {}
+func (liveSessions) InvalidateUser(userID string) {}
+
+// routeFixture serves RegisterRoutes over in-memory repositories holding
+// two projects, P and Q, each with one of everything the project routes
+// look up.
+type routeFixture struct {
+	mux  *http.ServeMux
+	keys *jwtkeys.KeySet
+	// ids and otherIDs fill the path placeholders with P's and Q's objects
+	ids, otherIDs map[string]string
+	comments      *memComments
+
+	admin, allowed, denied, outsider string
+}
+
+// newRouteFixture gives allowed a role holding grant, and denied one
+// holding grant without need.
+func newRouteFixture(t *testing.T, grant []domain.Permission, need domain.Permission) *routeFixture {
+	t.Helper()
+	users := &memUsers{rows: rows[domain.User]{}}
+	projects := &memProjects{rows: rows[domain.Project]{}}
+	tasks := &memTasks{rows: rows[domain.Task]{}}
+	notes := &memNotes{rows: rows[domain.Note]{}}
+	comments := &memComments{rows: rows[domain.Comment]{}}
+	activity := &memActivity{rows: rows[domain.TaskActivity]{}}
+	audit := &memAudit{rows: rows[domain.AuditEntry]{}}
+	invitations := &memInvitations{rows: rows[domain.Invitation]{}}
+	links := &memJoinLinks{rows: rows[domain.JoinLink]{}, uses: rows[domain.JoinLinkUse]{}}
+	hooks := &memWebhooks{rows: rows[domain.Webhook]{}}
+	deliveries := &memDeliveries{rows: rows[domain.WebhookDelivery]{}}
+	blobs, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newUser := func(email string) bson.ObjectID {
+		u := &domain.User{ID: bson.NewObjectID(), Email: email}
+		users.rows[u.ID] = u
+		return u.ID
+	}
+	f := &routeFixture{comments: comments}
+	admin, allowed, denied := newUser("admin@example.com"), newUser("allowed@example.com"), newUser("denied@example.com")
+	f.admin, f.allowed, f.denied = admin.Hex(), allowed.Hex(), denied.Hex()
+	f.outsider = newUser("outsider@example.com").Hex()
+	newUser("newcomer@example.com")
+
+	// seed creates a project and one object of each kind in it
+	seed := func(admin bson.ObjectID, members ...domain.ProjectMember) map[string]string {
+		target := bson.NewObjectID()
+		users.rows[target] = &domain.User{ID: target, Email: target.Hex() + "@example.com"}
+		p := &domain.Project{
+			ID:        bson.NewObjectID(),
+			Name:      "project",
+			Members:   append([]domain.ProjectMember{{UserID: admin, Role: domain.RoleAdmin}, {UserID: target, Role: domain.RoleMember}}, members...),
+			Workflow:  domain.DefaultWorkflow(),
+			Roles:     []domain.ProjectRole{{Name: "reviewer", Permissions: []domain.Permission{domain.PermProjectView}}},
+			CreatedBy: admin,
+		}
+		projects.rows[p.ID] = p
+
+		attachment := domain.Attachment{ID: bson.NewObjectID(), Name: "a.txt", MimeType: "text/plain; charset=utf-8", UploadedBy: admin}
+		attachment.StorageKey = p.ID.Hex() + "/" + attachment.ID.Hex()
+		if err := blobs.Save(context.Background(), attachment.StorageKey, strings.NewReader("hello")); err != nil {
+			t.Fatal(err)
+		}
+		task := &domain.Task{
+			ID:          bson.NewObjectID(),
+			ProjectID:   p.ID,
+			Title:       "task",
+			Status:      p.Workflow.Statuses[0],
+			Attachments: []domain.Attachment{attachment},
+			SubTasks:    []domain.SubTask{{ID: bson.NewObjectID(), Title: "subtask"}},
+			CreatedBy:   admin,
+		}
+		tasks.rows[task.ID] = task
+		note := &domain.Note{ID: bson.NewObjectID(), ProjectID: p.ID, Title: "note", CreatedBy: admin}
+		notes.rows[note.ID] = note
+		comment := &domain.Comment{ID: bson.NewObjectID(), ProjectID: p.ID, TaskID: task.ID, Content: "comment", CreatedBy: admin}
+		comments.rows[comment.ID] = comment
+		hook := &domain.Webhook{ID: bson.NewObjectID(), ProjectID: p.ID, URL: "https://hooks.example.com/x", Events: []domain.EventType{domain.EventTaskCreated}, Active: true, CreatedBy: admin}
+		hooks.rows[hook.ID] = hook
+		delivery := &domain.WebhookDelivery{ID: bson.NewObjectID(), WebhookID: hook.ID, ProjectID: p.ID, EventType: domain.EventTaskCreated, Status: domain.DeliveryFailed}
+		deliveries.rows[delivery.ID] = delivery
+		invitation := &domain.Invitation{ID: bson.NewObjectID(), ProjectID: p.ID, Email: "invited@example.com", Role: domain.RoleMember, Status: domain.InvitationPending, InvitedBy: admin, ExpiresAt: time.Now().Add(time.Hour)}
+		invitations.rows[invitation.ID] = invitation
+		link := &domain.JoinLink{ID: bson.NewObjectID(), ProjectID: p.ID, Role: domain.RoleMember, CreatedBy: admin}
+		links.rows[link.ID] = link
+
+		return map[string]string{
+			"projectId":    p.ID.Hex(),
+			"userId":       target.Hex(),
+			"taskId":       task.ID.Hex(),
+			"subTaskId":    task.SubTasks[0].ID.Hex(),
+			"attachmentId": attachment.ID.Hex(),
+			"noteId":       note.ID.Hex(),
+			"commentId":    comment.ID.Hex(),
+			"webhookId":    hook.ID.Hex(),
+			"deliveryId":   delivery.ID.Hex(),
+			"invitationId": invitation.ID.Hex(),
+			"linkId":       link.ID.Hex(),
+		}
+	}
+	f.ids = seed(admin,
+		domain.ProjectMember{UserID: allowed, Role: "granted"},
+		domain.ProjectMember{UserID: denied, Role: "withheld"},
+	)
+	p := projects.rows[mustOID(t, f.ids["projectId"])]
+	p.Roles = append(p.Roles,
+		domain.ProjectRole{Name: "granted", Permissions: grant},
+		domain.ProjectRole{Name: "withheld", Permissions: slices.DeleteFunc(slices.Clone(grant), func(perm domain.Permission) bool { return perm == need })},
+	)
+	f.otherIDs = seed(bson.NewObjectID())
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	bus := events.NewMemoryBus(16)
+	upload := config.UploadConfig{MaxSizeMB: 1, AllowedTypes: []string{"text/plain; charset=utf-8"}}
+	f.keys, err = jwtkeys.Load(config.JWTConfig{AccessSecret: "access"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.mux = http.NewServeMux()
+	RegisterRoutes(f.mux,
+		nil, nil,
+		NewProjectHandler(service.NewProjectService(projects, users, tasks, invitations, links, audit, bus)),
+		NewInvitationHandler(service.NewInvitationService(invitations, projects, users, audit, nopEmail{}, bus)),
+		NewJoinLinkHandler(service.NewJoinLinkService(links, projects, audit, bus, log)),
+		NewTaskHandler(service.NewTaskService(tasks, projects, activity, comments, blobs, bus, log)),
+		NewNoteHandler(service.NewNoteService(notes, projects, audit, bus)),
+		NewAttachmentHandler(service.NewAttachmentService(tasks, projects, activity, blobs, upload, bus, log), upload.MaxSizeMB),
+		NewCommentHandler(service.NewCommentService(comments, tasks, projects, users)),
+		NewAuditHandler(service.NewAuditService(audit, projects, users)),
+		nil,
+		NewEventHandler(service.NewEventService(projects, bus)),
+		NewWebhookHandler(service.NewWebhookService(hooks, deliveries, projects, audit)),
+		nil, nil,
+		fixedVersions{}, liveSessions{},
+		projects,
+		f.keys,
+	)
+	return f
+}
+
+func mustOID(t *testing.T, hex string) bson.ObjectID {
+	t.Helper()
+	oid, err := bson.ObjectIDFromHex(hex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return oid
+}
+
+var placeholder = regexp.MustCompile(`\{(\w+)\}`)
+
+// do calls route, a ServeMux pattern, as userID, filling the placeholders
+// in its path and body from vars and then ids.
+func (f *routeFixture) do(t *testing.T, userID, route string, ids, vars map[string]string, body, contentType string) *httptest.ResponseRecorder {
+	t.Helper()
+	fill := func(s string) string {
+		return placeholder.ReplaceAllStringFunc(s, func(m string) string {
+			name := m[1 : len(m)-1]
+			if v, ok := vars[name]; ok {
+				return v
+			}
+			return ids[name]
+		})
+	}
+	method, path, _ := strings.Cut(route, " ")
+
+	token, err := f.keys.Sign(jwt.MapClaims{"sub": userID, "sid": "session", "ver": 0, "exp": time.Now().Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The event stream only ends when the client goes away
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	r := httptest.NewRequestWithContext(ctx, method, fill(path), strings.NewReader(fill(body)))
+	r.Header.Set("Authorization", "Bearer "+token)
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	w := httptest.NewRecorder()
+	f.mux.ServeHTTP(w, r)
+	return w
+}
+
+type routeCase struct {
+	route       string
+	body        string
+	contentType string
+	// grant is what the allowed caller's role holds; the denied caller's
+	// role holds the same without need
+	grant  []domain.Permission
+	need   domain.Permission
+	status int
+	vars   map[string]string
+	setup  func(f *routeFixture, callerID string)
+}
+
+func routeCases(t *testing.T) []routeCase {
+	// Handing out a role, or changing a member who holds one, takes every
+	// permission that role carries
+	memberPerms := slices.Concat(domain.BuiltinRoles[2].Permissions, []domain.Permission{domain.PermMemberManage})
+	workflow, err := json.Marshal(domain.DefaultWorkflow())
+	if err != nil {
+		t.Fatal(err)
+	}
+	upload, uploadType := multipartBody(t, "file", "notes.txt", "hello world")
+	webhook := fmt.Sprintf(`{"url":"https://hooks.example.com/x","events":[%q]}`, domain.EventMemberAdded)
+	authored := func(f *routeFixture, callerID string) {
+		c := f.comments.rows[mustOID(t, f.ids["commentId"])]
+		c.CreatedBy = mustOID(t, callerID)
+	}
+	perms := func(p ...domain.Permission) []domain.Permission { return p }
+
+	return []routeCase{
+		{route: "GET /api/v1/projects/{projectId}", grant: perms(domain.PermProjectView), need: domain.PermProjectView},
+		{route: "PUT /api/v1/projects/{projectId}", body: `{"name":"renamed"}`, grant: perms(domain.PermProjectUpdate), need: domain.PermProjectUpdate},
+		{route: "DELETE /api/v1/projects/{projectId}", grant: perms(domain.PermProjectDelete), need: domain.PermProjectDelete},
+		{route: "GET /api/v1/projects/{projectId}/members", grant: perms(domain.PermProjectView), need: domain.PermProjectView},
+		{route: "POST /api/v1/projects/{projectId}/members", body: `{"email":"newcomer@example.com","role":"member"}`, grant: memberPerms, need: domain.PermMemberManage},
+		{route: "PUT /api/v1/projects/{projectId}/members/{userId}", body: `{"role":"reviewer"}`, grant: memberPerms, need: domain.PermMemberManage},
+		{route: "DELETE /api/v1/projects/{projectId}/members/{userId}", grant: memberPerms, need: domain.PermMemberManage},
+		{route: "POST /api/v1/projects/{projectId}/transfer-ownership", body: `{"user_id":"{userId}"}`, grant: domain.Permissions, need: domain.PermMemberManage},
+		{route: "GET /api/v1/projects/{projectId}/roles", grant: perms(domain.PermProjectView), need: domain.PermProjectView},
+		{route: "PUT /api/v1/projects/{projectId}/roles/{role}", body: `{"permissions":["project.view"]}`, grant: perms(domain.PermRoleManage, domain.PermProjectView), need: domain.PermRoleManage, vars: map[string]string{"role": "auditor"}},
+		{route: "DELETE /api/v1/projects/{projectId}/roles/{role}", grant: perms(domain.PermRoleManage, domain.PermProjectView), need: domain.PermRoleManage, vars: map[string]string{"role": "reviewer"}},
+		{route: "GET /api/v1/projects/{projectId}/workflow", grant: perms(domain.PermProjectView), need: domain.PermProjectView},
+		{route: "PUT /api/v1/projects/{projectId}/workflow", body: string(workflow), grant: perms(domain.PermProjectUpdate), need: domain.PermProjectUpdate},
+		{route: "GET /api/v1/projects/{projectId}/audit", grant: perms(domain.PermAuditView), need: domain.PermAuditView},
+		{route: "GET /api/v1/projects/{projectId}/events", grant: perms(domain.PermProjectView), need: domain.PermProjectView},
+
+		{route: "GET /api/v1/projects/{projectId}/invitations", grant: perms(domain.PermMemberManage), need: domain.PermMemberManage},
+		{route: "POST /api/v1/projects/{projectId}/invitations", body: `{"email":"invitee@example.com","role":"member"}`, grant: memberPerms, need: domain.PermMemberManage, status: http.StatusCreated},
+		{route: "DELETE /api/v1/projects/{projectId}/invitations/{invitationId}", grant: perms(domain.PermMemberManage), need: domain.PermMemberManage},
+
+		{route: "GET /api/v1/projects/{projectId}/join-links", grant: perms(domain.PermMemberManage), need: domain.PermMemberManage},
+		{route: "POST /api/v1/projects/{projectId}/join-links", body: `{"role":"member"}`, grant: memberPerms, need: domain.PermMemberManage, status: http.StatusCreated},
+		{route: "DELETE /api/v1/projects/{projectId}/join-links/{linkId}", grant: perms(domain.PermMemberManage), need: domain.PermMemberManage},
+		{route: "GET /api/v1/projects/{projectId}/join-links/{linkId}/uses", grant: perms(domain.PermMemberManage), need: domain.PermMemberManage},
+
+		{route: "GET /api/v1/projects/{projectId}/webhooks", grant: perms(domain.PermWebhookManage), need: domain.PermWebhookManage},
+		{route: "POST /api/v1/projects/{projectId}/webhooks", body: webhook, grant: perms(domain.PermWebhookManage, domain.PermProjectView), need: domain.PermWebhookManage, status: http.StatusCreated},
+		{route: "GET /api/v1/projects/{projectId}/webhooks/{webhookId}", grant: perms(domain.PermWebhookManage), need: domain.PermWebhookManage},
+		{route: "PUT /api/v1/projects/{projectId}/webhooks/{webhookId}", body: `{"active":false}`, grant: perms(domain.PermWebhookManage), need: domain.PermWebhookManage},
+		{route: "DELETE /api/v1/projects/{projectId}/webhooks/{webhookId}", grant: perms(domain.PermWebhookManage), need: domain.PermWebhookManage},
+		{route: "GET /api/v1/projects/{projectId}/webhooks/{webhookId}/deliveries", grant: perms(domain.PermWebhookManage), need: domain.PermWebhookManage},
+		{route: "POST /api/v1/projects/{projectId}/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver", grant: perms(domain.PermWebhookManage), need: domain.PermWebhookManage, status: http.StatusAccepted},
+
+		{route: "GET /api/v1/tasks/{projectId}", grant: perms(domain.PermTaskView), need: domain.PermTaskView},
+		{route: "POST /api/v1/tasks/{projectId}", body: `{"title":"new task","description":"text"}`, grant: perms(domain.PermTaskCreate), need: domain.PermTaskCreate, status: http.StatusCreated},
+		{route: "GET /api/v1/tasks/{projectId}/t/{taskId}", grant: perms(domain.PermTaskView), need: domain.PermTaskView},
+		{route: "PUT /api/v1/tasks/{projectId}/t/{taskId}", body: `{"title":"renamed"}`, grant: perms(domain.PermTaskUpdateAny), need: domain.PermTaskUpdateAny},
+		{route: "DELETE /api/v1/tasks/{projectId}/t/{taskId}", grant: perms(domain.PermTaskDelete), need: domain.PermTaskDelete},
+		{route: "POST /api/v1/tasks/{projectId}/t/{taskId}/subtasks", body: `{"title":"new subtask"}`, grant: perms(domain.PermTaskCreate), need: domain.PermTaskCreate, status: http.StatusCreated},
+		{route: "GET /api/v1/tasks/{projectId}/t/{taskId}/activity", grant: perms(domain.PermTaskView), need: domain.PermTaskView},
+		{route: "PUT /api/v1/tasks/{projectId}/st/{subTaskId}", body: `{"title":"renamed"}`, grant: perms(domain.PermTaskUpdateAny), need: domain.PermTaskUpdateAny},
+		{route: "DELETE /api/v1/tasks/{projectId}/st/{subTaskId}", grant: perms(domain.PermTaskDelete), need: domain.PermTaskDelete},
+
+		{route: "POST /api/v1/tasks/{projectId}/t/{taskId}/attachments", body: upload.String(), contentType: uploadType, grant: perms(domain.PermAttachmentWrite), need: domain.PermAttachmentWrite, status: http.StatusCreated},
+		{route: "GET /api/v1/tasks/{projectId}/t/{taskId}/attachments/{attachmentId}", grant: perms(domain.PermTaskView), need: domain.PermTaskView},
+		{route: "DELETE /api/v1/tasks/{projectId}/t/{taskId}/attachments/{attachmentId}", grant: perms(domain.PermTaskView, domain.PermAttachmentDeleteAny), need: domain.PermAttachmentDeleteAny},
+
+		{route: "GET /api/v1/tasks/{projectId}/t/{taskId}/comments", grant: perms(domain.PermTaskView), need: domain.PermTaskView},
+		{route: "POST /api/v1/tasks/{projectId}/t/{taskId}/comments", body: `{"content":"hi"}`, grant: perms(domain.PermCommentWrite), need: domain.PermCommentWrite, status: http.StatusCreated},
+		{route: "PUT /api/v1/tasks/{projectId}/t/{taskId}/comments/{commentId}", body: `{"content":"edited"}`, grant: perms(domain.PermCommentWrite), need: domain.PermCommentWrite, setup: authored},
+		{route: "DELETE /api/v1/tasks/{projectId}/t/{taskId}/comments/{commentId}", grant: perms(domain.PermTaskView, domain.PermCommentDeleteAny), need: domain.PermCommentDeleteAny},
+
+		{route: "GET /api/v1/notes/{projectId}", grant: perms(domain.PermNoteView), need: domain.PermNoteView},
+		{route: "POST /api/v1/notes/{projectId}", body: `{"title":"new note","content":"text"}`, grant: perms(domain.PermNoteWrite), need: domain.PermNoteWrite, status: http.StatusCreated},
+		{route: "GET /api/v1/notes/{projectId}/n/{noteId}", grant: perms(domain.PermNoteView), need: domain.PermNoteView},
+		{route: "PUT /api/v1/notes/{projectId}/n/{noteId}", body: `{"title":"renamed","content":"text"}`, grant: perms(domain.PermNoteWrite), need: domain.PermNoteWrite},
+		{route: "DELETE /api/v1/notes/{projectId}/n/{noteId}", grant: perms(domain.PermNoteWrite), need: domain.PermNoteWrite},
+	}
+}
+
+func TestProjectRoutesCheckTheCallersRole(t *testing.T) {
+	for _, tc := range routeCases(t) {
+		t.Run(tc.route, func(t *testing.T) {
+			want := tc.status
+			if want == 0 {
+				want = http.StatusOK
+			}
+			callers := []struct {
+				name   string
+				caller func(f *routeFixture) string
+				want   int
+			}{
+				{"non-member", func(f *routeFixture) string { return f.outsider }, http.StatusForbidden},
+				{"member without " + string(tc.need), func(f *routeFixture) string { return f.denied }, http.StatusForbidden},
+				{"member with it", func(f *routeFixture) string { return f.allowed }, want},
+			}
+			for _, c := range callers {
+				t.Run(c.name, func(t *testing.T) {
+					f := newRouteFixture(t, tc.grant, tc.need)
+					caller := c.caller(f)
+					if tc.setup != nil {
+						tc.setup(f, caller)
+					}
+					w := f.do(t, caller, tc.route, f.ids, tc.vars, tc.body, tc.contentType)
+					if w.Code != c.want {
+						t.Fatalf("status = %d, want %d: %s", w.Code, c.want, w.Body)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestProjectRoutesRefuseObjectsOfOtherProjects(t *testing.T) {
+	for _, tc := range routeCases(t) {
+		for _, m := range placeholder.FindAllStringSubmatch(tc.route, -1) {
+			name := m[1]
+			if name == "projectId" || name == "role" {
+				continue
+			}
+			t.Run(tc.route+"/"+name, func(t *testing.T) {
+				f := newRouteFixture(t, tc.grant, tc.need)
+				if tc.setup != nil {
+					tc.setup(f, f.admin)
+				}
+				ids := map[string]string{}
+				for k, v := range f.ids {
+					ids[k] = v
+				}
+				ids[name] = f.otherIDs[name]
+				w := f.do(t, f.admin, tc.route, ids, tc.vars, tc.body, tc.contentType)
+				if w.Code != http.StatusNotFound {
+					t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusNotFound, w.Body)
+				}
+			})
+		}
+	}
+}
+
+// TestEveryProjectRouteIsCovered keeps the tables above in step with
+// RegisterRoutes.
+func TestEveryProjectRouteIsCovered(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "routes.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var routes []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); !ok || sel.Sel.Name != "Handle" {
+			return true
+		}
+		wrap, ok := call.Args[1].(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if fn, ok := wrap.Fun.(*ast.Ident); !ok || fn.Name != "member" {
+			return true
+		}
+		if lit, ok := call.Args[0].(*ast.BasicLit); ok {
+			route, _ := strconv.Unquote(lit.Value)
+			routes = append(routes, route)
+		}
+		return true
+	})
+	if len(routes) == 0 {
+		t.Fatal("found no project routes in routes.go")
+	}
+
+	var covered []string
+	for _, tc := range routeCases(t) {
+		covered = append(covered, tc.route)
+	}
+	for _, route := range routes {
+		if !slices.Contains(covered, route) {
+			t.Errorf("%s has no test case", route)
+		}
+	}
+	for _, route := range covered {
+		if !slices.Contains(routes, route) {
+			t.Errorf("%s is not a project route", route)
+		}
+	}
+}
\ No newline at end of file
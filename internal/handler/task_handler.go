@@ -3,6 +3,7 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/0DayMonxrch/project-management-system/internal/domain"
 	"github.com/0DayMonxrch/project-management-system/internal/middleware"
@@ -49,8 +50,40 @@ func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *TaskHandler) ListTasks(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePageRequest(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	q := r.URL.Query()
+	query := domain.TaskQuery{
+		Status:     domain.TaskStatus(q.Get("status")),
+		AssignedTo: q.Get("assigned_to"),
+		CreatedBy:  q.Get("created_by"),
+		Due:        domain.DueWindow(q.Get("due")),
+	}
+
+	if query.DueFrom, _, err = parseDateParam(q.Get("due_from")); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "due_from: must be a date (YYYY-MM-DD) or RFC 3339 timestamp"})
+		return
+	}
+	dueTo, dateOnly, err := parseDateParam(q.Get("due_to"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "due_to: must be a date (YYYY-MM-DD) or RFC 3339 timestamp"})
+		return
+	}
+	// A plain date means "up to the end of that day"
+	if dateOnly {
+		dueTo = dueTo.AddDate(0, 0, 1)
+	} else if !dueTo.IsZero() {
+		dueTo = dueTo.Add(time.Nanosecond)
+	}
+	query.DueBefore = dueTo
+
+	userID, _ := middleware.GetUserID(r)
 	projectID := r.PathValue("projectId")
-	tasks, err := h.svc.ListTasks(r.Context(), projectID)
+	tasks, err := h.svc.ListTasks(r.Context(), projectID, userID, query, page)
 	if err != nil {
 		writeError(w, err)
 		return
@@ -59,10 +92,11 @@ func (h *TaskHandler) ListTasks(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *TaskHandler) GetTask(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
 	projectID := r.PathValue("projectId")
 	taskID := r.PathValue("taskId")
 
-	task, err := h.svc.GetTask(r.Context(), projectID, taskID)
+	task, err := h.svc.GetTask(r.Context(), projectID, taskID, userID)
 	if err != nil {
 		writeError(w, err)
 		return
@@ -78,9 +112,10 @@ func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
 	taskID := r.PathValue("taskId")
 
-	task, err := h.svc.UpdateTask(r.Context(), taskID, userID, body)
+	task, err := h.svc.UpdateTask(r.Context(), projectID, taskID, userID, body)
 	if err != nil {
 		writeError(w, err)
 		return
@@ -90,9 +125,10 @@ func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 
 func (h *TaskHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
 	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
 	taskID := r.PathValue("taskId")
 
-	if err := h.svc.DeleteTask(r.Context(), taskID, userID); err != nil {
+	if err := h.svc.DeleteTask(r.Context(), projectID, taskID, userID); err != nil {
 		writeError(w, err)
 		return
 	}
@@ -109,9 +145,10 @@ func (h *TaskHandler) CreateSubTask(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
 	taskID := r.PathValue("taskId")
 
-	task, err := h.svc.CreateSubTask(r.Context(), taskID, userID, body.Title)
+	task, err := h.svc.CreateSubTask(r.Context(), projectID, taskID, userID, body.Title)
 	if err != nil {
 		writeError(w, err)
 		return
@@ -120,19 +157,17 @@ func (h *TaskHandler) CreateSubTask(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *TaskHandler) UpdateSubTask(w http.ResponseWriter, r *http.Request) {
-	var body struct {
-		IsCompleted bool `json:"is_completed"`
-	}
+	var body map[string]any
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
 		return
 	}
 
 	userID, _ := middleware.GetUserID(r)
-	taskID := r.PathValue("taskId")
+	projectID := r.PathValue("projectId")
 	subTaskID := r.PathValue("subTaskId")
 
-	task, err := h.svc.UpdateSubTask(r.Context(), taskID, subTaskID, userID, body.IsCompleted)
+	task, err := h.svc.UpdateSubTask(r.Context(), projectID, subTaskID, userID, body)
 	if err != nil {
 		writeError(w, err)
 		return
@@ -142,12 +177,25 @@ func (h *TaskHandler) UpdateSubTask(w http.ResponseWriter, r *http.Request) {
 
 func (h *TaskHandler) DeleteSubTask(w http.ResponseWriter, r *http.Request) {
 	userID, _ := middleware.GetUserID(r)
-	taskID := r.PathValue("taskId")
+	projectID := r.PathValue("projectId")
 	subTaskID := r.PathValue("subTaskId")
 
-	if err := h.svc.DeleteSubTask(r.Context(), taskID, subTaskID, userID); err != nil {
+	if err := h.svc.DeleteSubTask(r.Context(), projectID, subTaskID, userID); err != nil {
 		writeError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, map[string]string{"message": "subtask deleted successfully"})
+}
+
+func (h *TaskHandler) ListActivity(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+	taskID := r.PathValue("taskId")
+
+	activity, err := h.svc.ListActivity(r.Context(), projectID, taskID, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, activity)
 }
\ No newline at end of file
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/middleware"
+	"github.com/0DayMonxrch/project-management-system/pkg/validator"
+)
+
+type WebhookHandler struct {
+	svc domain.WebhookService
+}
+
+func NewWebhookHandler(svc domain.WebhookService) *WebhookHandler {
+	return &WebhookHandler{svc: svc}
+}
+
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		URL    string             `json:"url"`
+		Events []domain.EventType `json:"events"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	if err := validator.New().
+		Required("url", body.URL).
+		PublicURL("url", body.URL).
+		MaxLength("url", body.URL, 2048).
+		Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+
+	hook, secret, err := h.svc.CreateWebhook(r.Context(), projectID, userID, body.URL, body.Events)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// The secret is only ever shown here
+	writeJSON(w, http.StatusCreated, struct {
+		*domain.Webhook
+		Secret string `json:"secret"`
+	}{hook, secret})
+}
+
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+
+	hooks, err := h.svc.ListWebhooks(r.Context(), projectID, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, hooks)
+}
+
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+	webhookID := r.PathValue("webhookId")
+
+	hook, err := h.svc.GetWebhook(r.Context(), projectID, webhookID, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, hook)
+}
+
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		URL    *string            `json:"url"`
+		Events []domain.EventType `json:"events"`
+		Active *bool              `json:"active"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	if body.URL != nil {
+		if err := validator.New().
+			PublicURL("url", *body.URL).
+			MaxLength("url", *body.URL, 2048).
+			Validate(); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+	}
+
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+	webhookID := r.PathValue("webhookId")
+
+	update := domain.WebhookUpdate{URL: body.URL, Events: body.Events, Active: body.Active}
+	hook, err := h.svc.UpdateWebhook(r.Context(), projectID, webhookID, userID, update)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, hook)
+}
+
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+	webhookID := r.PathValue("webhookId")
+
+	if err := h.svc.DeleteWebhook(r.Context(), projectID, webhookID, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "webhook deleted successfully"})
+}
+
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePageRequest(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+	webhookID := r.PathValue("webhookId")
+
+	deliveries, err := h.svc.ListDeliveries(r.Context(), projectID, webhookID, userID, page)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, deliveries)
+}
+
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	projectID := r.PathValue("projectId")
+	webhookID := r.PathValue("webhookId")
+	deliveryID := r.PathValue("deliveryId")
+
+	delivery, err := h.svc.Redeliver(r.Context(), projectID, webhookID, deliveryID, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, delivery)
+}
\ No newline at end of file
@@ -0,0 +1,189 @@
+// Package jwtkeys signs and verifies access tokens. Tokens are signed with
+// an RSA (RS256) or Ed25519 (EdDSA) key named in the kid header, and every
+// configured key is accepted for verification so keys can be rotated.
+// Without keys it falls back to HS256 with the shared access secret.
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/0DayMonxrch/project-management-system/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+type key struct {
+	id     string
+	method jwt.SigningMethod
+	public crypto.PublicKey
+	// private is nil for keys kept only to verify older tokens
+	private crypto.PrivateKey
+}
+
+type KeySet struct {
+	signing *key
+	keys    map[string]*key
+	secret  []byte
+}
+
+// JWK is a public key in RFC 7517 form.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// Load reads the keys in cfg. With no keys configured, tokens are signed
+// and verified with cfg.AccessSecret.
+func Load(cfg config.JWTConfig) (*KeySet, error) {
+	if len(cfg.Keys) == 0 {
+		if cfg.SigningKey != "" {
+			return nil, fmt.Errorf("signing key %q is not in jwt.keys", cfg.SigningKey)
+		}
+		return &KeySet{secret: []byte(cfg.AccessSecret)}, nil
+	}
+
+	ks := &KeySet{keys: make(map[string]*key)}
+	for _, kc := range cfg.Keys {
+		if kc.ID == "" {
+			return nil, errors.New("every jwt key needs an id")
+		}
+		if _, dup := ks.keys[kc.ID]; dup {
+			return nil, fmt.Errorf("duplicate jwt key id %q", kc.ID)
+		}
+		k, err := loadKey(kc)
+		if err != nil {
+			return nil, err
+		}
+		ks.keys[kc.ID] = k
+	}
+
+	ks.signing = ks.keys[cfg.SigningKey]
+	if ks.signing == nil {
+		return nil, fmt.Errorf("signing key %q is not in jwt.keys", cfg.SigningKey)
+	}
+	if ks.signing.private == nil {
+		return nil, fmt.Errorf("signing key %q has no private key", cfg.SigningKey)
+	}
+	return ks, nil
+}
+
+// Sign signs claims with the current signing key.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if ks.signing == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(ks.secret)
+	}
+	token := jwt.NewWithClaims(ks.signing.method, claims)
+	token.Header["kid"] = ks.signing.id
+	return token.SignedString(ks.signing.private)
+}
+
+// Parse verifies tokenStr and decodes it into claims.
+func (ks *KeySet) Parse(tokenStr string, claims jwt.Claims) (*jwt.Token, error) {
+	if ks.signing == nil {
+		return jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (any, error) {
+			return ks.secret, nil
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	}
+
+	return jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		k, ok := ks.keys[kid]
+		// Pin the algorithm to the key so one can't be swapped for another
+		if !ok || t.Method.Alg() != k.method.Alg() {
+			return nil, ErrUnknownKey
+		}
+		return k.public, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}))
+}
+
+// JWKS returns the public verification keys. It is empty when tokens are
+// signed with the shared secret.
+func (ks *KeySet) JWKS() JWKS {
+	doc := JWKS{Keys: []JWK{}}
+	for _, k := range ks.keys {
+		jwk := JWK{Kid: k.id, Use: "sig", Alg: k.method.Alg()}
+		switch pub := k.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		doc.Keys = append(doc.Keys, jwk)
+	}
+	slices.SortFunc(doc.Keys, func(a, b JWK) int { return strings.Compare(a.Kid, b.Kid) })
+	return doc
+}
+
+// --- helpers ---
+
+// loadKey reads a PEM file holding either a private key (PKCS #8, or
+// PKCS #1 for RSA) or, for verification-only keys, a PKIX public key.
+func loadKey(kc config.JWTKeyConfig) (*key, error) {
+	data, err := os.ReadFile(kc.File)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwt key %q: %w", kc.ID, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("jwt key %q is not PEM encoded", kc.ID)
+	}
+
+	k := &key{id: kc.ID}
+	var parsed any
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("jwt key %q has unsupported PEM type %q", kc.ID, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse jwt key %q: %w", kc.ID, err)
+	}
+
+	switch p := parsed.(type) {
+	case *rsa.PrivateKey:
+		k.method, k.private, k.public = jwt.SigningMethodRS256, p, &p.PublicKey
+	case ed25519.PrivateKey:
+		k.method, k.private, k.public = jwt.SigningMethodEdDSA, p, p.Public()
+	case *rsa.PublicKey:
+		k.method, k.public = jwt.SigningMethodRS256, p
+	case ed25519.PublicKey:
+		k.method, k.public = jwt.SigningMethodEdDSA, p
+	default:
+		return nil, fmt.Errorf("jwt key %q must be RSA or Ed25519", kc.ID)
+	}
+	if pub, ok := k.public.(*rsa.PublicKey); ok && pub.N.BitLen() < 2048 {
+		return nil, fmt.Errorf("jwt key %q: RSA keys must be at least 2048 bits", kc.ID)
+	}
+	return k, nil
+}
\ No newline at end of file
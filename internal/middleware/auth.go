@@ -2,18 +2,36 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/jwtkeys"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 type contextKey string
 
-const UserIDKey contextKey = "userID"
+// sessionClaims are the claims of a JWT access token.
+type sessionClaims struct {
+	jwt.RegisteredClaims
+	SessionID string `json:"sid"`
+	Version   int    `json:"ver"`
+}
+
+const (
+	UserIDKey      contextKey = "userID"
+	SessionIDKey   contextKey = "sessionID"
+	AccessTokenKey contextKey = "accessToken"
+	scopedKey      contextKey = "allowScopedTokens"
+)
 
-func Authenticate(secret string) func(http.Handler) http.Handler {
+// Authenticate accepts either a JWT access token or a personal access token
+// as the bearer credential. JWTs are rejected once the user's token version
+// has moved past the one they were issued with, or once the session they
+// were issued for has ended.
+func Authenticate(keys *jwtkeys.KeySet, tokens domain.AccessTokenService, versions domain.TokenVersionCache, sessions domain.SessionCache) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -24,12 +42,13 @@ func Authenticate(secret string) func(http.Handler) http.Handler {
 
 			tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
 
-			token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (any, error) {
-				if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, domain.ErrTokenInvalid
-				}
-				return []byte(secret), nil
-			})
+			if strings.HasPrefix(tokenStr, domain.AccessTokenPrefix) {
+				authenticateAccessToken(w, r, next, tokens, versions, tokenStr)
+				return
+			}
+
+			var claims sessionClaims
+			token, err := keys.Parse(tokenStr, &claims)
 			if err != nil || !token.Valid {
 				writeError(w, http.StatusUnauthorized, domain.ErrTokenInvalid.Error())
 				return
@@ -41,13 +60,128 @@ func Authenticate(secret string) func(http.Handler) http.Handler {
 				return
 			}
 
+			version, ok := currentVersion(w, r, versions, userID)
+			if !ok {
+				return
+			}
+			if claims.Version != version {
+				writeError(w, http.StatusUnauthorized, domain.ErrTokenInvalid.Error())
+				return
+			}
+
+			live, err := sessions.Active(r.Context(), userID, claims.SessionID)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, "internal server error")
+				return
+			}
+			if !live {
+				writeError(w, http.StatusUnauthorized, domain.ErrTokenInvalid.Error())
+				return
+			}
+
 			ctx := context.WithValue(r.Context(), UserIDKey, userID)
+			ctx = context.WithValue(ctx, SessionIDKey, claims.SessionID)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// RejectAccessTokens guards routes that need an interactive session, such
+// as managing credentials, from personal access tokens.
+func RejectAccessTokens(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := GetAccessToken(r); ok {
+			writeError(w, http.StatusForbidden, "personal access tokens cannot be used here")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AllowScopedTokens opens a route without a {projectId} to personal access
+// tokens limited to some projects, which are otherwise refused there. It
+// must wrap Authenticate, and only suits routes that reveal nothing about
+// other projects.
+func AllowScopedTokens(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), scopedKey, true)))
+	})
+}
+
 func GetUserID(r *http.Request) (string, bool) {
 	id, ok := r.Context().Value(UserIDKey).(string)
 	return id, ok
+}
+
+// GetSessionID returns the session a JWT access token was issued for. It is
+// empty for personal access tokens.
+func GetSessionID(r *http.Request) string {
+	id, _ := r.Context().Value(SessionIDKey).(string)
+	return id
+}
+
+// GetAccessToken returns the personal access token the request was
+// authenticated with, if any.
+func GetAccessToken(r *http.Request) (*domain.AccessToken, bool) {
+	token, ok := r.Context().Value(AccessTokenKey).(*domain.AccessToken)
+	return token, ok
+}
+
+// --- helpers ---
+
+func authenticateAccessToken(w http.ResponseWriter, r *http.Request, next http.Handler, tokens domain.AccessTokenService, versions domain.TokenVersionCache, raw string) {
+	token, err := tokens.Authenticate(r.Context(), raw)
+	switch {
+	case errors.Is(err, domain.ErrTokenInvalid), errors.Is(err, domain.ErrTokenExpired):
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	markTokenVerified(r, raw)
+
+	// Only checks the owner still exists; personal access tokens are revoked
+	// individually rather than by version
+	if _, ok := currentVersion(w, r, versions, token.UserID.Hex()); !ok {
+		return
+	}
+
+	if token.Scope == domain.ScopeRead && r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusForbidden, "token is read-only")
+		return
+	}
+	// Tokens limited to some projects can only reach routes naming one of
+	// them, plus the few user-level routes marked with AllowScopedTokens
+	if len(token.ProjectIDs) > 0 {
+		projectID := r.PathValue("projectId")
+		allowed, _ := r.Context().Value(scopedKey).(bool)
+		switch {
+		case projectID != "" && !token.AllowsProject(projectID):
+			writeError(w, http.StatusForbidden, "token is not valid for this project")
+			return
+		case projectID == "" && !allowed:
+			writeError(w, http.StatusForbidden, "token is limited to specific projects and cannot be used here")
+			return
+		}
+	}
+
+	ctx := context.WithValue(r.Context(), UserIDKey, token.UserID.Hex())
+	ctx = context.WithValue(ctx, AccessTokenKey, token)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// currentVersion looks up the user's token version, writing a 401 if the
+// user is gone. It reports whether the request may continue.
+func currentVersion(w http.ResponseWriter, r *http.Request, versions domain.TokenVersionCache, userID string) (int, bool) {
+	version, err := versions.Version(r.Context(), userID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrInvalidInput):
+		writeError(w, http.StatusUnauthorized, domain.ErrTokenInvalid.Error())
+		return 0, false
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return 0, false
+	}
+	return version, true
 }
\ No newline at end of file
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+)
+
+const ProjectRoleKey contextKey = "projectRole"
+
+// ProjectAccess lets only members of the project named by {projectId}
+// through. The project is loaded once here and passed on in the request
+// context, together with the caller's role in it. Must run after
+// Authenticate.
+func ProjectAccess(projects domain.ProjectRepository) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, _ := GetUserID(r)
+
+			project, err := projects.FindByID(r.Context(), r.PathValue("projectId"))
+			switch {
+			case errors.Is(err, domain.ErrNotFound):
+				writeError(w, http.StatusNotFound, err.Error())
+				return
+			case errors.Is(err, domain.ErrInvalidInput):
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			case err != nil:
+				writeError(w, http.StatusInternalServerError, "internal server error")
+				return
+			}
+
+			role, ok := project.MemberRole(userID)
+			if !ok {
+				writeError(w, http.StatusForbidden, domain.ErrForbidden.Error())
+				return
+			}
+
+			ctx := domain.WithProject(r.Context(), project)
+			ctx = context.WithValue(ctx, ProjectRoleKey, role)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetProjectRole returns the caller's role in the project named by the
+// path. It is only set behind ProjectAccess.
+func GetProjectRole(r *http.Request) (domain.Role, bool) {
+	role, ok := r.Context().Value(ProjectRoleKey).(domain.Role)
+	return role, ok
+}
\ No newline at end of file
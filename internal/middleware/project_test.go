@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type oneProject struct {
+	domain.ProjectRepository
+	project *domain.Project
+}
+
+func (r oneProject) FindByID(ctx context.Context, id string) (*domain.Project, error) {
+	if id != r.project.ID.Hex() {
+		return nil, domain.ErrNotFound
+	}
+	return r.project, nil
+}
+
+func TestProjectAccess(t *testing.T) {
+	member, outsider := bson.NewObjectID(), bson.NewObjectID()
+	project := &domain.Project{ID: bson.NewObjectID(), Members: []domain.ProjectMember{{UserID: member, Role: domain.RoleMember}}}
+
+	tests := []struct {
+		name      string
+		userID    string
+		projectID string
+		want      int
+	}{
+		{name: "member", userID: member.Hex(), projectID: project.ID.Hex(), want: http.StatusOK},
+		{name: "outsider", userID: outsider.Hex(), projectID: project.ID.Hex(), want: http.StatusForbidden},
+		{name: "unknown project", userID: member.Hex(), projectID: bson.NewObjectID().Hex(), want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var role domain.Role
+			var loaded bool
+			h := ProjectAccess(oneProject{project: project})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				role, _ = GetProjectRole(r)
+				_, loaded = domain.ProjectFromContext(r.Context(), tt.projectID)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/projects/"+tt.projectID, nil)
+			r.SetPathValue("projectId", tt.projectID)
+			r = r.WithContext(context.WithValue(r.Context(), UserIDKey, tt.userID))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.want {
+				t.Fatalf("status %d, want %d", w.Code, tt.want)
+			}
+			if tt.want == http.StatusOK && (role != domain.RoleMember || !loaded) {
+				t.Fatalf("role %q, project loaded %v, want the member's role and the project", role, loaded)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,318 @@
+package middleware
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/config"
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/jwtkeys"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// bucketSweepInterval is how often buckets that have refilled are dropped.
+	bucketSweepInterval = time.Minute
+	// maxBuckets caps the buckets kept at once. A caller that shows up while
+	// it is reached takes the place of the least recently used bucket.
+	maxBuckets = 100_000
+	// verifiedTokenTTL is how long a personal access token the auth
+	// middleware accepted keeps its own bucket without being seen again.
+	verifiedTokenTTL  = 10 * time.Minute
+	maxVerifiedTokens = 10_000
+)
+
+// rateLimiterKey holds the rateLimiter in the request context, so the auth
+// middleware can report tokens it has verified.
+const rateLimiterKey contextKey = "rateLimiter"
+
+// rateLimitRule is a parsed config.RateLimitPolicy.
+type rateLimitRule struct {
+	method   string
+	segments []string
+	prefix   bool
+	// rate is in tokens per second; zero means unlimited
+	rate  float64
+	burst int
+}
+
+type bucketKey struct {
+	rule     int
+	identity string
+}
+
+type bucket struct {
+	key    bucketKey
+	tokens float64
+	last   time.Time
+}
+
+type rateLimiter struct {
+	// rules ends with the default policy, which matches every request
+	rules []rateLimitRule
+	keys  *jwtkeys.KeySet
+
+	mu      sync.Mutex
+	buckets map[bucketKey]*list.Element
+	// recent holds the *bucket values, least recently used first
+	recent *list.List
+	// tokens maps the hashes of verified personal access tokens to when
+	// they stop counting as verified
+	tokens map[string]time.Time
+	swept  time.Time
+}
+
+// RateLimit gives each caller a token bucket per route policy. Callers are
+// told apart by the user ID of a validly signed JWT, by a personal access
+// token once Authenticate has accepted it, and otherwise by IP. Buckets
+// live in this process, so each instance behind a load balancer enforces
+// the limits separately.
+func RateLimit(cfg config.RateLimitConfig, keys *jwtkeys.KeySet) (func(http.Handler) http.Handler, error) {
+	if !cfg.Enabled {
+		return func(next http.Handler) http.Handler { return next }, nil
+	}
+
+	l := newRateLimiter(keys)
+	for _, policy := range cfg.Routes {
+		rule, err := parseRateLimitRule(policy)
+		if err != nil {
+			return nil, err
+		}
+		l.rules = append(l.rules, rule)
+	}
+	l.rules = append(l.rules, rateLimitRule{
+		prefix: true,
+		rate:   float64(cfg.Default.RequestsPerMinute) / 60,
+		burst:  max(cfg.Default.Burst, 1),
+	})
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			i := l.match(r)
+			rule := l.rules[i]
+			if rule.rate == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			now := time.Now()
+			ok, remaining, reset := l.take(bucketKey{rule: i, identity: l.identity(r, now)}, now)
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(rule.burst))
+			h.Set("RateLimit-Remaining", strconv.Itoa(remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(reset)))
+			if !ok {
+				h.Set("Retry-After", strconv.Itoa(ceilSeconds(reset)))
+				writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), rateLimiterKey, l)))
+		})
+	}, nil
+}
+
+// --- helpers ---
+
+func newRateLimiter(keys *jwtkeys.KeySet) *rateLimiter {
+	return &rateLimiter{
+		keys:    keys,
+		buckets: make(map[bucketKey]*list.Element),
+		recent:  list.New(),
+		tokens:  make(map[string]time.Time),
+	}
+}
+
+// match returns the index of the first rule matching the request.
+func (l *rateLimiter) match(r *http.Request) int {
+	segments := pathSegments(r.URL.Path)
+	for i, rule := range l.rules {
+		if rule.matches(r.Method, segments) {
+			return i
+		}
+	}
+	return len(l.rules) - 1
+}
+
+// take spends a token from the caller's bucket. It returns whether one was
+// left, how many remain, and how long until the bucket is full again or,
+// if it was empty, until the next token.
+func (l *rateLimiter) take(key bucketKey, now time.Time) (bool, int, time.Duration) {
+	rule := l.rules[key.rule]
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	var b *bucket
+	if e, ok := l.buckets[key]; ok {
+		l.recent.MoveToBack(e)
+		b = e.Value.(*bucket)
+	} else {
+		if len(l.buckets) >= maxBuckets {
+			oldest := l.recent.Front()
+			delete(l.buckets, l.recent.Remove(oldest).(*bucket).key)
+		}
+		b = &bucket{key: key, tokens: float64(rule.burst), last: now}
+		l.buckets[key] = l.recent.PushBack(b)
+	}
+	b.tokens = min(float64(rule.burst), b.tokens+now.Sub(b.last).Seconds()*rule.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, 0, secondsDuration((1 - b.tokens) / rule.rate)
+	}
+	b.tokens--
+	return true, int(b.tokens), secondsDuration((float64(rule.burst) - b.tokens) / rule.rate)
+}
+
+// sweep drops buckets that have refilled, at most once per
+// bucketSweepInterval. The caller must hold l.mu.
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < bucketSweepInterval {
+		return
+	}
+	l.swept = now
+	for key, e := range l.buckets {
+		b, rule := e.Value.(*bucket), l.rules[key.rule]
+		if b.tokens+now.Sub(b.last).Seconds()*rule.rate >= float64(rule.burst) {
+			delete(l.buckets, key)
+			l.recent.Remove(e)
+		}
+	}
+	for hash, until := range l.tokens {
+		if !now.Before(until) {
+			delete(l.tokens, hash)
+		}
+	}
+}
+
+// tokenVerified records that the personal access token with hash is real,
+// so its requests get their own bucket from now on.
+func (l *rateLimiter) tokenVerified(hash string) {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.tokens[hash]; !ok && len(l.tokens) >= maxVerifiedTokens {
+		// The token goes on sharing its IP's bucket
+		return
+	}
+	l.tokens[hash] = now.Add(verifiedTokenTTL)
+}
+
+// markTokenVerified tells the rate limiter that handled r, if any, that raw
+// is a real personal access token.
+func markTokenVerified(r *http.Request, raw string) {
+	if l, ok := r.Context().Value(rateLimiterKey).(*rateLimiter); ok {
+		l.tokenVerified(hashAccessToken(raw))
+	}
+}
+
+func hashAccessToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// identity names the caller. JWTs are only trusted once their signature
+// checks out. Checking a personal access token needs the database, so one
+// is keyed by its hash only after Authenticate has accepted it; until then
+// it counts against the IP, and made-up tokens can't each get a fresh
+// bucket. IPv6 callers are keyed by their /64, since a single host is
+// usually handed a whole one.
+func (l *rateLimiter) identity(r *http.Request, now time.Time) string {
+	if raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		if strings.HasPrefix(raw, domain.AccessTokenPrefix) {
+			hash := hashAccessToken(raw)
+			l.mu.Lock()
+			until, verified := l.tokens[hash]
+			l.mu.Unlock()
+			if verified && now.Before(until) {
+				return "token:" + hash
+			}
+		}
+		var claims jwt.RegisteredClaims
+		if token, err := l.keys.Parse(raw, &claims); err == nil && token.Valid && claims.Subject != "" {
+			return "user:" + claims.Subject
+		}
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if addr, err := netip.ParseAddr(ip); err == nil && addr.Is6() && !addr.Is4In6() {
+		return "ip:" + netip.PrefixFrom(addr.WithZone(""), 64).Masked().String()
+	}
+	return "ip:" + ip
+}
+
+func (rule rateLimitRule) matches(method string, segments []string) bool {
+	// As with ServeMux, GET also covers HEAD
+	if rule.method != "" && rule.method != method && !(rule.method == http.MethodGet && method == http.MethodHead) {
+		return false
+	}
+	if len(segments) < len(rule.segments) || (!rule.prefix && len(segments) != len(rule.segments)) {
+		return false
+	}
+	for i, s := range rule.segments {
+		if s != segments[i] && !isWildcard(s) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseRateLimitRule(policy config.RateLimitPolicy) (rateLimitRule, error) {
+	method, path, found := strings.Cut(policy.Pattern, " ")
+	if !found {
+		method, path = "", method
+	}
+	if !strings.HasPrefix(path, "/") {
+		return rateLimitRule{}, fmt.Errorf("rate limit pattern %q: path must start with /", policy.Pattern)
+	}
+	if method != strings.ToUpper(method) {
+		return rateLimitRule{}, fmt.Errorf("rate limit pattern %q: method must be upper case", policy.Pattern)
+	}
+	if policy.RequestsPerMinute < 0 || policy.Burst < 0 {
+		return rateLimitRule{}, fmt.Errorf("rate limit pattern %q: limits must not be negative", policy.Pattern)
+	}
+
+	return rateLimitRule{
+		method:   method,
+		segments: pathSegments(path),
+		prefix:   strings.HasSuffix(path, "/"),
+		rate:     float64(policy.RequestsPerMinute) / 60,
+		burst:    max(policy.Burst, 1),
+	}, nil
+}
+
+func pathSegments(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func isWildcard(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func secondsDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
\ No newline at end of file
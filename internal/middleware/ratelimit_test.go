@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/config"
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/jwtkeys"
+)
+
+var goodToken = domain.AccessTokenPrefix + "good"
+
+// rateLimited puts a limiter of burst requests in front of a stand-in for
+// Authenticate that accepts only goodToken.
+func rateLimited(t *testing.T, burst int) http.Handler {
+	t.Helper()
+	keys, err := jwtkeys.Load(config.JWTConfig{AccessSecret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	limit, err := RateLimit(config.RateLimitConfig{
+		Enabled: true,
+		Default: config.RateLimitPolicy{RequestsPerMinute: 1, Burst: burst},
+	}, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if raw != goodToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		markTokenVerified(r, raw)
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func send(h http.Handler, ip, token string) int {
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/projects/", nil)
+	r.RemoteAddr = ip + ":1234"
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Code
+}
+
+func TestRateLimitKeysUnverifiedTokensByIP(t *testing.T) {
+	h := rateLimited(t, 2)
+
+	// Each made-up token would otherwise get a fresh bucket
+	for i := range 2 {
+		if code := send(h, "198.51.100.1", fmt.Sprintf("%sfake%d", domain.AccessTokenPrefix, i)); code != http.StatusUnauthorized {
+			t.Fatalf("request %d: status %d, want 401 from auth", i, code)
+		}
+	}
+	if code := send(h, "198.51.100.1", domain.AccessTokenPrefix+"fake-next"); code != http.StatusTooManyRequests {
+		t.Fatalf("status %d, want 429 once the IP's bucket is empty", code)
+	}
+	if code := send(h, "198.51.100.2", domain.AccessTokenPrefix+"fake-other"); code != http.StatusUnauthorized {
+		t.Fatalf("status %d from another IP, want 401", code)
+	}
+}
+
+func TestRateLimitKeysVerifiedTokenByToken(t *testing.T) {
+	h := rateLimited(t, 2)
+
+	// The first request counts against the IP, since the token isn't known yet
+	if code := send(h, "198.51.100.1", goodToken); code != http.StatusOK {
+		t.Fatalf("status %d, want 200", code)
+	}
+	// From then on the token has its own bucket, wherever it's used from
+	for _, ip := range []string{"198.51.100.1", "203.0.113.9"} {
+		if code := send(h, ip, goodToken); code != http.StatusOK {
+			t.Fatalf("status %d from %s, want 200", code, ip)
+		}
+	}
+	if code := send(h, "203.0.113.9", goodToken); code != http.StatusTooManyRequests {
+		t.Fatalf("status %d, want 429 once the token's bucket is empty", code)
+	}
+	// The IP still has what the token's first request left it
+	if code := send(h, "198.51.100.1", ""); code != http.StatusUnauthorized {
+		t.Fatalf("status %d, want 401 from auth", code)
+	}
+	if code := send(h, "198.51.100.1", ""); code != http.StatusTooManyRequests {
+		t.Fatalf("status %d, want 429", code)
+	}
+}
+
+func TestRateLimitKeysIPv6ByPrefix(t *testing.T) {
+	h := rateLimited(t, 1)
+
+	if code := send(h, "[2001:db8:1:2::1]", ""); code != http.StatusUnauthorized {
+		t.Fatalf("status %d, want 401 from auth", code)
+	}
+	// Another address in the same /64 is the same caller
+	if code := send(h, "[2001:db8:1:2:ffff::9]", ""); code != http.StatusTooManyRequests {
+		t.Fatalf("status %d from the same /64, want 429", code)
+	}
+	if code := send(h, "[2001:db8:1:3::1]", ""); code != http.StatusUnauthorized {
+		t.Fatalf("status %d from another /64, want 401", code)
+	}
+}
+
+func TestRateLimitCapsBuckets(t *testing.T) {
+	l := newRateLimiter(nil)
+	l.rules = []rateLimitRule{{prefix: true, rate: 1, burst: 1}}
+	now := time.Now()
+	// Sweeping is due in a minute; until then every bucket is in use
+	l.swept = now
+	for i := range maxBuckets {
+		l.take(bucketKey{identity: fmt.Sprintf("ip:%d", i)}, now)
+	}
+	// ip:0 was used last, so ip:1 is now the oldest
+	l.take(bucketKey{identity: "ip:0"}, now)
+
+	// Callers over the cap each get their own bucket
+	for _, identity := range []string{"ip:new-1", "ip:new-2"} {
+		if ok, _, _ := l.take(bucketKey{identity: identity}, now); !ok {
+			t.Fatalf("%s over the cap was refused", identity)
+		}
+	}
+	if len(l.buckets) != maxBuckets {
+		t.Fatalf("%d buckets, want the cap", len(l.buckets))
+	}
+	for _, identity := range []string{"ip:1", "ip:2"} {
+		if _, ok := l.buckets[bucketKey{identity: identity}]; ok {
+			t.Errorf("%s wasn't evicted", identity)
+		}
+	}
+	if _, ok := l.buckets[bucketKey{identity: "ip:0"}]; !ok {
+		t.Error("the recently used ip:0 was evicted")
+	}
+
+	// Once the others have refilled, the next sweep frees room
+	later := now.Add(bucketSweepInterval)
+	if ok, _, _ := l.take(bucketKey{identity: "ip:new-3"}, later); !ok {
+		t.Fatal("caller refused after the sweep")
+	}
+	if len(l.buckets) != 1 || l.recent.Len() != 1 {
+		t.Fatalf("%d buckets after the sweep, want 1", len(l.buckets))
+	}
+}
\ No newline at end of file
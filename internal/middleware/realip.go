@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// RealIP sets r.RemoteAddr to the client's address when the request came
+// through one of the trusted reverse proxies, so logging, rate limiting and
+// login throttling see the client rather than the proxy. X-Forwarded-For
+// is read from the right, skipping trusted proxies; anything left of the
+// first untrusted address could have been sent by the client, so it is
+// ignored. Requests from other peers keep their address whatever headers
+// they send. proxies are addresses or CIDR prefixes.
+func RealIP(proxies []string) (func(http.Handler) http.Handler, error) {
+	trusted := make([]netip.Prefix, 0, len(proxies))
+	for _, p := range proxies {
+		prefix, err := parseProxy(p)
+		if err != nil {
+			return nil, err
+		}
+		trusted = append(trusted, prefix)
+	}
+	isTrusted := func(addr netip.Addr) bool {
+		for _, p := range trusted {
+			if p.Contains(addr.Unmap()) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		if len(trusted) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			peer, err := netip.ParseAddrPort(r.RemoteAddr)
+			if err != nil || !isTrusted(peer.Addr()) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			client := peer.Addr()
+			hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+			for i := len(hops) - 1; i >= 0 && isTrusted(client); i-- {
+				addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+				if err != nil {
+					break
+				}
+				client = addr
+			}
+
+			r2 := r.Clone(r.Context())
+			r2.RemoteAddr = net.JoinHostPort(client.Unmap().String(), "0")
+			next.ServeHTTP(w, r2)
+		})
+	}, nil
+}
+
+func parseProxy(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("trusted proxy %q: %w", s, err)
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("trusted proxy %q: %w", s, err)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
\ No newline at end of file
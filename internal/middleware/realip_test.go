@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRealIP(t *testing.T) {
+	proxies := []string{"10.0.0.0/8", "192.0.2.1"}
+	tests := []struct {
+		name   string
+		peer   string
+		header []string
+		want   string
+	}{
+		{name: "direct client", peer: "203.0.113.7:5000", want: "203.0.113.7:5000"},
+		{name: "untrusted peer can't spoof", peer: "203.0.113.7:5000", header: []string{"198.51.100.1"}, want: "203.0.113.7:5000"},
+		{name: "through proxy", peer: "10.1.2.3:443", header: []string{"198.51.100.1"}, want: "198.51.100.1:0"},
+		{name: "client-supplied hops ignored", peer: "10.1.2.3:443", header: []string{"1.1.1.1, 198.51.100.1"}, want: "198.51.100.1:0"},
+		{name: "chain of proxies", peer: "10.1.2.3:443", header: []string{"198.51.100.1, 192.0.2.1, 10.9.9.9"}, want: "198.51.100.1:0"},
+		{name: "repeated header", peer: "10.1.2.3:443", header: []string{"1.1.1.1", "198.51.100.1"}, want: "198.51.100.1:0"},
+		{name: "garbage stops the walk", peer: "10.1.2.3:443", header: []string{"198.51.100.1, nonsense"}, want: "10.1.2.3:0"},
+		{name: "no header", peer: "10.1.2.3:443", want: "10.1.2.3:0"},
+		{name: "single trusted address", peer: "192.0.2.1:443", header: []string{"2001:db8::1"}, want: "[2001:db8::1]:0"},
+		{name: "ipv4-mapped peer", peer: "[::ffff:10.1.2.3]:443", header: []string{"198.51.100.1"}, want: "198.51.100.1:0"},
+	}
+
+	realIP, err := RealIP(proxies)
+	if err != nil {
+		t.Fatalf("RealIP: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			h := realIP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { got = r.RemoteAddr }))
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.peer
+			for _, v := range tt.header {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), r)
+			if got != tt.want {
+				t.Errorf("RemoteAddr = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRealIPRejectsBadProxy(t *testing.T) {
+	for _, p := range []string{"10.0.0.0/33", "proxy.internal", ""} {
+		if _, err := RealIP([]string{p}); err == nil {
+			t.Errorf("RealIP(%q) accepted", p)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,373 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID = "pms"
+	testCode     = "auth-code"
+	testVerifier = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+)
+
+// mockIssuer is an OpenID provider that issues one code. Its token endpoint
+// enforces PKCE against the challenge of the last authorization URL.
+type mockIssuer struct {
+	t   *testing.T
+	srv *httptest.Server
+
+	mu        sync.Mutex
+	key       *rsa.PrivateKey
+	kid       string
+	meta      map[string]string
+	challenge string
+	nonce     string
+	// claims edits the ID token before it's signed
+	claims func(jwt.MapClaims)
+	// sign overrides how the ID token is signed
+	sign func(jwt.MapClaims) string
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+	m := &mockIssuer{t: t, key: newRSAKey(t), kid: "k1"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", m.discovery)
+	mux.HandleFunc("GET /jwks", m.jwks)
+	mux.HandleFunc("POST /token", m.token)
+	m.srv = httptest.NewServer(mux)
+	t.Cleanup(m.srv.Close)
+	m.meta = map[string]string{
+		"issuer":                 m.srv.URL,
+		"authorization_endpoint": m.srv.URL + "/authorize",
+		"token_endpoint":         m.srv.URL + "/token",
+		"jwks_uri":               m.srv.URL + "/jwks",
+	}
+	return m
+}
+
+func (m *mockIssuer) provider() *provider {
+	return NewProvider(config.OIDCProviderConfig{
+		Name:        "mock",
+		Issuer:      m.srv.URL,
+		ClientID:    testClientID,
+		RedirectURL: "http://app.test/api/v1/auth/oidc/mock/callback",
+	}).(*provider)
+}
+
+func (m *mockIssuer) discovery(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	json.NewEncoder(w).Encode(m.meta)
+}
+
+func (m *mockIssuer) jwks(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pub := m.key.PublicKey
+	json.NewEncoder(w).Encode(map[string]any{"keys": []jwk{{
+		Kty: "RSA",
+		Kid: m.kid,
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}})
+}
+
+func (m *mockIssuer) token(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "bad form", http.StatusBadRequest)
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if r.PostForm.Get("code") != testCode || base64.RawURLEncoding.EncodeToString(sum[:]) != m.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            m.srv.URL,
+		"aud":            testClientID,
+		"sub":            "user-123",
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          m.nonce,
+		"email":          "ada@example.com",
+		"email_verified": true,
+		"name":           "Ada",
+	}
+	if m.claims != nil {
+		m.claims(claims)
+	}
+	var idToken string
+	if m.sign != nil {
+		idToken = m.sign(claims)
+	} else {
+		idToken = signRS256(m.t, m.key, m.kid, claims)
+	}
+	json.NewEncoder(w).Encode(map[string]string{"id_token": idToken, "token_type": "Bearer"})
+}
+
+// authorize plays the browser's trip to the provider: it checks the
+// authorization URL and remembers what the token endpoint must see.
+func (m *mockIssuer) authorize(t *testing.T, p *provider, state, nonce string) {
+	t.Helper()
+	sum := sha256.Sum256([]byte(testVerifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	raw, err := p.AuthCodeURL(context.Background(), state, nonce, challenge)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("parse auth url: %v", err)
+	}
+	if got := u.Scheme + "://" + u.Host + u.Path; got != m.srv.URL+"/authorize" {
+		t.Fatalf("auth url points at %s", got)
+	}
+	q := u.Query()
+	for k, want := range map[string]string{
+		"response_type":         "code",
+		"client_id":             testClientID,
+		"state":                 state,
+		"nonce":                 nonce,
+		"code_challenge":        challenge,
+		"code_challenge_method": "S256",
+	} {
+		if got := q.Get(k); got != want {
+			t.Errorf("auth url %s = %q, want %q", k, got, want)
+		}
+	}
+
+	m.mu.Lock()
+	m.challenge = q.Get("code_challenge")
+	m.nonce = q.Get("nonce")
+	m.mu.Unlock()
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return key
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	tok.Header["kid"] = kid
+	s, err := tok.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign id token: %v", err)
+	}
+	return s
+}
+
+func TestExchange(t *testing.T) {
+	m := newMockIssuer(t)
+	p := m.provider()
+	m.authorize(t, p, "state-1", "nonce-1")
+
+	identity, err := p.Exchange(context.Background(), testCode, testVerifier, "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if identity.Provider != "mock" || identity.Subject != "user-123" || identity.Email != "ada@example.com" || !identity.EmailVerified || identity.Name != "Ada" {
+		t.Errorf("identity = %+v", identity)
+	}
+}
+
+func TestExchangeEmailVerifiedAsString(t *testing.T) {
+	m := newMockIssuer(t)
+	m.claims = func(c jwt.MapClaims) { c["email_verified"] = "false" }
+	p := m.provider()
+	m.authorize(t, p, "state-1", "nonce-1")
+
+	identity, err := p.Exchange(context.Background(), testCode, testVerifier, "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if identity.EmailVerified {
+		t.Error(`email_verified "false" was taken as verified`)
+	}
+}
+
+func TestExchangePKCE(t *testing.T) {
+	m := newMockIssuer(t)
+	p := m.provider()
+	m.authorize(t, p, "state-1", "nonce-1")
+
+	for name, verifier := range map[string]string{
+		"wrong verifier":   strings.Repeat("f", 64),
+		"missing verifier": "",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := p.Exchange(context.Background(), testCode, verifier, "nonce-1"); err == nil {
+				t.Fatal("exchange succeeded without the PKCE verifier")
+			}
+		})
+	}
+}
+
+func TestExchangeRejectsIDToken(t *testing.T) {
+	other := newRSAKey(t)
+	tests := []struct {
+		name   string
+		nonce  string
+		claims func(jwt.MapClaims)
+		sign   func(m *mockIssuer) func(jwt.MapClaims) string
+	}{
+		{name: "nonce mismatch", nonce: "another-nonce"},
+		{name: "missing nonce", claims: func(c jwt.MapClaims) { delete(c, "nonce") }},
+		{name: "wrong audience", claims: func(c jwt.MapClaims) { c["aud"] = "someone-else" }},
+		{name: "azp mismatch", claims: func(c jwt.MapClaims) { c["aud"] = []string{testClientID, "other"}; c["azp"] = "other" }},
+		{name: "wrong issuer", claims: func(c jwt.MapClaims) { c["iss"] = "https://evil.example" }},
+		{name: "expired", claims: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-5 * time.Minute).Unix() }},
+		{name: "no expiry", claims: func(c jwt.MapClaims) { delete(c, "exp") }},
+		{name: "missing sub", claims: func(c jwt.MapClaims) { delete(c, "sub") }},
+		{
+			name: "unknown key",
+			sign: func(m *mockIssuer) func(jwt.MapClaims) string {
+				return func(c jwt.MapClaims) string { return signRS256(t, other, "k2", c) }
+			},
+		},
+		{
+			name: "wrong key with known kid",
+			sign: func(m *mockIssuer) func(jwt.MapClaims) string {
+				return func(c jwt.MapClaims) string { return signRS256(t, other, m.kid, c) }
+			},
+		},
+		{
+			name: "hmac",
+			sign: func(m *mockIssuer) func(jwt.MapClaims) string {
+				return func(c jwt.MapClaims) string {
+					s, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString([]byte(testClientID))
+					return s
+				}
+			},
+		},
+		{
+			name: "unsigned",
+			sign: func(m *mockIssuer) func(jwt.MapClaims) string {
+				return func(c jwt.MapClaims) string {
+					s, _ := jwt.NewWithClaims(jwt.SigningMethodNone, c).SignedString(jwt.UnsafeAllowNoneSignatureType)
+					return s
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMockIssuer(t)
+			m.claims = tt.claims
+			if tt.sign != nil {
+				m.sign = tt.sign(m)
+			}
+			p := m.provider()
+			m.authorize(t, p, "state-1", "nonce-1")
+
+			nonce := "nonce-1"
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+			_, err := p.Exchange(context.Background(), testCode, testVerifier, nonce)
+			if !errors.Is(err, ErrInvalidIDToken) {
+				t.Fatalf("Exchange error = %v, want ErrInvalidIDToken", err)
+			}
+		})
+	}
+}
+
+func TestDiscovery(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(meta map[string]string)
+	}{
+		{name: "issuer mismatch", edit: func(meta map[string]string) { meta["issuer"] = "https://evil.example" }},
+		{name: "missing token endpoint", edit: func(meta map[string]string) { delete(meta, "token_endpoint") }},
+		{name: "missing jwks", edit: func(meta map[string]string) { delete(meta, "jwks_uri") }},
+		{name: "missing authorization endpoint", edit: func(meta map[string]string) { delete(meta, "authorization_endpoint") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMockIssuer(t)
+			tt.edit(m.meta)
+			p := m.provider()
+			if _, err := p.AuthCodeURL(context.Background(), "s", "n", "c"); err == nil {
+				t.Fatal("AuthCodeURL succeeded against a bad discovery document")
+			}
+			if _, err := p.Exchange(context.Background(), testCode, testVerifier, "n"); err == nil {
+				t.Fatal("Exchange succeeded against a bad discovery document")
+			}
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		m := newMockIssuer(t)
+		p := m.provider()
+		m.srv.Close()
+		if _, err := p.AuthCodeURL(context.Background(), "s", "n", "c"); err == nil {
+			t.Fatal("AuthCodeURL succeeded with the issuer down")
+		}
+	})
+
+	t.Run("trailing slash", func(t *testing.T) {
+		m := newMockIssuer(t)
+		m.meta["issuer"] = m.srv.URL + "/"
+		if _, err := m.provider().AuthCodeURL(context.Background(), "s", "n", "c"); err != nil {
+			t.Fatalf("AuthCodeURL: %v", err)
+		}
+	})
+}
+
+func TestKeyRotation(t *testing.T) {
+	m := newMockIssuer(t)
+	p := m.provider()
+	m.authorize(t, p, "state-1", "nonce-1")
+	if _, err := p.Exchange(context.Background(), testCode, testVerifier, "nonce-1"); err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	m.mu.Lock()
+	m.key, m.kid = newRSAKey(t), "k2"
+	m.mu.Unlock()
+
+	// The keys were fetched moments ago, so an unknown kid can't make the
+	// provider hammer the JWKS endpoint
+	if _, err := p.Exchange(context.Background(), testCode, testVerifier, "nonce-1"); !errors.Is(err, ErrInvalidIDToken) {
+		t.Fatalf("Exchange right after rotation = %v, want ErrInvalidIDToken", err)
+	}
+
+	p.keys.mu.Lock()
+	p.keys.fetched = time.Now().Add(-2 * minRefresh)
+	p.keys.mu.Unlock()
+	if _, err := p.Exchange(context.Background(), testCode, testVerifier, "nonce-1"); err != nil {
+		t.Fatalf("Exchange after rotation: %v", err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type accessTokenRepository struct {
+	col *mongo.Collection
+}
+
+func NewAccessTokenRepository(db *mongo.Database) domain.AccessTokenRepository {
+	return &accessTokenRepository{col: db.Collection("access_tokens")}
+}
+
+func (r *accessTokenRepository) Create(ctx context.Context, token *domain.AccessToken) error {
+	token.ID = bson.NewObjectID()
+	token.CreatedAt = time.Now()
+
+	_, err := r.col.InsertOne(ctx, token)
+	return err
+}
+
+func (r *accessTokenRepository) FindByHash(ctx context.Context, hash string) (*domain.AccessToken, error) {
+	var token domain.AccessToken
+	err := r.col.FindOne(ctx, bson.M{"token_hash": hash}).Decode(&token)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	return &token, err
+}
+
+func (r *accessTokenRepository) FindByUserID(ctx context.Context, userID string) ([]domain.AccessToken, error) {
+	oid, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.col.Find(ctx, bson.M{"user_id": oid}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tokens := []domain.AccessToken{}
+	if err := cursor.All(ctx, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (r *accessTokenRepository) TouchLastUsed(ctx context.Context, id bson.ObjectID, now time.Time, within time.Duration) error {
+	query := bson.M{
+		"_id": id,
+		"$or": bson.A{
+			bson.M{"last_used_at": bson.M{"$exists": false}},
+			bson.M{"last_used_at": bson.M{"$lt": now.Add(-within)}},
+		},
+	}
+	_, err := r.col.UpdateOne(ctx, query, bson.M{"$set": bson.M{"last_used_at": now}})
+	return err
+}
+
+func (r *accessTokenRepository) Delete(ctx context.Context, id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	_, err = r.col.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
+
+func (r *accessTokenRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	oid, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	_, err = r.col.DeleteMany(ctx, bson.M{"user_id": oid})
+	return err
+}
\ No newline at end of file
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type activityRepository struct {
+	col *mongo.Collection
+}
+
+func NewActivityRepository(db *mongo.Database) domain.ActivityRepository {
+	return &activityRepository{col: db.Collection("task_activity")}
+}
+
+func (r *activityRepository) Create(ctx context.Context, activity *domain.TaskActivity) error {
+	activity.ID = bson.NewObjectID()
+	activity.CreatedAt = time.Now()
+
+	_, err := r.col.InsertOne(ctx, activity)
+	return err
+}
+
+func (r *activityRepository) FindByTaskID(ctx context.Context, taskID string) ([]domain.TaskActivity, error) {
+	oid, err := bson.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.col.Find(ctx, bson.M{"task_id": oid}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var activity []domain.TaskActivity
+	if err := cursor.All(ctx, &activity); err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+func (r *activityRepository) AnonymizeUser(ctx context.Context, userID string) error {
+	oid, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	if _, err := r.col.UpdateMany(ctx, bson.M{"actor_id": oid}, bson.M{"$set": bson.M{"actor_id": bson.ObjectID{}}}); err != nil {
+		return err
+	}
+
+	// Changes hold IDs as hex strings
+	for _, side := range []string{"from", "to"} {
+		update := bson.M{"$set": bson.M{"changes.$[c]." + side: bson.ObjectID{}.Hex()}}
+		opts := options.UpdateMany().SetArrayFilters([]any{bson.M{"c." + side: oid.Hex()}})
+		if _, err := r.col.UpdateMany(ctx, bson.M{"changes." + side: oid.Hex()}, update, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type attemptStore struct {
+	col *mongo.Collection
+}
+
+// NewAttemptStore returns an AttemptStore shared by every instance using
+// the database. A TTL index removes expired records.
+func NewAttemptStore(db *mongo.Database) domain.AttemptStore {
+	return &attemptStore{col: db.Collection("login_attempts")}
+}
+
+func (s *attemptStore) Get(ctx context.Context, key string) (*domain.AttemptRecord, error) {
+	var record domain.AttemptRecord
+	// The TTL monitor runs about once a minute, so skip expired records here
+	err := s.col.FindOne(ctx, bson.M{"_id": key, "expires_at": bson.M{"$gt": time.Now()}}).Decode(&record)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &domain.AttemptRecord{Key: key}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (s *attemptStore) Fail(ctx context.Context, key string, window time.Duration) (*domain.AttemptRecord, error) {
+	now := time.Now()
+	// A pipeline update resets stale failures and counts this one atomically
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"failures": bson.M{"$cond": bson.A{
+			bson.M{"$gt": bson.A{"$last_failure_at", now.Add(-window)}},
+			bson.M{"$add": bson.A{"$failures", 1}},
+			1,
+		}},
+		"last_failure_at": now,
+		"expires_at":      bson.M{"$max": bson.A{now.Add(window), "$locked_until"}},
+	}}}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var record domain.AttemptRecord
+	if err := s.col.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (s *attemptStore) Refund(ctx context.Context, key string) error {
+	// A lockout in between has already cleared the count
+	filter := bson.M{"_id": key, "failures": bson.M{"$gt": 0}}
+	_, err := s.col.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"failures": -1}})
+	return err
+}
+
+func (s *attemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	update := bson.M{
+		"$set": bson.M{"failures": 0, "locked_until": until},
+		"$max": bson.M{"expires_at": until},
+	}
+	_, err := s.col.UpdateOne(ctx, bson.M{"_id": key}, update, options.UpdateOne().SetUpsert(true))
+	return err
+}
+
+func (s *attemptStore) Reset(ctx context.Context, key string) error {
+	_, err := s.col.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
\ No newline at end of file
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type auditRepository struct {
+	col *mongo.Collection
+}
+
+func NewAuditRepository(db *mongo.Database) domain.AuditRepository {
+	return &auditRepository{col: db.Collection("audit_log")}
+}
+
+func (r *auditRepository) Create(ctx context.Context, entry *domain.AuditEntry) error {
+	entry.ID = bson.NewObjectID()
+	entry.CreatedAt = time.Now()
+
+	_, err := r.col.InsertOne(ctx, entry)
+	return err
+}
+
+func (r *auditRepository) Find(ctx context.Context, filter domain.AuditFilter) (*domain.AuditPage, error) {
+	query := bson.M{}
+	for field, hex := range map[string]string{"project_id": filter.ProjectID, "actor_id": filter.ActorID} {
+		if hex == "" {
+			continue
+		}
+		oid, err := bson.ObjectIDFromHex(hex)
+		if err != nil {
+			return nil, domain.ErrInvalidInput
+		}
+		query[field] = oid
+	}
+	if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+	if filter.TargetID != "" {
+		query["target_id"] = filter.TargetID
+	}
+
+	created := bson.M{}
+	if !filter.Since.IsZero() {
+		created["$gte"] = filter.Since
+	}
+	if !filter.Until.IsZero() {
+		created["$lt"] = filter.Until
+	}
+	if len(created) > 0 {
+		query["created_at"] = created
+	}
+
+	// IDs increase with insertion time, so they double as a stable cursor
+	if filter.Cursor != "" {
+		oid, err := bson.ObjectIDFromHex(filter.Cursor)
+		if err != nil {
+			return nil, domain.ErrInvalidInput
+		}
+		query["_id"] = bson.M{"$lt": oid}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetLimit(int64(filter.Limit) + 1)
+	cursor, err := r.col.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []domain.AuditEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	page := &domain.AuditPage{Entries: entries}
+	if len(entries) > filter.Limit {
+		page.Entries = entries[:filter.Limit]
+		page.NextCursor = page.Entries[filter.Limit-1].ID.Hex()
+	}
+	return page, nil
+}
\ No newline at end of file
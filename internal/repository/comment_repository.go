@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type commentRepository struct {
+	col *mongo.Collection
+}
+
+func NewCommentRepository(db *mongo.Database) domain.CommentRepository {
+	return &commentRepository{col: db.Collection("comments")}
+}
+
+func (r *commentRepository) Create(ctx context.Context, comment *domain.Comment) error {
+	comment.ID = bson.NewObjectID()
+	comment.CreatedAt = time.Now()
+	comment.UpdatedAt = time.Now()
+
+	_, err := r.col.InsertOne(ctx, comment)
+	return err
+}
+
+func (r *commentRepository) FindByID(ctx context.Context, id string) (*domain.Comment, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+
+	var comment domain.Comment
+	err = r.col.FindOne(ctx, bson.M{"_id": oid}).Decode(&comment)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	return &comment, err
+}
+
+func (r *commentRepository) FindByTaskID(ctx context.Context, taskID string) ([]domain.Comment, error) {
+	oid, err := bson.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.col.Find(ctx, bson.M{"task_id": oid}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var comments []domain.Comment
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (r *commentRepository) Update(ctx context.Context, comment *domain.Comment) error {
+	comment.UpdatedAt = time.Now()
+	_, err := r.col.ReplaceOne(ctx, bson.M{"_id": comment.ID}, comment)
+	return err
+}
+
+func (r *commentRepository) Delete(ctx context.Context, id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	_, err = r.col.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
+
+func (r *commentRepository) DeleteReplies(ctx context.Context, parentID string) error {
+	oid, err := bson.ObjectIDFromHex(parentID)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	_, err = r.col.DeleteMany(ctx, bson.M{"parent_id": oid})
+	return err
+}
+
+func (r *commentRepository) DeleteByTaskID(ctx context.Context, taskID string) error {
+	oid, err := bson.ObjectIDFromHex(taskID)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	_, err = r.col.DeleteMany(ctx, bson.M{"task_id": oid})
+	return err
+}
+
+func (r *commentRepository) FindByCreator(ctx context.Context, userID string) ([]domain.Comment, error) {
+	oid, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+	return findAll[domain.Comment](ctx, r.col, bson.M{"created_by": oid})
+}
+
+func (r *commentRepository) AnonymizeUser(ctx context.Context, userID string) error {
+	oid, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	if _, err := r.col.UpdateMany(ctx, bson.M{"created_by": oid}, bson.M{"$set": bson.M{"created_by": bson.ObjectID{}}}); err != nil {
+		return err
+	}
+	_, err = r.col.UpdateMany(ctx, bson.M{"mentions": oid}, bson.M{"$pull": bson.M{"mentions": oid}})
+	return err
+}
\ No newline at end of file
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type invitationRepository struct {
+	col *mongo.Collection
+}
+
+func NewInvitationRepository(db *mongo.Database) domain.InvitationRepository {
+	return &invitationRepository{col: db.Collection("invitations")}
+}
+
+func (r *invitationRepository) Create(ctx context.Context, invitation *domain.Invitation) error {
+	invitation.ID = bson.NewObjectID()
+	invitation.CreatedAt = time.Now()
+
+	_, err := r.col.InsertOne(ctx, invitation)
+	return err
+}
+
+func (r *invitationRepository) FindByID(ctx context.Context, id string) (*domain.Invitation, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+	return r.findOne(ctx, bson.M{"_id": oid})
+}
+
+func (r *invitationRepository) FindByTokenHash(ctx context.Context, hash string) (*domain.Invitation, error) {
+	return r.findOne(ctx, bson.M{"token_hash": hash})
+}
+
+func (r *invitationRepository) FindPendingByProjectID(ctx context.Context, projectID string, now time.Time) ([]domain.Invitation, error) {
+	oid, err := bson.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+	return findAll[domain.Invitation](ctx, r.col, bson.M{
+		"project_id": oid,
+		"status":     domain.InvitationPending,
+		"expires_at": bson.M{"$gt": now},
+	})
+}
+
+func (r *invitationRepository) FindPendingByEmail(ctx context.Context, email string, now time.Time) ([]domain.Invitation, error) {
+	return findAll[domain.Invitation](ctx, r.col, bson.M{
+		"email":      email,
+		"status":     domain.InvitationPending,
+		"expires_at": bson.M{"$gt": now},
+	})
+}
+
+func (r *invitationRepository) Update(ctx context.Context, invitation *domain.Invitation) error {
+	_, err := r.col.ReplaceOne(ctx, bson.M{"_id": invitation.ID}, invitation)
+	return err
+}
+
+func (r *invitationRepository) Delete(ctx context.Context, id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	_, err = r.col.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
+
+func (r *invitationRepository) AnonymizeUser(ctx context.Context, userID string) error {
+	oid, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	if _, err := r.col.UpdateMany(ctx, bson.M{"invited_by": oid}, bson.M{"$set": bson.M{"invited_by": bson.ObjectID{}}}); err != nil {
+		return err
+	}
+	_, err = r.col.UpdateMany(ctx, bson.M{"accepted_by": oid}, bson.M{"$set": bson.M{"accepted_by": bson.ObjectID{}}})
+	return err
+}
+
+func (r *invitationRepository) findOne(ctx context.Context, query bson.M) (*domain.Invitation, error) {
+	var invitation domain.Invitation
+	err := r.col.FindOne(ctx, query).Decode(&invitation)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	return &invitation, err
+}
\ No newline at end of file
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type joinLinkRepository struct {
+	col  *mongo.Collection
+	uses *mongo.Collection
+}
+
+func NewJoinLinkRepository(db *mongo.Database) domain.JoinLinkRepository {
+	return &joinLinkRepository{col: db.Collection("join_links"), uses: db.Collection("join_link_uses")}
+}
+
+func (r *joinLinkRepository) Create(ctx context.Context, link *domain.JoinLink) error {
+	link.ID = bson.NewObjectID()
+	link.CreatedAt = time.Now()
+
+	_, err := r.col.InsertOne(ctx, link)
+	return err
+}
+
+func (r *joinLinkRepository) FindByID(ctx context.Context, id string) (*domain.JoinLink, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+	return r.findOne(ctx, bson.M{"_id": oid})
+}
+
+func (r *joinLinkRepository) FindByTokenHash(ctx context.Context, hash string) (*domain.JoinLink, error) {
+	return r.findOne(ctx, bson.M{"token_hash": hash})
+}
+
+func (r *joinLinkRepository) FindByProjectID(ctx context.Context, projectID string) ([]domain.JoinLink, error) {
+	oid, err := bson.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+	return findAll[domain.JoinLink](ctx, r.col, bson.M{"project_id": oid})
+}
+
+// ClaimUse counts a use in the same write that checks the link is still
+// good, so concurrent joins can't overrun MaxUses.
+func (r *joinLinkRepository) ClaimUse(ctx context.Context, id bson.ObjectID, now time.Time) error {
+	query := bson.M{
+		"_id":        id,
+		"revoked_at": bson.M{"$exists": false},
+		"$and": bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"expires_at": bson.M{"$exists": false}},
+				bson.M{"expires_at": bson.M{"$gt": now}},
+			}},
+			bson.M{"$or": bson.A{
+				bson.M{"max_uses": 0},
+				bson.M{"$expr": bson.M{"$lt": bson.A{"$uses", "$max_uses"}}},
+			}},
+		},
+	}
+	result, err := r.col.UpdateOne(ctx, query, bson.M{"$inc": bson.M{"uses": 1}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *joinLinkRepository) ReleaseUse(ctx context.Context, id bson.ObjectID) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id, "uses": bson.M{"$gt": 0}}, bson.M{"$inc": bson.M{"uses": -1}})
+	return err
+}
+
+func (r *joinLinkRepository) Revoke(ctx context.Context, id bson.ObjectID, now time.Time) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"revoked_at": now}})
+	return err
+}
+
+func (r *joinLinkRepository) CreateUse(ctx context.Context, use *domain.JoinLinkUse) error {
+	use.ID = bson.NewObjectID()
+	use.CreatedAt = time.Now()
+
+	_, err := r.uses.InsertOne(ctx, use)
+	return err
+}
+
+func (r *joinLinkRepository) FindUses(ctx context.Context, linkID string) ([]domain.JoinLinkUse, error) {
+	oid, err := bson.ObjectIDFromHex(linkID)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+	return findAll[domain.JoinLinkUse](ctx, r.uses, bson.M{"link_id": oid})
+}
+
+func (r *joinLinkRepository) AnonymizeUser(ctx context.Context, userID string) error {
+	oid, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	if _, err := r.col.UpdateMany(ctx, bson.M{"created_by": oid}, bson.M{"$set": bson.M{"created_by": bson.ObjectID{}}}); err != nil {
+		return err
+	}
+	_, err = r.uses.UpdateMany(ctx, bson.M{"user_id": oid}, bson.M{"$set": bson.M{"user_id": bson.ObjectID{}}})
+	return err
+}
+
+func (r *joinLinkRepository) findOne(ctx context.Context, query bson.M) (*domain.JoinLink, error) {
+	var link domain.JoinLink
+	err := r.col.FindOne(ctx, query).Decode(&link)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	return &link, err
+}
\ No newline at end of file
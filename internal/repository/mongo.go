@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
@@ -23,4 +24,34 @@ func NewMongoClient(uri string) (*mongo.Client, error) {
 	}
 
 	return client, nil
+}
+
+// replaceExcept replaces the document with id by doc, except that the stored
+// values of the keep fields are left as they are. Those fields are changed
+// only by dedicated atomic updates, which a replace made from a stale copy
+// would otherwise undo.
+func replaceExcept(ctx context.Context, col *mongo.Collection, id bson.ObjectID, doc any, keep ...string) (*mongo.UpdateResult, error) {
+	kept := bson.M{}
+	for _, field := range keep {
+		kept[field] = "$" + field
+	}
+	// $literal stops values such as "$title" being read as field paths
+	replacement := bson.M{"$mergeObjects": bson.A{bson.M{"$literal": doc}, kept}}
+	return col.UpdateOne(ctx, bson.M{"_id": id}, mongo.Pipeline{{{Key: "$replaceWith", Value: replacement}}})
+}
+
+// findAll returns every document matching query, oldest first.
+func findAll[T any](ctx context.Context, col *mongo.Collection, query bson.M) ([]T, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := col.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	items := []T{}
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
\ No newline at end of file
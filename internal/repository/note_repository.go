@@ -41,23 +41,17 @@ func (r *noteRepository) FindByID(ctx context.Context, id string) (*domain.Note,
 	return &note, err
 }
 
-func (r *noteRepository) FindByProjectID(ctx context.Context, projectID string) ([]domain.Note, error) {
+func (r *noteRepository) FindByProjectID(ctx context.Context, projectID string, page domain.PageRequest) (*domain.Page[domain.Note], error) {
 	oid, err := bson.ObjectIDFromHex(projectID)
 	if err != nil {
 		return nil, domain.ErrInvalidInput
 	}
+	return findPage[domain.Note](ctx, r.col, bson.M{"project_id": oid}, page)
+}
 
-	cursor, err := r.col.Find(ctx, bson.M{"project_id": oid})
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var notes []domain.Note
-	if err := cursor.All(ctx, &notes); err != nil {
-		return nil, err
-	}
-	return notes, nil
+// Search matches note titles and content.
+func (r *noteRepository) Search(ctx context.Context, projectIDs []string, text string, limit int) ([]domain.TextMatch[domain.Note], error) {
+	return findText[domain.Note](ctx, r.col, projectIDs, text, limit)
 }
 
 func (r *noteRepository) Update(ctx context.Context, note *domain.Note) error {
@@ -73,4 +67,21 @@ func (r *noteRepository) Delete(ctx context.Context, id string) error {
 	}
 	_, err = r.col.DeleteOne(ctx, bson.M{"_id": oid})
 	return err
+}
+
+func (r *noteRepository) FindByCreator(ctx context.Context, userID string) ([]domain.Note, error) {
+	oid, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+	return findAll[domain.Note](ctx, r.col, bson.M{"created_by": oid})
+}
+
+func (r *noteRepository) AnonymizeUser(ctx context.Context, userID string) error {
+	oid, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	_, err = r.col.UpdateMany(ctx, bson.M{"created_by": oid}, bson.M{"$set": bson.M{"created_by": bson.ObjectID{}}})
+	return err
 }
\ No newline at end of file
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// pageCursor is the position after the last item of a page: its sort value
+// plus its ID as a tie-breaker. Sort records the order the page was read in,
+// since the position means nothing under another one.
+type pageCursor struct {
+	Sort  string        `bson:"s"`
+	Value bson.RawValue `bson:"v"`
+	ID    bson.ObjectID `bson:"id"`
+}
+
+// findPage runs query with keyset pagination over page.Sort, fetching one
+// extra document to tell whether another page exists.
+func findPage[T any](ctx context.Context, col *mongo.Collection, query bson.M, page domain.PageRequest) (*domain.Page[T], error) {
+	field, dir := strings.TrimPrefix(page.Sort, "-"), 1
+	if strings.HasPrefix(page.Sort, "-") {
+		dir = -1
+	}
+
+	if page.Cursor != "" {
+		cur, err := decodeCursor(page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if cur.Sort != page.Sort {
+			return nil, fmt.Errorf("cursor was issued for a different sort: %w", domain.ErrInvalidInput)
+		}
+		op := "$gt"
+		if dir < 0 {
+			op = "$lt"
+		}
+		after := bson.M{"$or": bson.A{
+			bson.M{field: bson.M{op: cur.Value}},
+			bson.M{field: cur.Value, "_id": bson.M{op: cur.ID}},
+		}}
+		query = bson.M{"$and": bson.A{query, after}}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: field, Value: dir}, {Key: "_id", Value: dir}}).
+		SetLimit(int64(page.Limit) + 1)
+	cursor, err := col.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var raws []bson.Raw
+	for cursor.Next(ctx) {
+		raws = append(raws, append(bson.Raw(nil), cursor.Current...))
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &domain.Page[T]{Items: make([]T, 0, min(len(raws), page.Limit))}
+	if len(raws) > page.Limit {
+		raws = raws[:page.Limit]
+		last := raws[len(raws)-1]
+		value := last.Lookup(field)
+		if value.Type == 0 {
+			value = bson.RawValue{Type: bson.TypeNull}
+		}
+		result.NextCursor, err = encodeCursor(pageCursor{Sort: page.Sort, Value: value, ID: last.Lookup("_id").ObjectID()})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, raw := range raws {
+		var item T
+		if err := bson.Unmarshal(raw, &item); err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, item)
+	}
+	return result, nil
+}
+
+func encodeCursor(c pageCursor) (string, error) {
+	b, err := bson.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeCursor(s string) (pageCursor, error) {
+	var c pageCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, domain.ErrInvalidInput
+	}
+	if err := bson.Unmarshal(b, &c); err != nil {
+		return c, domain.ErrInvalidInput
+	}
+	return c, nil
+}
\ No newline at end of file
@@ -3,11 +3,13 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/0DayMonxrch/project-management-system/internal/domain"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type projectRepository struct {
@@ -41,29 +43,117 @@ func (r *projectRepository) FindByID(ctx context.Context, id string) (*domain.Pr
 	return &project, err
 }
 
-func (r *projectRepository) FindByUserID(ctx context.Context, userID string) ([]domain.Project, error) {
+func (r *projectRepository) FindByUserID(ctx context.Context, userID string, page domain.PageRequest) (*domain.Page[domain.Project], error) {
 	oid, err := bson.ObjectIDFromHex(userID)
 	if err != nil {
 		return nil, domain.ErrInvalidInput
 	}
+	return findPage[domain.Project](ctx, r.col, bson.M{"members.user_id": oid}, page)
+}
+
+func (r *projectRepository) FindWithoutAdmin(ctx context.Context, page domain.PageRequest) (*domain.Page[domain.Project], error) {
+	query := bson.M{"members": bson.M{"$not": bson.M{"$elemMatch": bson.M{"role": domain.RoleAdmin}}}}
+	return findPage[domain.Project](ctx, r.col, query, page)
+}
 
-	cursor, err := r.col.Find(ctx, bson.M{"members.user_id": oid})
+func (r *projectRepository) Update(ctx context.Context, project *domain.Project) error {
+	project.UpdatedAt = time.Now()
+	_, err := replaceExcept(ctx, r.col, project.ID, project, "members", "created_by")
+	return err
+}
+
+func (r *projectRepository) SetOwner(ctx context.Context, projectID, userID bson.ObjectID) error {
+	query := bson.M{"_id": projectID, "members": bson.M{"$elemMatch": bson.M{"user_id": userID, "role": domain.RoleAdmin}}}
+	res, err := r.col.UpdateOne(ctx, query, bson.M{"$set": bson.M{"created_by": userID, "updated_at": time.Now()}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount > 0 {
+		return nil
+	}
+	n, err := r.col.CountDocuments(ctx, bson.M{"_id": projectID})
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer cursor.Close(ctx)
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return fmt.Errorf("new owner is no longer an admin of the project: %w", domain.ErrConflict)
+}
 
-	var projects []domain.Project
-	if err := cursor.All(ctx, &projects); err != nil {
-		return nil, err
+func (r *projectRepository) AddMember(ctx context.Context, projectID bson.ObjectID, member domain.ProjectMember) error {
+	query := bson.M{"_id": projectID, "members.user_id": bson.M{"$ne": member.UserID}}
+	res, err := r.col.UpdateOne(ctx, query, bson.M{
+		"$push": bson.M{"members": member},
+		"$set":  bson.M{"updated_at": time.Now()},
+	})
+	if err != nil {
+		return err
 	}
-	return projects, nil
+	if res.MatchedCount == 0 {
+		n, err := r.col.CountDocuments(ctx, bson.M{"_id": projectID})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("user is already a member: %w", domain.ErrConflict)
+	}
+	return nil
 }
 
-func (r *projectRepository) Update(ctx context.Context, project *domain.Project) error {
-	project.UpdatedAt = time.Now()
-	_, err := r.col.ReplaceOne(ctx, bson.M{"_id": project.ID}, project)
-	return err
+func (r *projectRepository) UpdateMemberRole(ctx context.Context, projectID, userID bson.ObjectID, role domain.Role) error {
+	query := bson.M{"_id": projectID, "members.user_id": userID}
+	if role != domain.RoleAdmin {
+		query = keepsAdmin(query, userID)
+	}
+	update := bson.M{"$set": bson.M{"members.$[m].role": role, "updated_at": time.Now()}}
+	opts := options.UpdateOne().SetArrayFilters([]any{bson.M{"m.user_id": userID}})
+	res, err := r.col.UpdateOne(ctx, query, update, opts)
+	if err != nil {
+		return err
+	}
+	return r.memberWriteResult(ctx, res, projectID, userID)
+}
+
+func (r *projectRepository) RemoveMember(ctx context.Context, projectID, userID bson.ObjectID) error {
+	query := keepsAdmin(bson.M{"_id": projectID, "members.user_id": userID}, userID)
+	res, err := r.col.UpdateOne(ctx, query, bson.M{
+		"$pull": bson.M{"members": bson.M{"user_id": userID}},
+		"$set":  bson.M{"updated_at": time.Now()},
+	})
+	if err != nil {
+		return err
+	}
+	return r.memberWriteResult(ctx, res, projectID, userID)
+}
+
+// keepsAdmin narrows query to projects where taking userID's role away
+// leaves an admin: either they aren't one, or someone else is too. Checking
+// in the write itself stops two admins demoting each other at once.
+func keepsAdmin(query bson.M, userID bson.ObjectID) bson.M {
+	query["$or"] = bson.A{
+		bson.M{"members": bson.M{"$elemMatch": bson.M{"user_id": userID, "role": bson.M{"$ne": domain.RoleAdmin}}}},
+		bson.M{"members": bson.M{"$elemMatch": bson.M{"user_id": bson.M{"$ne": userID}, "role": domain.RoleAdmin}}},
+	}
+	return query
+}
+
+// memberWriteResult tells a member who is gone from one the write refused
+// to change.
+func (r *projectRepository) memberWriteResult(ctx context.Context, res *mongo.UpdateResult, projectID, userID bson.ObjectID) error {
+	if res.MatchedCount > 0 {
+		return nil
+	}
+	n, err := r.col.CountDocuments(ctx, bson.M{"_id": projectID, "members.user_id": userID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return fmt.Errorf("project would be left without an admin: %w", domain.ErrConflict)
 }
 
 func (r *projectRepository) Delete(ctx context.Context, id string) error {
@@ -73,4 +163,13 @@ func (r *projectRepository) Delete(ctx context.Context, id string) error {
 	}
 	_, err = r.col.DeleteOne(ctx, bson.M{"_id": oid})
 	return err
+}
+
+func (r *projectRepository) AnonymizeUser(ctx context.Context, userID string) error {
+	oid, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	_, err = r.col.UpdateMany(ctx, bson.M{"created_by": oid}, bson.M{"$set": bson.M{"created_by": bson.ObjectID{}}})
+	return err
 }
\ No newline at end of file
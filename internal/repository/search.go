@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// findText runs a $text query restricted to projectIDs, best matches first.
+func findText[T any](ctx context.Context, col *mongo.Collection, projectIDs []string, text string, limit int) ([]domain.TextMatch[T], error) {
+	oids := make([]bson.ObjectID, 0, len(projectIDs))
+	for _, id := range projectIDs {
+		oid, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, domain.ErrInvalidInput
+		}
+		oids = append(oids, oid)
+	}
+	matches := []domain.TextMatch[T]{}
+	if len(oids) == 0 {
+		return matches, nil
+	}
+
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}}).
+		SetLimit(int64(limit))
+	cursor, err := col.Find(ctx, bson.M{"project_id": bson.M{"$in": oids}, "$text": bson.M{"$search": text}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var match domain.TextMatch[T]
+		if err := cursor.Decode(&match.Item); err != nil {
+			return nil, err
+		}
+		match.Score = cursor.Current.Lookup("score").Double()
+		matches = append(matches, match)
+	}
+	return matches, cursor.Err()
+}
\ No newline at end of file
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// previousHashLimit is how many rotated refresh tokens a session remembers
+// for reuse detection.
+const previousHashLimit = 32
+
+type sessionRepository struct {
+	col *mongo.Collection
+}
+
+func NewSessionRepository(db *mongo.Database) domain.SessionRepository {
+	return &sessionRepository{col: db.Collection("sessions")}
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *domain.Session) error {
+	session.ID = bson.NewObjectID()
+	session.CreatedAt = time.Now()
+	session.LastSeenAt = session.CreatedAt
+	if session.PreviousHashes == nil {
+		session.PreviousHashes = []string{}
+	}
+
+	_, err := r.col.InsertOne(ctx, session)
+	return err
+}
+
+func (r *sessionRepository) FindByTokenHash(ctx context.Context, hash string) (*domain.Session, error) {
+	query := bson.M{"$or": bson.A{
+		bson.M{"token_hash": hash},
+		bson.M{"previous_hashes": hash},
+	}}
+
+	var session domain.Session
+	err := r.col.FindOne(ctx, query).Decode(&session)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	return &session, err
+}
+
+func (r *sessionRepository) FindByID(ctx context.Context, id string) (*domain.Session, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+
+	var session domain.Session
+	err = r.col.FindOne(ctx, bson.M{"_id": oid}).Decode(&session)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	return &session, err
+}
+
+func (r *sessionRepository) FindByUserID(ctx context.Context, userID string) ([]domain.Session, error) {
+	oid, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+
+	query := bson.M{"user_id": oid, "expires_at": bson.M{"$gt": time.Now()}}
+	opts := options.Find().SetSort(bson.D{{Key: "last_seen_at", Value: -1}})
+	cursor, err := r.col.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	sessions := []domain.Session{}
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *sessionRepository) Rotate(ctx context.Context, id bson.ObjectID, oldHash, newHash string, client domain.ClientInfo, expiresAt time.Time) error {
+	update := bson.M{
+		"$set": bson.M{
+			"token_hash":   newHash,
+			"user_agent":   client.UserAgent,
+			"ip":           client.IP,
+			"last_seen_at": time.Now(),
+			"expires_at":   expiresAt,
+		},
+		"$push": bson.M{
+			"previous_hashes": bson.M{"$each": bson.A{oldHash}, "$slice": -previousHashLimit},
+		},
+	}
+
+	// Matching on the old hash makes concurrent rotations of one token fail
+	result, err := r.col.UpdateOne(ctx, bson.M{"_id": id, "token_hash": oldHash}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *sessionRepository) Delete(ctx context.Context, userID, id string) error {
+	userOID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+
+	result, err := r.col.DeleteOne(ctx, bson.M{"_id": oid, "user_id": userOID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *sessionRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	oid, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	_, err = r.col.DeleteMany(ctx, bson.M{"user_id": oid})
+	return err
+}
+
+func (r *sessionRepository) DeleteOthers(ctx context.Context, userID, keepID string) error {
+	oid, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	keepOID, err := bson.ObjectIDFromHex(keepID)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	_, err = r.col.DeleteMany(ctx, bson.M{"user_id": oid, "_id": bson.M{"$ne": keepOID}})
+	return err
+}
\ No newline at end of file
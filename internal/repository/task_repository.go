@@ -8,6 +8,7 @@ import (
 	"github.com/0DayMonxrch/project-management-system/internal/domain"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type taskRepository struct {
@@ -41,31 +42,117 @@ func (r *taskRepository) FindByID(ctx context.Context, id string) (*domain.Task,
 	return &task, err
 }
 
-func (r *taskRepository) FindByProjectID(ctx context.Context, projectID string) ([]domain.Task, error) {
-	oid, err := bson.ObjectIDFromHex(projectID)
+func (r *taskRepository) FindBySubTaskID(ctx context.Context, projectID, subTaskID string) (*domain.Task, error) {
+	projectOID, err := bson.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+	subTaskOID, err := bson.ObjectIDFromHex(subTaskID)
 	if err != nil {
 		return nil, domain.ErrInvalidInput
 	}
 
-	cursor, err := r.col.Find(ctx, bson.M{"project_id": oid})
+	var task domain.Task
+	err = r.col.FindOne(ctx, bson.M{"project_id": projectOID, "subtasks._id": subTaskOID}).Decode(&task)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	return &task, err
+}
+
+func (r *taskRepository) FindByProjectID(ctx context.Context, projectID string, filter domain.TaskFilter, page domain.PageRequest) (*domain.Page[domain.Task], error) {
+	oid, err := bson.ObjectIDFromHex(projectID)
 	if err != nil {
-		return nil, err
+		return nil, domain.ErrInvalidInput
 	}
-	defer cursor.Close(ctx)
 
-	var tasks []domain.Task
-	if err := cursor.All(ctx, &tasks); err != nil {
-		return nil, err
+	query := bson.M{"project_id": oid}
+	for field, hex := range map[string]string{"assigned_to": filter.AssignedTo, "created_by": filter.CreatedBy} {
+		if hex == "" {
+			continue
+		}
+		id, err := bson.ObjectIDFromHex(hex)
+		if err != nil {
+			return nil, domain.ErrInvalidInput
+		}
+		query[field] = id
 	}
-	return tasks, nil
+
+	status := bson.M{}
+	if filter.Status != "" {
+		status["$eq"] = filter.Status
+	}
+	if len(filter.ExcludeStatuses) > 0 {
+		status["$nin"] = filter.ExcludeStatuses
+	}
+	if len(status) > 0 {
+		query["status"] = status
+	}
+
+	due := bson.M{}
+	if !filter.DueFrom.IsZero() {
+		due["$gte"] = filter.DueFrom
+	}
+	if !filter.DueBefore.IsZero() {
+		due["$lt"] = filter.DueBefore
+	}
+	if len(due) > 0 {
+		query["due_date"] = due
+	}
+
+	return findPage[domain.Task](ctx, r.col, query, page)
+}
+
+// Search matches task titles, descriptions and subtask titles.
+func (r *taskRepository) Search(ctx context.Context, projectIDs []string, text string, limit int) ([]domain.TextMatch[domain.Task], error) {
+	return findText[domain.Task](ctx, r.col, projectIDs, text, limit)
 }
 
+func (r *taskRepository) CountByStatus(ctx context.Context, projectID string, statuses []domain.TaskStatus) (int64, error) {
+	oid, err := bson.ObjectIDFromHex(projectID)
+	if err != nil {
+		return 0, domain.ErrInvalidInput
+	}
+	return r.col.CountDocuments(ctx, bson.M{"project_id": oid, "status": bson.M{"$in": statuses}})
+}
+
+// Update replaces the task but keeps its stored attachments, which only
+// change through AddAttachment and RemoveAttachment so an edit made from a
+// stale copy can't drop a file uploaded in the meantime.
 func (r *taskRepository) Update(ctx context.Context, task *domain.Task) error {
 	task.UpdatedAt = time.Now()
-	_, err := r.col.ReplaceOne(ctx, bson.M{"_id": task.ID}, task)
+	_, err := replaceExcept(ctx, r.col, task.ID, task, "attachments")
 	return err
 }
 
+func (r *taskRepository) AddAttachment(ctx context.Context, taskID bson.ObjectID, a domain.Attachment) error {
+	res, err := r.col.UpdateOne(ctx, bson.M{"_id": taskID}, bson.M{
+		"$push": bson.M{"attachments": a},
+		"$set":  bson.M{"updated_at": time.Now()},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *taskRepository) RemoveAttachment(ctx context.Context, taskID, attachmentID bson.ObjectID) error {
+	res, err := r.col.UpdateOne(ctx, bson.M{"_id": taskID, "attachments._id": attachmentID}, bson.M{
+		"$pull": bson.M{"attachments": bson.M{"_id": attachmentID}},
+		"$set":  bson.M{"updated_at": time.Now()},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
 func (r *taskRepository) Delete(ctx context.Context, id string) error {
 	oid, err := bson.ObjectIDFromHex(id)
 	if err != nil {
@@ -73,4 +160,32 @@ func (r *taskRepository) Delete(ctx context.Context, id string) error {
 	}
 	_, err = r.col.DeleteOne(ctx, bson.M{"_id": oid})
 	return err
+}
+
+func (r *taskRepository) FindByUserID(ctx context.Context, userID string) ([]domain.Task, error) {
+	oid, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+	query := bson.M{"$or": bson.A{bson.M{"created_by": oid}, bson.M{"assigned_to": oid}}}
+	return findAll[domain.Task](ctx, r.col, query)
+}
+
+func (r *taskRepository) AnonymizeUser(ctx context.Context, userID string) error {
+	oid, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+
+	// Unassign rather than leave the task with a placeholder assignee
+	if _, err := r.col.UpdateMany(ctx, bson.M{"assigned_to": oid}, bson.M{"$set": bson.M{"assigned_to": bson.ObjectID{}}}); err != nil {
+		return err
+	}
+	if _, err := r.col.UpdateMany(ctx, bson.M{"created_by": oid}, bson.M{"$set": bson.M{"created_by": bson.ObjectID{}}}); err != nil {
+		return err
+	}
+	update := bson.M{"$set": bson.M{"attachments.$[a].uploaded_by": bson.ObjectID{}}}
+	opts := options.UpdateMany().SetArrayFilters([]any{bson.M{"a.uploaded_by": oid}})
+	_, err = r.col.UpdateMany(ctx, bson.M{"attachments.uploaded_by": oid}, update, opts)
+	return err
 }
\ No newline at end of file
@@ -71,8 +71,141 @@ func (r *userRepository) FindByResetToken(ctx context.Context, token string) (*d
 	return &user, err
 }
 
+func (r *userRepository) FindByUnlockToken(ctx context.Context, token string) (*domain.User, error) {
+	var user domain.User
+	err := r.col.FindOne(ctx, bson.M{"unlock_token": token}).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	return &user, err
+}
+
+func (r *userRepository) FindByEmailChangeToken(ctx context.Context, token string) (*domain.User, error) {
+	var user domain.User
+	err := r.col.FindOne(ctx, bson.M{"email_change_token": token}).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	return &user, err
+}
+
+func (r *userRepository) FindByIdentity(ctx context.Context, provider, subject string) (*domain.User, error) {
+	query := bson.M{"identities": bson.M{"$elemMatch": bson.M{"provider": provider, "subject": subject}}}
+
+	var user domain.User
+	err := r.col.FindOne(ctx, query).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	return &user, err
+}
+
+func (r *userRepository) FindByIDs(ctx context.Context, ids []string) ([]domain.User, error) {
+	oids := make([]bson.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		oid, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, domain.ErrInvalidInput
+		}
+		oids = append(oids, oid)
+	}
+
+	cursor, err := r.col.Find(ctx, bson.M{"_id": bson.M{"$in": oids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []domain.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// Update replaces the user but keeps the stored token version, recovery
+// codes and last TOTP step, so saving a copy read before a bump, a used
+// recovery code or a used TOTP code can't undo it.
 func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 	user.UpdatedAt = time.Now()
-	_, err := r.col.ReplaceOne(ctx, bson.M{"_id": user.ID}, user)
+	_, err := replaceExcept(ctx, r.col, user.ID, user, "token_version", "recovery_codes", "totp_last_step")
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrConflict
+	}
+	return err
+}
+
+func (r *userRepository) IncrementTokenVersion(ctx context.Context, id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	result, err := r.col.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$inc": bson.M{"token_version": 1}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) ClaimTOTPStep(ctx context.Context, id string, step int64) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	// Matching on an older step lets only one of two requests with the same code through
+	filter := bson.M{"_id": oid, "totp_last_step": bson.M{"$lt": step}}
+	result, err := r.col.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"totp_last_step": step}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) UseRecoveryCode(ctx context.Context, id, hash string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	filter := bson.M{"_id": oid, "recovery_codes": hash}
+	result, err := r.col.UpdateOne(ctx, filter, bson.M{"$pull": bson.M{"recovery_codes": hash}})
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) SetRecoveryCodes(ctx context.Context, id string, hashes []string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	if hashes == nil {
+		hashes = []string{}
+	}
+	result, err := r.col.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{"recovery_codes": hashes, "updated_at": time.Now()}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) Delete(ctx context.Context, id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	_, err = r.col.DeleteOne(ctx, bson.M{"_id": oid})
 	return err
 }
\ No newline at end of file
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type webhookDeliveryRepository struct {
+	col *mongo.Collection
+}
+
+func NewWebhookDeliveryRepository(db *mongo.Database) domain.WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{col: db.Collection("webhook_deliveries")}
+}
+
+func (r *webhookDeliveryRepository) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	delivery.ID = bson.NewObjectID()
+	delivery.CreatedAt = time.Now()
+
+	_, err := r.col.InsertOne(ctx, delivery)
+	return err
+}
+
+func (r *webhookDeliveryRepository) FindByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+
+	var delivery domain.WebhookDelivery
+	err = r.col.FindOne(ctx, bson.M{"_id": oid}).Decode(&delivery)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	return &delivery, err
+}
+
+func (r *webhookDeliveryRepository) FindByWebhookID(ctx context.Context, webhookID string, page domain.PageRequest) (*domain.Page[domain.WebhookDelivery], error) {
+	oid, err := bson.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+	return findPage[domain.WebhookDelivery](ctx, r.col, bson.M{"webhook_id": oid}, page)
+}
+
+func (r *webhookDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*domain.WebhookDelivery, error) {
+	query := bson.M{
+		"status":          domain.DeliveryPending,
+		"next_attempt_at": bson.M{"$lte": now},
+		"locked_until":    bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{"locked_until": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var delivery domain.WebhookDelivery
+	err := r.col.FindOneAndUpdate(ctx, query, update, opts).Decode(&delivery)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	return &delivery, err
+}
+
+func (r *webhookDeliveryRepository) Update(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	_, err := r.col.ReplaceOne(ctx, bson.M{"_id": delivery.ID}, delivery)
+	return err
+}
+
+func (r *webhookDeliveryRepository) DeleteByWebhookID(ctx context.Context, webhookID string) error {
+	oid, err := bson.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	_, err = r.col.DeleteMany(ctx, bson.M{"webhook_id": oid})
+	return err
+}
\ No newline at end of file
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type webhookRepository struct {
+	col *mongo.Collection
+}
+
+func NewWebhookRepository(db *mongo.Database) domain.WebhookRepository {
+	return &webhookRepository{col: db.Collection("webhooks")}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, hook *domain.Webhook) error {
+	hook.ID = bson.NewObjectID()
+	hook.CreatedAt = time.Now()
+	hook.UpdatedAt = time.Now()
+
+	_, err := r.col.InsertOne(ctx, hook)
+	return err
+}
+
+func (r *webhookRepository) FindByID(ctx context.Context, id string) (*domain.Webhook, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+
+	var hook domain.Webhook
+	err = r.col.FindOne(ctx, bson.M{"_id": oid}).Decode(&hook)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	return &hook, err
+}
+
+func (r *webhookRepository) FindByProjectID(ctx context.Context, projectID string) ([]domain.Webhook, error) {
+	oid, err := bson.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+	return r.find(ctx, bson.M{"project_id": oid})
+}
+
+// FindSubscribed returns the project's active webhooks subscribed to any of eventTypes.
+func (r *webhookRepository) FindSubscribed(ctx context.Context, projectID string, eventTypes []domain.EventType) ([]domain.Webhook, error) {
+	oid, err := bson.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+	return r.find(ctx, bson.M{"project_id": oid, "active": true, "events": bson.M{"$in": eventTypes}})
+}
+
+func (r *webhookRepository) Update(ctx context.Context, hook *domain.Webhook) error {
+	hook.UpdatedAt = time.Now()
+	_, err := r.col.ReplaceOne(ctx, bson.M{"_id": hook.ID}, hook)
+	return err
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	_, err = r.col.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
+
+func (r *webhookRepository) AnonymizeUser(ctx context.Context, userID string) error {
+	oid, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	_, err = r.col.UpdateMany(ctx, bson.M{"created_by": oid}, bson.M{"$set": bson.M{"created_by": bson.ObjectID{}}})
+	return err
+}
+
+func (r *webhookRepository) find(ctx context.Context, query bson.M) ([]domain.Webhook, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.col.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	hooks := []domain.Webhook{}
+	if err := cursor.All(ctx, &hooks); err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
\ No newline at end of file
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	maxTokenLifetime = 365 * 24 * time.Hour
+	maxTokensPerUser = 50
+
+	// lastUsedPrecision bounds how often a token's LastUsedAt is written.
+	lastUsedPrecision = time.Minute
+)
+
+type accessTokenService struct {
+	tokenRepo   domain.AccessTokenRepository
+	projectRepo domain.ProjectRepository
+	auditRepo   domain.AuditRepository
+}
+
+func NewAccessTokenService(tokenRepo domain.AccessTokenRepository, projectRepo domain.ProjectRepository, auditRepo domain.AuditRepository) domain.AccessTokenService {
+	return &accessTokenService{tokenRepo: tokenRepo, projectRepo: projectRepo, auditRepo: auditRepo}
+}
+
+func (s *accessTokenService) CreateToken(ctx context.Context, userID, name string, scope domain.TokenScope, projectIDs []string, expiresAt time.Time) (*domain.AccessToken, string, error) {
+	if scope != domain.ScopeRead && scope != domain.ScopeReadWrite {
+		return nil, "", fmt.Errorf("scope must be %q or %q: %w", domain.ScopeRead, domain.ScopeReadWrite, domain.ErrInvalidInput)
+	}
+	now := time.Now()
+	if !expiresAt.After(now) || expiresAt.Sub(now) > maxTokenLifetime {
+		return nil, "", fmt.Errorf("expires_at must be in the future and within 365 days: %w", domain.ErrInvalidInput)
+	}
+
+	existing, err := s.tokenRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(existing) >= maxTokensPerUser {
+		return nil, "", fmt.Errorf("at most %d tokens per user: %w", maxTokensPerUser, domain.ErrConflict)
+	}
+
+	projectOIDs, err := s.memberProjects(ctx, userID, projectIDs)
+	if err != nil {
+		return nil, "", err
+	}
+
+	secret, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+	raw := domain.AccessTokenPrefix + secret
+
+	userOID, _ := bson.ObjectIDFromHex(userID)
+	token := &domain.AccessToken{
+		UserID:     userOID,
+		Name:       name,
+		Prefix:     raw[:len(domain.AccessTokenPrefix)+8],
+		TokenHash:  hashToken(raw),
+		Scope:      scope,
+		ProjectIDs: projectOIDs,
+		ExpiresAt:  expiresAt,
+	}
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		return nil, "", err
+	}
+
+	changes := []domain.FieldChange{{Field: "name", To: name}, {Field: "scope", To: string(scope)}}
+	if err := recordAudit(ctx, s.auditRepo, bson.ObjectID{}, userID, domain.AuditTokenCreated, "access_token", token.ID.Hex(), changes); err != nil {
+		return nil, "", err
+	}
+	return token, raw, nil
+}
+
+func (s *accessTokenService) ListTokens(ctx context.Context, userID string) ([]domain.AccessToken, error) {
+	return s.tokenRepo.FindByUserID(ctx, userID)
+}
+
+func (s *accessTokenService) RevokeToken(ctx context.Context, userID, tokenID string) error {
+	tokens, err := s.tokenRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(tokens, func(t domain.AccessToken) bool { return t.ID.Hex() == tokenID })
+	if i < 0 {
+		return domain.ErrNotFound
+	}
+
+	if err := s.tokenRepo.Delete(ctx, tokenID); err != nil {
+		return err
+	}
+	changes := []domain.FieldChange{{Field: "name", From: tokens[i].Name}}
+	return recordAudit(ctx, s.auditRepo, bson.ObjectID{}, userID, domain.AuditTokenRevoked, "access_token", tokenID, changes)
+}
+
+func (s *accessTokenService) Authenticate(ctx context.Context, raw string) (*domain.AccessToken, error) {
+	if !strings.HasPrefix(raw, domain.AccessTokenPrefix) {
+		return nil, domain.ErrTokenInvalid
+	}
+	token, err := s.tokenRepo.FindByHash(ctx, hashToken(raw))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !now.Before(token.ExpiresAt) {
+		return nil, domain.ErrTokenExpired
+	}
+	if err := s.tokenRepo.TouchLastUsed(ctx, token.ID, now, lastUsedPrecision); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// --- helpers ---
+
+// memberProjects resolves projectIDs, requiring the user to belong to each.
+func (s *accessTokenService) memberProjects(ctx context.Context, userID string, projectIDs []string) ([]bson.ObjectID, error) {
+	oids := []bson.ObjectID{}
+	for _, id := range slices.Compact(slices.Sorted(slices.Values(projectIDs))) {
+		project, err := s.projectRepo.FindByID(ctx, id)
+		if errors.Is(err, domain.ErrNotFound) || (err == nil && !isMember(project, userID)) {
+			return nil, fmt.Errorf("not a member of project %s: %w", id, domain.ErrInvalidInput)
+		}
+		if err != nil {
+			return nil, err
+		}
+		oids = append(oids, project.ID)
+	}
+	return oids, nil
+}
\ No newline at end of file
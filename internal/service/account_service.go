@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// reauthWindow is how recently an account without a password must have
+// signed in to delete itself.
+const reauthWindow = 10 * time.Minute
+
+type accountService struct {
+	userRepo       domain.UserRepository
+	sessionRepo    domain.SessionRepository
+	tokenRepo      domain.AccessTokenRepository
+	projectRepo    domain.ProjectRepository
+	taskRepo       domain.TaskRepository
+	noteRepo       domain.NoteRepository
+	commentRepo    domain.CommentRepository
+	activityRepo   domain.ActivityRepository
+	invitationRepo domain.InvitationRepository
+	joinLinkRepo   domain.JoinLinkRepository
+	webhookRepo    domain.WebhookRepository
+	auditRepo      domain.AuditRepository
+	versions       domain.TokenVersionCache
+	events         domain.EventBus
+	log            *slog.Logger
+}
+
+func NewAccountService(userRepo domain.UserRepository, sessionRepo domain.SessionRepository, tokenRepo domain.AccessTokenRepository, projectRepo domain.ProjectRepository, taskRepo domain.TaskRepository, noteRepo domain.NoteRepository, commentRepo domain.CommentRepository, activityRepo domain.ActivityRepository, invitationRepo domain.InvitationRepository, joinLinkRepo domain.JoinLinkRepository, webhookRepo domain.WebhookRepository, auditRepo domain.AuditRepository, versions domain.TokenVersionCache, events domain.EventBus, log *slog.Logger) domain.AccountService {
+	return &accountService{
+		userRepo:       userRepo,
+		sessionRepo:    sessionRepo,
+		tokenRepo:      tokenRepo,
+		projectRepo:    projectRepo,
+		taskRepo:       taskRepo,
+		noteRepo:       noteRepo,
+		commentRepo:    commentRepo,
+		activityRepo:   activityRepo,
+		invitationRepo: invitationRepo,
+		joinLinkRepo:   joinLinkRepo,
+		webhookRepo:    webhookRepo,
+		auditRepo:      auditRepo,
+		versions:       versions,
+		events:         events,
+		log:            log,
+	}
+}
+
+func (s *accountService) ExportData(ctx context.Context, userID string) (*domain.AccountExport, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &domain.AccountExport{ExportedAt: time.Now(), User: user}
+	if export.Sessions, err = s.sessionRepo.FindByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.AccessTokens, err = s.tokenRepo.FindByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.Projects, err = s.memberProjects(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.Tasks, err = s.taskRepo.FindByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.Notes, err = s.noteRepo.FindByCreator(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.Comments, err = s.commentRepo.FindByCreator(ctx, userID); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+func (s *accountService) DeleteAccount(ctx context.Context, userID, sessionID, password, code string) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.reauthenticate(ctx, user, sessionID, password, code); err != nil {
+		return err
+	}
+
+	projects, err := s.memberProjects(ctx, userID)
+	if err != nil {
+		return err
+	}
+	var soleAdminOf []string
+	for _, p := range projects {
+		if isSoleAdmin(&p, userID) {
+			soleAdminOf = append(soleAdminOf, p.Name)
+		}
+	}
+	if len(soleAdminOf) > 0 {
+		return fmt.Errorf("you are the only admin of %s; make another member admin or delete the project first: %w", strings.Join(soleAdminOf, ", "), domain.ErrConflict)
+	}
+
+	for _, p := range projects {
+		if err := s.leaveProject(ctx, &p, user.ID); err != nil {
+			return err
+		}
+	}
+
+	// Replace references rather than leave IDs that resolve to nobody
+	if err := s.projectRepo.AnonymizeUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.taskRepo.AnonymizeUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.noteRepo.AnonymizeUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.commentRepo.AnonymizeUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.activityRepo.AnonymizeUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.invitationRepo.AnonymizeUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.joinLinkRepo.AnonymizeUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.webhookRepo.AnonymizeUser(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.DeleteByUserID(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.tokenRepo.DeleteByUserID(ctx, userID); err != nil {
+		return err
+	}
+	// Deleted last, so a failed attempt can simply be retried
+	if err := s.userRepo.Delete(ctx, userID); err != nil {
+		return err
+	}
+	s.versions.Invalidate(userID)
+	// The account is gone either way, so a failed audit write is only logged
+	if err := recordAudit(ctx, s.auditRepo, bson.ObjectID{}, userID, domain.AuditAccountDeleted, "user", userID, nil); err != nil {
+		s.log.Error("failed to record account deletion", "user_id", userID, "error", err)
+	}
+	return nil
+}
+
+// --- helpers ---
+
+// reauthenticate makes sure the owner is at the keyboard, not just someone
+// holding their session: the password if the account has one, otherwise a
+// sign-in within reauthWindow, and the second factor if it is enabled.
+func (s *accountService) reauthenticate(ctx context.Context, user *domain.User, sessionID, password, code string) error {
+	if user.Password != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+			return domain.ErrUnauthorized
+		}
+	} else if !user.TOTPEnabled {
+		// Accounts created through SSO have no password, so the provider
+		// has to have vouched for the user just now
+		session, err := s.sessionRepo.FindByID(ctx, sessionID)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+		if err != nil || session.UserID != user.ID || time.Since(session.CreatedAt) > reauthWindow {
+			return fmt.Errorf("sign in again, then delete the account within %d minutes: %w", int(reauthWindow.Minutes()), domain.ErrForbidden)
+		}
+	}
+
+	if user.TOTPEnabled {
+		return checkSecondFactor(ctx, s.userRepo, s.auditRepo, user, code)
+	}
+	return nil
+}
+
+// memberProjects walks every page of the user's projects.
+func (s *accountService) memberProjects(ctx context.Context, userID string) ([]domain.Project, error) {
+	projects := []domain.Project{}
+	page := domain.PageRequest{Limit: maxPageSize, Sort: defaultPageSort}
+	for {
+		result, err := s.projectRepo.FindByUserID(ctx, userID, page)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, result.Items...)
+		if result.NextCursor == "" {
+			return projects, nil
+		}
+		page.Cursor = result.NextCursor
+	}
+}
+
+// leaveProject removes a deleted user from a project's members. The write
+// is refused if another request has taken away the project's other admins
+// since it was loaded.
+func (s *accountService) leaveProject(ctx context.Context, project *domain.Project, userID bson.ObjectID) error {
+	i := slices.IndexFunc(project.Members, func(m domain.ProjectMember) bool { return m.UserID == userID })
+	if i < 0 {
+		return nil
+	}
+	member := project.Members[i]
+	err := s.projectRepo.RemoveMember(ctx, project.ID, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if errors.Is(err, domain.ErrConflict) {
+		return fmt.Errorf("you are now the only admin of %s; make another member admin or delete the project first: %w", project.Name, domain.ErrConflict)
+	}
+	if err != nil {
+		return err
+	}
+
+	changes := []domain.FieldChange{{Field: "role", From: string(member.Role)}}
+	if err := recordAudit(ctx, s.auditRepo, project.ID, userID.Hex(), domain.AuditMemberRemoved, "user", userID.Hex(), changes); err != nil {
+		return err
+	}
+	publishEvent(ctx, s.events, project.ID, userID.Hex(), domain.EventMemberRemoved, userID, changes, nil)
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/pkg/totp"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// anonymized records which stores had a deleted user's ID replaced.
+type anonymized []string
+
+func (a *anonymized) add(store string) error {
+	*a = append(*a, store)
+	return nil
+}
+
+type deletionUserRepo struct {
+	memUserRepo
+}
+
+func (r *deletionUserRepo) Delete(ctx context.Context, id string) error {
+	delete(r.users, id)
+	return nil
+}
+
+func (r *deletionUserRepo) ClaimTOTPStep(ctx context.Context, id string, step int64) error {
+	u := r.users[id]
+	if step <= u.TOTPLastStep {
+		return domain.ErrNotFound
+	}
+	u.TOTPLastStep = step
+	return nil
+}
+
+type deletionSessionRepo struct {
+	domain.SessionRepository
+	sessions map[string]*domain.Session
+}
+
+func (r deletionSessionRepo) FindByID(ctx context.Context, id string) (*domain.Session, error) {
+	if s, ok := r.sessions[id]; ok {
+		return s, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r deletionSessionRepo) DeleteByUserID(ctx context.Context, userID string) error { return nil }
+
+type deletionTokenRepo struct{ domain.AccessTokenRepository }
+
+func (deletionTokenRepo) DeleteByUserID(ctx context.Context, userID string) error { return nil }
+
+type deletionProjectRepo struct {
+	domain.ProjectRepository
+	log *anonymized
+}
+
+func (deletionProjectRepo) FindByUserID(ctx context.Context, userID string, page domain.PageRequest) (*domain.Page[domain.Project], error) {
+	return &domain.Page[domain.Project]{}, nil
+}
+
+func (r deletionProjectRepo) AnonymizeUser(ctx context.Context, userID string) error {
+	return r.log.add("projects")
+}
+
+// deletionAuditFails fails to record the account deletion itself.
+type deletionAuditFails struct{ domain.AuditRepository }
+
+func (deletionAuditFails) Create(ctx context.Context, entry *domain.AuditEntry) error {
+	if entry.Action == domain.AuditAccountDeleted {
+		return errors.New("audit write failed")
+	}
+	return nil
+}
+
+type anonTaskRepo struct {
+	domain.TaskRepository
+	log *anonymized
+}
+
+func (r anonTaskRepo) AnonymizeUser(ctx context.Context, userID string) error {
+	return r.log.add("tasks")
+}
+
+type anonNoteRepo struct {
+	domain.NoteRepository
+	log *anonymized
+}
+
+func (r anonNoteRepo) AnonymizeUser(ctx context.Context, userID string) error {
+	return r.log.add("notes")
+}
+
+type anonCommentRepo struct {
+	domain.CommentRepository
+	log *anonymized
+}
+
+func (r anonCommentRepo) AnonymizeUser(ctx context.Context, userID string) error {
+	return r.log.add("comments")
+}
+
+type anonActivityRepo struct {
+	domain.ActivityRepository
+	log *anonymized
+}
+
+func (r anonActivityRepo) AnonymizeUser(ctx context.Context, userID string) error {
+	return r.log.add("activity")
+}
+
+type anonInvitationRepo struct {
+	domain.InvitationRepository
+	log *anonymized
+}
+
+func (r anonInvitationRepo) AnonymizeUser(ctx context.Context, userID string) error {
+	return r.log.add("invitations")
+}
+
+type anonJoinLinkRepo struct {
+	domain.JoinLinkRepository
+	log *anonymized
+}
+
+func (r anonJoinLinkRepo) AnonymizeUser(ctx context.Context, userID string) error {
+	return r.log.add("join_links")
+}
+
+type anonWebhookRepo struct {
+	domain.WebhookRepository
+	log *anonymized
+}
+
+func (r anonWebhookRepo) AnonymizeUser(ctx context.Context, userID string) error {
+	return r.log.add("webhooks")
+}
+
+type nopVersions struct{ domain.TokenVersionCache }
+
+func (nopVersions) Invalidate(userID string) {}
+
+type deletionFixture struct {
+	svc        domain.AccountService
+	users      *deletionUserRepo
+	sessions   deletionSessionRepo
+	user       *domain.User
+	sessionID  string
+	anonymized *anonymized
+}
+
+// newDeletionFixture sets up user, signed in on a session that started
+// signedInAgo.
+func newDeletionFixture(t *testing.T, user *domain.User, signedInAgo time.Duration) *deletionFixture {
+	t.Helper()
+	user.ID = bson.NewObjectID()
+	users := &deletionUserRepo{memUserRepo{users: map[string]*domain.User{user.ID.Hex(): user}}}
+	session := &domain.Session{ID: bson.NewObjectID(), UserID: user.ID, CreatedAt: time.Now().Add(-signedInAgo)}
+	sessions := deletionSessionRepo{sessions: map[string]*domain.Session{session.ID.Hex(): session}}
+
+	f := &deletionFixture{users: users, sessions: sessions, user: user, sessionID: session.ID.Hex(), anonymized: &anonymized{}}
+	f.svc = f.service(deletionProjectRepo{log: f.anonymized}, nopAuditRepo{})
+	return f
+}
+
+func (f *deletionFixture) service(projects domain.ProjectRepository, audit domain.AuditRepository) domain.AccountService {
+	log := f.anonymized
+	return NewAccountService(f.users, f.sessions, deletionTokenRepo{}, projects, anonTaskRepo{log: log}, anonNoteRepo{log: log}, anonCommentRepo{log: log}, anonActivityRepo{log: log}, anonInvitationRepo{log: log}, anonJoinLinkRepo{log: log}, anonWebhookRepo{log: log}, audit, nopVersions{}, nopEventBus{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func (f *deletionFixture) deleted() bool {
+	_, ok := f.users.users[f.user.ID.Hex()]
+	return !ok
+}
+
+func newTOTPSecret(t *testing.T) string {
+	t.Helper()
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return secret
+}
+
+func currentCode(t *testing.T, secret string) string {
+	t.Helper()
+	code, err := totp.Code(secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func TestDeleteAccountReauthentication(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := newTOTPSecret(t)
+
+	tests := []struct {
+		name        string
+		user        domain.User
+		signedInAgo time.Duration
+		password    string
+		code        string
+		want        error
+	}{
+		{name: "password", user: domain.User{Password: string(hash)}, signedInAgo: time.Hour, password: "correct horse"},
+		{name: "wrong password", user: domain.User{Password: string(hash)}, password: "wrong", want: domain.ErrUnauthorized},
+		{name: "password without 2FA code", user: domain.User{Password: string(hash), TOTPEnabled: true, TOTPSecret: secret}, password: "correct horse", want: domain.ErrUnauthorized},
+		{name: "password and 2FA code", user: domain.User{Password: string(hash), TOTPEnabled: true, TOTPSecret: secret}, password: "correct horse", code: "current"},
+		{name: "SSO with recent sign-in", user: domain.User{}, signedInAgo: time.Minute},
+		{name: "SSO with stale sign-in", user: domain.User{}, signedInAgo: time.Hour, want: domain.ErrForbidden},
+		{name: "SSO ignores the password field", user: domain.User{}, signedInAgo: time.Hour, password: "anything", want: domain.ErrForbidden},
+		{name: "SSO with 2FA needs the code", user: domain.User{TOTPEnabled: true, TOTPSecret: secret}, signedInAgo: time.Minute, want: domain.ErrUnauthorized},
+		{name: "SSO with 2FA code", user: domain.User{TOTPEnabled: true, TOTPSecret: secret}, signedInAgo: time.Hour, code: "current"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := tt.user
+			f := newDeletionFixture(t, &user, tt.signedInAgo)
+			code := tt.code
+			if code == "current" {
+				code = currentCode(t, secret)
+			}
+
+			err := f.svc.DeleteAccount(context.Background(), user.ID.Hex(), f.sessionID, tt.password, code)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("DeleteAccount: %v", err)
+				}
+				if !f.deleted() {
+					t.Fatal("account was not deleted")
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("DeleteAccount error = %v, want %v", err, tt.want)
+			}
+			if f.deleted() {
+				t.Fatal("account was deleted without re-authentication")
+			}
+		})
+	}
+}
+
+func TestDeleteAccountRejectsAnotherUsersSession(t *testing.T) {
+	f := newDeletionFixture(t, &domain.User{}, time.Minute)
+	other := newDeletionFixture(t, &domain.User{}, time.Minute)
+
+	err := f.svc.DeleteAccount(context.Background(), f.user.ID.Hex(), other.sessionID, "", "")
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("DeleteAccount error = %v, want ErrForbidden", err)
+	}
+}
+
+func TestDeleteAccountAnonymizesReferences(t *testing.T) {
+	f := newDeletionFixture(t, &domain.User{}, time.Minute)
+
+	if err := f.svc.DeleteAccount(context.Background(), f.user.ID.Hex(), f.sessionID, "", ""); err != nil {
+		t.Fatalf("DeleteAccount: %v", err)
+	}
+	for _, store := range []string{"projects", "tasks", "notes", "comments", "activity", "invitations", "join_links", "webhooks"} {
+		if !slices.Contains(*f.anonymized, store) {
+			t.Errorf("%s still reference the deleted user", store)
+		}
+	}
+}
+
+func TestDeleteAccountLeavesProjectsAtomically(t *testing.T) {
+	f := newDeletionFixture(t, &domain.User{}, time.Minute)
+	other, newcomer := bson.NewObjectID(), bson.NewObjectID()
+	project := &domain.Project{ID: bson.NewObjectID(), Name: "Apollo", Members: []domain.ProjectMember{
+		{UserID: f.user.ID, Role: domain.RoleAdmin},
+		{UserID: other, Role: domain.RoleAdmin},
+	}}
+	projects := &staleProjectRepo{memProjectRepo: newMemProjectRepo(project), snapshot: *project}
+	projects.snapshot.Members = slices.Clone(project.Members)
+	// Since the snapshot, someone joined and the other admin was demoted
+	project.Members = append(project.Members, domain.ProjectMember{UserID: newcomer, Role: domain.RoleMember})
+	project.Members[1].Role = domain.RoleMember
+
+	err := f.service(projects, nopAuditRepo{}).DeleteAccount(context.Background(), f.user.ID.Hex(), f.sessionID, "", "")
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("DeleteAccount error = %v, want ErrConflict", err)
+	}
+	if f.deleted() || len(project.Members) != 3 {
+		t.Fatalf("deleted=%v with %d members, want the account and project untouched", f.deleted(), len(project.Members))
+	}
+
+	// With an admin again, leaving keeps the newcomer, and a failed audit
+	// write once the account is gone doesn't fail the request
+	project.Members[1].Role = domain.RoleAdmin
+	if err := f.service(projects, deletionAuditFails{}).DeleteAccount(context.Background(), f.user.ID.Hex(), f.sessionID, "", ""); err != nil {
+		t.Fatalf("DeleteAccount: %v", err)
+	}
+	if !f.deleted() || len(project.Members) != 2 || !isMember(project, newcomer.Hex()) {
+		t.Fatalf("deleted=%v, members %v, want the account gone and the newcomer kept", f.deleted(), project.Members)
+	}
+}
\ No newline at end of file
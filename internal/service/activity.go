@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// recordActivity appends an entry to the task's change log.
+func recordActivity(ctx context.Context, repo domain.ActivityRepository, task *domain.Task, actorID string, action domain.ActivityAction, targetID bson.ObjectID, changes []domain.FieldChange) error {
+	actorOID, _ := bson.ObjectIDFromHex(actorID)
+	if changes == nil {
+		changes = []domain.FieldChange{}
+	}
+	return repo.Create(ctx, &domain.TaskActivity{
+		ProjectID: task.ProjectID,
+		TaskID:    task.ID,
+		TargetID:  targetID,
+		ActorID:   actorOID,
+		Action:    action,
+		Changes:   changes,
+	})
+}
+
+// diffTask lists the user-editable task fields that differ between before and after.
+func diffTask(before, after *domain.Task) []domain.FieldChange {
+	var changes []domain.FieldChange
+	changes = appendChange(changes, "title", before.Title, after.Title)
+	changes = appendChange(changes, "description", before.Description, after.Description)
+	changes = appendChange(changes, "status", string(before.Status), string(after.Status))
+	changes = appendChange(changes, "assigned_to", formatID(before.AssignedTo), formatID(after.AssignedTo))
+	changes = appendChange(changes, "start_date", formatDate(before.StartDate), formatDate(after.StartDate))
+	changes = appendChange(changes, "due_date", formatDate(before.DueDate), formatDate(after.DueDate))
+	return changes
+}
+
+// diffSubTask lists the subtask fields that differ between before and after.
+func diffSubTask(before, after domain.SubTask) []domain.FieldChange {
+	var changes []domain.FieldChange
+	changes = appendChange(changes, "title", before.Title, after.Title)
+	changes = appendChange(changes, "is_completed", strconv.FormatBool(before.IsCompleted), strconv.FormatBool(after.IsCompleted))
+	changes = appendChange(changes, "start_date", formatDate(before.StartDate), formatDate(after.StartDate))
+	changes = appendChange(changes, "due_date", formatDate(before.DueDate), formatDate(after.DueDate))
+	return changes
+}
+
+func appendChange(changes []domain.FieldChange, field, from, to string) []domain.FieldChange {
+	if from == to {
+		return changes
+	}
+	return append(changes, domain.FieldChange{Field: field, From: from, To: to})
+}
+
+func formatID(id bson.ObjectID) string {
+	if id.IsZero() {
+		return ""
+	}
+	return id.Hex()
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
\ No newline at end of file
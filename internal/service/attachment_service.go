@@ -0,0 +1,162 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/config"
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type attachmentService struct {
+	taskRepo     domain.TaskRepository
+	projectRepo  domain.ProjectRepository
+	activityRepo domain.ActivityRepository
+	storage      domain.BlobStorage
+	cfg          config.UploadConfig
+	events       domain.EventBus
+	log          *slog.Logger
+}
+
+func NewAttachmentService(taskRepo domain.TaskRepository, projectRepo domain.ProjectRepository, activityRepo domain.ActivityRepository, storage domain.BlobStorage, cfg config.UploadConfig, events domain.EventBus, log *slog.Logger) domain.AttachmentService {
+	return &attachmentService{taskRepo: taskRepo, projectRepo: projectRepo, activityRepo: activityRepo, storage: storage, cfg: cfg, events: events, log: log}
+}
+
+func (s *attachmentService) UploadAttachment(ctx context.Context, projectID, taskID, requesterID, filename string, content io.Reader) (*domain.Attachment, error) {
+	_, task, err := findProjectTask(ctx, s.projectRepo, s.taskRepo, projectID, taskID, requesterID, domain.PermAttachmentWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read one byte past the limit so oversized files can be detected
+	maxBytes := int64(s.cfg.MaxSizeMB) << 20
+	data, err := io.ReadAll(io.LimitReader(content, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("%w: maximum size is %d MB", domain.ErrFileTooLarge, s.cfg.MaxSizeMB)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("file is empty: %w", domain.ErrInvalidInput)
+	}
+
+	// Trust the bytes, not the client-supplied Content-Type
+	mimeType := http.DetectContentType(data)
+	if !slices.Contains(s.cfg.AllowedTypes, mimeType) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnsupportedMedia, mimeType)
+	}
+
+	requesterOID, _ := bson.ObjectIDFromHex(requesterID)
+	attachment := domain.Attachment{
+		ID:         bson.NewObjectID(),
+		Name:       sanitizeFilename(filename),
+		MimeType:   mimeType,
+		Size:       int64(len(data)),
+		UploadedBy: requesterOID,
+		UploadedAt: time.Now(),
+	}
+	attachment.StorageKey = fmt.Sprintf("%s/%s/%s", projectID, taskID, attachment.ID.Hex())
+	attachment.URL = fmt.Sprintf("/api/v1/tasks/%s/t/%s/attachments/%s", projectID, taskID, attachment.ID.Hex())
+
+	if err := s.storage.Save(ctx, attachment.StorageKey, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	if err := s.taskRepo.AddAttachment(ctx, task.ID, attachment); err != nil {
+		// Don't leave an orphaned file behind
+		_ = s.storage.Delete(ctx, attachment.StorageKey)
+		return nil, err
+	}
+
+	changes := []domain.FieldChange{{Field: "attachment", To: attachment.Name}}
+	s.record(ctx, task, requesterID, domain.ActivityAttachmentAdded, attachment.ID, changes, attachment)
+	return &attachment, nil
+}
+
+func (s *attachmentService) DownloadAttachment(ctx context.Context, projectID, taskID, attachmentID, requesterID string) (*domain.Attachment, io.ReadCloser, error) {
+	_, task, err := findProjectTask(ctx, s.projectRepo, s.taskRepo, projectID, taskID, requesterID, domain.PermTaskView)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, a := range task.Attachments {
+		if a.ID.Hex() == attachmentID {
+			content, err := s.storage.Open(ctx, a.StorageKey)
+			if err != nil {
+				return nil, nil, err
+			}
+			return &a, content, nil
+		}
+	}
+	return nil, nil, domain.ErrNotFound
+}
+
+func (s *attachmentService) DeleteAttachment(ctx context.Context, projectID, taskID, attachmentID, requesterID string) error {
+	project, task, err := findProjectTask(ctx, s.projectRepo, s.taskRepo, projectID, taskID, requesterID, domain.PermTaskView)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range task.Attachments {
+		if a.ID.Hex() != attachmentID {
+			continue
+		}
+		// Uploaders can remove their own files; moderators can remove any
+		perm := domain.PermAttachmentWrite
+		if a.UploadedBy.Hex() != requesterID {
+			perm = domain.PermAttachmentDeleteAny
+		}
+		if err := authorize(project, requesterID, perm); err != nil {
+			return err
+		}
+		if err := s.taskRepo.RemoveAttachment(ctx, task.ID, a.ID); err != nil {
+			return err
+		}
+		// The attachment is gone, so a blob that fails to delete is only logged
+		if err := s.storage.Delete(ctx, a.StorageKey); err != nil {
+			s.log.Error("failed to delete attachment", "task_id", taskID, "key", a.StorageKey, "error", err)
+		}
+		changes := []domain.FieldChange{{Field: "attachment", From: a.Name}}
+		s.record(ctx, task, requesterID, domain.ActivityAttachmentRemoved, a.ID, changes, nil)
+		return nil
+	}
+	return domain.ErrNotFound
+}
+
+// --- helpers ---
+
+// record appends to the task's change log and announces the change on the
+// project's event stream. The attachment write has already succeeded by
+// then, so a failed activity write is logged rather than reported to the
+// client, who might otherwise upload the file again.
+func (s *attachmentService) record(ctx context.Context, task *domain.Task, actorID string, action domain.ActivityAction, attachmentID bson.ObjectID, changes []domain.FieldChange, payload any) {
+	if err := recordActivity(ctx, s.activityRepo, task, actorID, action, attachmentID, changes); err != nil {
+		s.log.Error("failed to record task activity", "task_id", task.ID.Hex(), "action", action, "error", err)
+	}
+	// Activity actions on attachments double as event types
+	publishEvent(ctx, s.events, task.ProjectID, actorID, domain.EventType(action), attachmentID, changes, payload)
+}
+
+func sanitizeFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == '"' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "." || name == "/" || name == "" {
+		return "file"
+	}
+	return name
+}
\ No newline at end of file
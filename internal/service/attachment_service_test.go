@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/0DayMonxrch/project-management-system/internal/config"
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/storage"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type attachmentTaskRepo struct {
+	domain.TaskRepository
+	task domain.Task
+}
+
+func (r *attachmentTaskRepo) FindByID(ctx context.Context, id string) (*domain.Task, error) {
+	task := r.task
+	return &task, nil
+}
+
+func (r *attachmentTaskRepo) AddAttachment(ctx context.Context, taskID bson.ObjectID, a domain.Attachment) error {
+	r.task.Attachments = append(r.task.Attachments, a)
+	return nil
+}
+
+func (r *attachmentTaskRepo) RemoveAttachment(ctx context.Context, taskID, attachmentID bson.ObjectID) error {
+	r.task.Attachments = nil
+	return nil
+}
+
+type failingActivityRepo struct{ domain.ActivityRepository }
+
+func (failingActivityRepo) Create(ctx context.Context, activity *domain.TaskActivity) error {
+	return errors.New("activity write failed")
+}
+
+type failingDeleteStorage struct{ domain.BlobStorage }
+
+func (failingDeleteStorage) Delete(ctx context.Context, key string) error {
+	return errors.New("blob delete failed")
+}
+
+type recordingEventBus struct {
+	domain.EventBus
+	events []domain.ProjectEvent
+}
+
+func (b *recordingEventBus) Publish(ctx context.Context, event domain.ProjectEvent) {
+	b.events = append(b.events, event)
+}
+
+func TestAttachmentChangesSucceedWhenFollowUpsFail(t *testing.T) {
+	admin := bson.NewObjectID()
+	project := &domain.Project{ID: bson.NewObjectID(), Members: []domain.ProjectMember{{UserID: admin, Role: domain.RoleAdmin}}}
+	tasks := &attachmentTaskRepo{task: domain.Task{ID: bson.NewObjectID(), ProjectID: project.ID}}
+	blobs, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus := &recordingEventBus{}
+	cfg := config.UploadConfig{MaxSizeMB: 1, AllowedTypes: []string{"text/plain; charset=utf-8"}}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := NewAttachmentService(tasks, newMemProjectRepo(project), failingActivityRepo{}, blobs, cfg, bus, log)
+
+	a, err := svc.UploadAttachment(context.Background(), project.ID.Hex(), tasks.task.ID.Hex(), admin.Hex(), "a.txt", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatalf("upload failed after the attachment was saved: %v", err)
+	}
+
+	svc = NewAttachmentService(tasks, newMemProjectRepo(project), failingActivityRepo{}, failingDeleteStorage{blobs}, cfg, bus, log)
+	if err := svc.DeleteAttachment(context.Background(), project.ID.Hex(), tasks.task.ID.Hex(), a.ID.Hex(), admin.Hex()); err != nil {
+		t.Fatalf("delete failed after the attachment was removed: %v", err)
+	}
+
+	if len(bus.events) != 2 || bus.events[0].Type != domain.EventAttachmentAdded || bus.events[1].Type != domain.EventAttachmentRemoved {
+		t.Fatalf("published %v, want attachment.added then attachment.removed", bus.events)
+	}
+	if bus.events[0].TargetID != a.ID {
+		t.Errorf("event target = %s, want the attachment %s", bus.events[0].TargetID.Hex(), a.ID.Hex())
+	}
+}
\ No newline at end of file
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type auditService struct {
+	auditRepo   domain.AuditRepository
+	projectRepo domain.ProjectRepository
+	userRepo    domain.UserRepository
+}
+
+func NewAuditService(auditRepo domain.AuditRepository, projectRepo domain.ProjectRepository, userRepo domain.UserRepository) domain.AuditService {
+	return &auditService{auditRepo: auditRepo, projectRepo: projectRepo, userRepo: userRepo}
+}
+
+func (s *auditService) ListProjectAudit(ctx context.Context, projectID, requesterID string, filter domain.AuditFilter) (*domain.AuditPage, error) {
+	if _, err := authorizeProject(ctx, s.projectRepo, projectID, requesterID, domain.PermAuditView); err != nil {
+		return nil, err
+	}
+
+	filter.ProjectID = projectID
+	filter.Limit = clampPageSize(filter.Limit, defaultPageSize, maxPageSize)
+	return s.auditRepo.Find(ctx, filter)
+}
+
+func (s *auditService) ListAudit(ctx context.Context, requesterID string, filter domain.AuditFilter) (*domain.AuditPage, error) {
+	user, err := s.userRepo.FindByID(ctx, requesterID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role != domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+
+	filter.Limit = clampPageSize(filter.Limit, defaultPageSize, maxPageSize)
+	return s.auditRepo.Find(ctx, filter)
+}
+
+// --- helpers ---
+
+// recordAudit appends an entry to the audit log. projectID may be zero for
+// account-level events.
+func recordAudit(ctx context.Context, repo domain.AuditRepository, projectID bson.ObjectID, actorID string, action domain.AuditAction, targetType, targetID string, changes []domain.FieldChange) error {
+	actorOID, _ := bson.ObjectIDFromHex(actorID)
+	if changes == nil {
+		changes = []domain.FieldChange{}
+	}
+	return repo.Create(ctx, &domain.AuditEntry{
+		ProjectID:  projectID,
+		ActorID:    actorOID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Changes:    changes,
+	})
+}
\ No newline at end of file
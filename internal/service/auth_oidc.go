@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	oidcAudience   = "oidc"
+	oidcFlowExpiry = 10 * time.Minute
+)
+
+// oidcFlowClaims carry an in-progress sign-in between StartOIDCLogin and
+// CompleteOIDCLogin, so no server-side state is needed. A flow started by
+// StartOIDCLink has the linking user as its subject.
+type oidcFlowClaims struct {
+	jwt.RegisteredClaims
+	Provider string `json:"prv"`
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"cv"`
+}
+
+func (s *authService) StartOIDCLogin(ctx context.Context, provider string) (string, string, error) {
+	return s.startOIDCFlow(ctx, provider, "")
+}
+
+func (s *authService) StartOIDCLink(ctx context.Context, provider, userID string) (string, string, error) {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return "", "", err
+	}
+	return s.startOIDCFlow(ctx, provider, userID)
+}
+
+func (s *authService) startOIDCFlow(ctx context.Context, provider, userID string) (string, string, error) {
+	idp, err := s.identityProvider(provider)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := generateToken()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := generateToken()
+	if err != nil {
+		return "", "", err
+	}
+	// RFC 7636: the verifier is 43-128 characters; 64 hex characters fits
+	verifier, err := generateToken()
+	if err != nil {
+		return "", "", err
+	}
+	challenge := sha256.Sum256([]byte(verifier))
+
+	authURL, err := idp.AuthCodeURL(ctx, state, nonce, base64.RawURLEncoding.EncodeToString(challenge[:]))
+	if err != nil {
+		return "", "", err
+	}
+
+	now := time.Now()
+	claims := oidcFlowClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			Audience:  jwt.ClaimStrings{oidcAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(oidcFlowExpiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Provider: provider,
+		State:    state,
+		Nonce:    nonce,
+		Verifier: verifier,
+	}
+	flowToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.cfg.RefreshSecret))
+	if err != nil {
+		return "", "", err
+	}
+	return authURL, flowToken, nil
+}
+
+func (s *authService) CompleteOIDCLogin(ctx context.Context, provider, code, state, flowToken string, client domain.ClientInfo) (*domain.LoginResult, error) {
+	idp, err := s.identityProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	var flow oidcFlowClaims
+	if err := s.parseJWT(flowToken, s.cfg.RefreshSecret, &flow, jwt.WithAudience(oidcAudience)); err != nil {
+		return nil, domain.ErrTokenInvalid
+	}
+	if flow.Provider != provider || subtle.ConstantTimeCompare([]byte(flow.State), []byte(state)) != 1 {
+		return nil, domain.ErrTokenInvalid
+	}
+
+	identity, err := idp.Exchange(ctx, code, flow.Verifier, flow.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%s sign-in failed: %w: %w", provider, domain.ErrUnauthorized, err)
+	}
+
+	var user *domain.User
+	if flow.Subject != "" {
+		user, err = s.linkIdentity(ctx, flow.Subject, identity)
+	} else {
+		user, err = s.findOrProvisionUser(ctx, identity)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		mfaToken, err := s.newMFAToken(user)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.LoginResult{MFARequired: true, MFAToken: mfaToken}, nil
+	}
+	return s.startSession(ctx, user, client)
+}
+
+func (s *authService) LoginMethods() domain.LoginMethods {
+	methods := domain.LoginMethods{Password: !s.authCfg.DisablePasswordLogin, OIDCProviders: []string{}}
+	for name := range s.providers {
+		methods.OIDCProviders = append(methods.OIDCProviders, name)
+	}
+	slices.Sort(methods.OIDCProviders)
+	return methods
+}
+
+// --- helpers ---
+
+func (s *authService) identityProvider(name string) (domain.IdentityProvider, error) {
+	idp, ok := s.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown identity provider %q: %w", name, domain.ErrNotFound)
+	}
+	return idp, nil
+}
+
+// findOrProvisionUser returns the user linked to identity. Failing that it
+// creates an account, but only if the provider verified the email. An
+// existing account with the same email is linked only when the provider is
+// trusted to do so; otherwise its owner has to link it with StartOIDCLink.
+func (s *authService) findOrProvisionUser(ctx context.Context, identity *domain.ExternalIdentity) (*domain.User, error) {
+	user, err := s.userRepo.FindByIdentity(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, fmt.Errorf("%s did not provide a verified email: %w", identity.Provider, domain.ErrForbidden)
+	}
+	link := domain.IdentityLink{Provider: identity.Provider, Subject: identity.Subject, LinkedAt: time.Now()}
+
+	user, err = s.userRepo.FindByEmail(ctx, identity.Email)
+	switch {
+	case err == nil:
+		if !s.linksByEmail(identity.Provider) {
+			return nil, fmt.Errorf("an account with this email already exists; sign in to it and link %s from there: %w", identity.Provider, domain.ErrConflict)
+		}
+		user.Identities = append(user.Identities, link)
+		// The provider has just vouched for the address
+		user.IsEmailVerified = true
+		user.VerificationToken = ""
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, err
+		}
+	case errors.Is(err, domain.ErrNotFound):
+		name := identity.Name
+		if name == "" {
+			name = identity.Email
+		}
+		user = &domain.User{
+			Name:            name,
+			Email:           identity.Email,
+			Role:            domain.RoleMember,
+			IsEmailVerified: true,
+			Identities:      []domain.IdentityLink{link},
+		}
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if err := s.recordIdentityLinked(ctx, user, identity); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// linkIdentity attaches identity to userID, who started the flow from a
+// signed-in session. The email the provider reports doesn't matter here.
+func (s *authService) linkIdentity(ctx context.Context, userID string, identity *domain.ExternalIdentity) (*domain.User, error) {
+	linked, err := s.userRepo.FindByIdentity(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		if linked.ID.Hex() != userID {
+			return nil, fmt.Errorf("this %s account is linked to another user: %w", identity.Provider, domain.ErrConflict)
+		}
+		return linked, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Identities = append(user.Identities, domain.IdentityLink{Provider: identity.Provider, Subject: identity.Subject, LinkedAt: time.Now()})
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordIdentityLinked(ctx, user, identity); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *authService) recordIdentityLinked(ctx context.Context, user *domain.User, identity *domain.ExternalIdentity) error {
+	changes := []domain.FieldChange{{Field: "provider", To: identity.Provider}}
+	return recordAudit(ctx, s.auditRepo, bson.ObjectID{}, user.ID.Hex(), domain.AuditIdentityLinked, "user", user.ID.Hex(), changes)
+}
+
+// linksByEmail reports whether provider may sign users into existing
+// accounts with the same verified email.
+func (s *authService) linksByEmail(provider string) bool {
+	for _, p := range s.authCfg.OIDCProviders {
+		if p.Name == provider {
+			return p.LinkByEmail
+		}
+	}
+	return false
+}
\ No newline at end of file
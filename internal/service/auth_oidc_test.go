@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"log/slog"
+	"net/url"
+	"testing"
+
+	"github.com/0DayMonxrch/project-management-system/internal/config"
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/jwtkeys"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// stubIdP stands in for a provider's round trip: it hands back identity for
+// the code it was given, provided the verifier matches the challenge and
+// the nonce is the one it was sent.
+type stubIdP struct {
+	identity  domain.ExternalIdentity
+	challenge string
+	nonce     string
+}
+
+func (p *stubIdP) Name() string { return p.identity.Provider }
+
+func (p *stubIdP) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	p.challenge, p.nonce = codeChallenge, nonce
+	return "https://idp.test/authorize?" + url.Values{"state": {state}}.Encode(), nil
+}
+
+func (p *stubIdP) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*domain.ExternalIdentity, error) {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	if code != "code" || base64.RawURLEncoding.EncodeToString(sum[:]) != p.challenge || nonce != p.nonce {
+		return nil, errors.New("invalid_grant")
+	}
+	identity := p.identity
+	return &identity, nil
+}
+
+type memUserRepo struct {
+	domain.UserRepository
+	users map[string]*domain.User
+}
+
+func (r *memUserRepo) Create(ctx context.Context, user *domain.User) error {
+	user.ID = bson.NewObjectID()
+	r.users[user.ID.Hex()] = user
+	return nil
+}
+
+func (r *memUserRepo) Update(ctx context.Context, user *domain.User) error {
+	r.users[user.ID.Hex()] = user
+	return nil
+}
+
+func (r *memUserRepo) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	if u, ok := r.users[id]; ok {
+		copied := *u
+		return &copied, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *memUserRepo) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			copied := *u
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *memUserRepo) FindByUnlockToken(ctx context.Context, token string) (*domain.User, error) {
+	for _, u := range r.users {
+		if u.UnlockToken == token {
+			copied := *u
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *memUserRepo) FindByVerificationToken(ctx context.Context, token string) (*domain.User, error) {
+	for _, u := range r.users {
+		if u.VerificationToken == token {
+			copied := *u
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *memUserRepo) FindByIdentity(ctx context.Context, provider, subject string) (*domain.User, error) {
+	for _, u := range r.users {
+		if hasLink(u.Identities, domain.IdentityLink{Provider: provider, Subject: subject}) {
+			copied := *u
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func hasLink(links []domain.IdentityLink, want domain.IdentityLink) bool {
+	for _, l := range links {
+		if l.Provider == want.Provider && l.Subject == want.Subject {
+			return true
+		}
+	}
+	return false
+}
+
+type memSessionRepo struct {
+	domain.SessionRepository
+}
+
+func (memSessionRepo) Create(ctx context.Context, session *domain.Session) error {
+	session.ID = bson.NewObjectID()
+	return nil
+}
+
+type nopAuditRepo struct {
+	domain.AuditRepository
+}
+
+func (nopAuditRepo) Create(ctx context.Context, entry *domain.AuditEntry) error { return nil }
+
+type nopInvitations struct {
+	domain.InvitationService
+}
+
+func (nopInvitations) AcceptPending(ctx context.Context, user *domain.User) error { return nil }
+
+type failingInvitations struct {
+	domain.InvitationService
+}
+
+func (failingInvitations) AcceptPending(ctx context.Context, user *domain.User) error {
+	return errors.New("database unavailable")
+}
+
+const existingEmail = "ada@example.com"
+
+// newOIDCTestService signs in through a provider called "corp" that vouches
+// for a verified existingEmail, with one password account already using it.
+func newOIDCTestService(t *testing.T, linkByEmail bool) (*authService, *stubIdP, *memUserRepo, *domain.User) {
+	t.Helper()
+	existing := &domain.User{ID: bson.NewObjectID(), Email: existingEmail, Password: "hash", IsEmailVerified: true}
+	users := &memUserRepo{users: map[string]*domain.User{existing.ID.Hex(): existing}}
+	idp := &stubIdP{identity: domain.ExternalIdentity{Provider: "corp", Subject: "sub-1", Email: existingEmail, EmailVerified: true}}
+
+	jwtCfg := config.JWTConfig{AccessSecret: "access", RefreshSecret: "refresh", AccessExpiryMinutes: 15, RefreshExpiryDays: 7}
+	keys, err := jwtkeys.Load(jwtCfg)
+	if err != nil {
+		t.Fatalf("load keys: %v", err)
+	}
+	authCfg := config.AuthConfig{OIDCProviders: []config.OIDCProviderConfig{{Name: "corp", LinkByEmail: linkByEmail}}}
+	svc := NewAuthService(users, memSessionRepo{}, nopAuditRepo{}, nil, nil, NewSessionCache(nil, 0), nil, nopInvitations{}, []domain.IdentityProvider{idp}, keys, jwtCfg, authCfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return svc.(*authService), idp, users, existing
+}
+
+// signIn runs a flow from its start to the callback.
+func signIn(t *testing.T, svc *authService, start func() (string, string, error)) (*domain.LoginResult, error) {
+	t.Helper()
+	authURL, flowToken, err := start()
+	if err != nil {
+		t.Fatalf("start flow: %v", err)
+	}
+	u, _ := url.Parse(authURL)
+	return svc.CompleteOIDCLogin(context.Background(), "corp", "code", u.Query().Get("state"), flowToken, domain.ClientInfo{})
+}
+
+func TestCompleteOIDCLoginRejectsBadFlow(t *testing.T) {
+	svc, _, _, _ := newOIDCTestService(t, false)
+	ctx := context.Background()
+
+	authURL, flowToken, err := svc.StartOIDCLogin(ctx, "corp")
+	if err != nil {
+		t.Fatalf("StartOIDCLogin: %v", err)
+	}
+	u, _ := url.Parse(authURL)
+	state := u.Query().Get("state")
+
+	_, otherFlow, err := svc.StartOIDCLogin(ctx, "corp")
+	if err != nil {
+		t.Fatalf("StartOIDCLogin: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		provider  string
+		state     string
+		flowToken string
+		want      error
+	}{
+		{name: "state mismatch", provider: "corp", state: "forged", flowToken: flowToken, want: domain.ErrTokenInvalid},
+		{name: "empty state", provider: "corp", state: "", flowToken: flowToken, want: domain.ErrTokenInvalid},
+		{name: "another flow's cookie", provider: "corp", state: state, flowToken: otherFlow, want: domain.ErrTokenInvalid},
+		{name: "tampered flow", provider: "corp", state: state, flowToken: flowToken + "x", want: domain.ErrTokenInvalid},
+		{name: "no flow", provider: "corp", state: state, flowToken: "", want: domain.ErrTokenInvalid},
+		{name: "unknown provider", provider: "other", state: state, flowToken: flowToken, want: domain.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CompleteOIDCLogin(ctx, tt.provider, "code", tt.state, tt.flowToken, domain.ClientInfo{})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("CompleteOIDCLogin error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompleteOIDCLoginDoesNotLinkByEmail(t *testing.T) {
+	svc, _, users, existing := newOIDCTestService(t, false)
+
+	_, err := signIn(t, svc, func() (string, string, error) { return svc.StartOIDCLogin(context.Background(), "corp") })
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("CompleteOIDCLogin error = %v, want ErrConflict", err)
+	}
+	if len(users.users[existing.ID.Hex()].Identities) != 0 {
+		t.Fatal("provider account was linked to the existing account")
+	}
+}
+
+func TestCompleteOIDCLoginLinksByEmailWhenTrusted(t *testing.T) {
+	svc, _, users, existing := newOIDCTestService(t, true)
+
+	result, err := signIn(t, svc, func() (string, string, error) { return svc.StartOIDCLogin(context.Background(), "corp") })
+	if err != nil {
+		t.Fatalf("CompleteOIDCLogin: %v", err)
+	}
+	if result.AccessToken == "" {
+		t.Fatal("no session was started")
+	}
+	if got := users.users[existing.ID.Hex()].Identities; len(got) != 1 || got[0].Subject != "sub-1" {
+		t.Fatalf("identities = %+v, want the provider account", got)
+	}
+}
+
+func TestCompleteOIDCLoginRequiresVerifiedEmail(t *testing.T) {
+	svc, idp, users, _ := newOIDCTestService(t, true)
+	idp.identity.Email = "new@example.com"
+	idp.identity.EmailVerified = false
+
+	_, err := signIn(t, svc, func() (string, string, error) { return svc.StartOIDCLogin(context.Background(), "corp") })
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("CompleteOIDCLogin error = %v, want ErrForbidden", err)
+	}
+	if len(users.users) != 1 {
+		t.Fatal("an account was created for an unverified email")
+	}
+}
+
+func TestCompleteOIDCLoginProvisionsNewUser(t *testing.T) {
+	svc, idp, users, _ := newOIDCTestService(t, false)
+	idp.identity.Email = "new@example.com"
+
+	if _, err := signIn(t, svc, func() (string, string, error) { return svc.StartOIDCLogin(context.Background(), "corp") }); err != nil {
+		t.Fatalf("CompleteOIDCLogin: %v", err)
+	}
+	created, err := users.FindByIdentity(context.Background(), "corp", "sub-1")
+	if err != nil {
+		t.Fatalf("no account was created: %v", err)
+	}
+	if created.Email != "new@example.com" || !created.IsEmailVerified {
+		t.Fatalf("created %+v", created)
+	}
+}
+
+func TestOIDCLinkFlow(t *testing.T) {
+	svc, idp, users, existing := newOIDCTestService(t, false)
+	// Linking doesn't depend on the provider knowing the same address
+	idp.identity.Email = "ada@corp.example"
+	link := func() (string, string, error) {
+		return svc.StartOIDCLink(context.Background(), "corp", existing.ID.Hex())
+	}
+
+	if _, err := signIn(t, svc, link); err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	if got := users.users[existing.ID.Hex()].Identities; len(got) != 1 || got[0].Subject != "sub-1" {
+		t.Fatalf("identities = %+v, want the provider account", got)
+	}
+
+	// Signing in with the provider now reaches the linked account
+	if _, err := signIn(t, svc, func() (string, string, error) { return svc.StartOIDCLogin(context.Background(), "corp") }); err != nil {
+		t.Fatalf("sign in after linking: %v", err)
+	}
+
+	// Linking again is a no-op
+	if _, err := signIn(t, svc, link); err != nil {
+		t.Fatalf("relink: %v", err)
+	}
+	if got := users.users[existing.ID.Hex()].Identities; len(got) != 1 {
+		t.Fatalf("identities = %+v after relinking", got)
+	}
+}
+
+func TestOIDCLinkFlowRefusesIdentityOfAnotherUser(t *testing.T) {
+	svc, _, users, existing := newOIDCTestService(t, false)
+	other := &domain.User{Email: "other@example.com", Identities: []domain.IdentityLink{{Provider: "corp", Subject: "sub-1"}}}
+	users.Create(context.Background(), other)
+
+	_, err := signIn(t, svc, func() (string, string, error) {
+		return svc.StartOIDCLink(context.Background(), "corp", existing.ID.Hex())
+	})
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("link error = %v, want ErrConflict", err)
+	}
+	if len(users.users[existing.ID.Hex()].Identities) != 0 {
+		t.Fatal("another user's provider account was linked")
+	}
+}
+func TestPendingInvitationFailureDoesNotBlockSignIn(t *testing.T) {
+	svc, idp, users, existing := newOIDCTestService(t, false)
+	svc.invitations = failingInvitations{}
+	idp.identity.Email = "new@example.com"
+
+	result, err := signIn(t, svc, func() (string, string, error) { return svc.StartOIDCLogin(context.Background(), "corp") })
+	if err != nil {
+		t.Fatalf("CompleteOIDCLogin: %v", err)
+	}
+	if result.AccessToken == "" {
+		t.Fatal("no session was started")
+	}
+
+	existing.IsEmailVerified = false
+	existing.VerificationToken = "verify"
+	if err := svc.VerifyEmail(context.Background(), "verify"); err != nil {
+		t.Fatalf("VerifyEmail: %v", err)
+	}
+	if !users.users[existing.ID.Hex()].IsEmailVerified {
+		t.Fatal("email was not verified")
+	}
+}
\ No newline at end of file
@@ -3,27 +3,89 @@ package service
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/0DayMonxrch/project-management-system/internal/config"
 	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/jwtkeys"
+	"github.com/0DayMonxrch/project-management-system/pkg/totp"
 	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	totpIssuer = "Project Camp"
+	// totpSkew accepts codes one step either side of now, for clock drift
+	totpSkew          = 1
+	recoveryCodeCount = 10
+
+	mfaAudience    = "mfa"
+	mfaTokenExpiry = 5 * time.Minute
+
+	emailChangeExpiry = 24 * time.Hour
+)
+
+var errPasswordLoginDisabled = fmt.Errorf("password login is disabled, sign in with SSO: %w", domain.ErrForbidden)
+
 type authService struct {
-	userRepo domain.UserRepository
-	email    domain.EmailService
-	cfg      config.JWTConfig
+	userRepo    domain.UserRepository
+	sessionRepo domain.SessionRepository
+	auditRepo   domain.AuditRepository
+	email       domain.EmailService
+	versions    domain.TokenVersionCache
+	sessions    domain.SessionCache
+	throttle    *loginThrottle
+	invitations domain.InvitationService
+	providers   map[string]domain.IdentityProvider
+	keys        *jwtkeys.KeySet
+	cfg         config.JWTConfig
+	authCfg     config.AuthConfig
+	log         *slog.Logger
 }
 
-func NewAuthService(userRepo domain.UserRepository, email domain.EmailService, cfg config.JWTConfig) domain.AuthService {
-	return &authService{userRepo: userRepo, email: email, cfg: cfg}
+// accessClaims are the claims of an access token. SessionID ties it to the
+// session whose refresh token issued it; Version must match the user's
+// TokenVersion for the token to be accepted.
+type accessClaims struct {
+	jwt.RegisteredClaims
+	SessionID string `json:"sid,omitempty"`
+	Version   int    `json:"ver"`
+}
+
+func NewAuthService(userRepo domain.UserRepository, sessionRepo domain.SessionRepository, auditRepo domain.AuditRepository, email domain.EmailService, versions domain.TokenVersionCache, sessions domain.SessionCache, attempts domain.AttemptStore, invitations domain.InvitationService, providers []domain.IdentityProvider, keys *jwtkeys.KeySet, cfg config.JWTConfig, authCfg config.AuthConfig, log *slog.Logger) domain.AuthService {
+	byName := make(map[string]domain.IdentityProvider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+	return &authService{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		auditRepo:   auditRepo,
+		email:       email,
+		versions:    versions,
+		sessions:    sessions,
+		throttle:    &loginThrottle{store: attempts, cfg: authCfg.Throttle},
+		invitations: invitations,
+		providers:   byName,
+		keys:        keys,
+		cfg:         cfg,
+		authCfg:     authCfg,
+		log:         log,
+	}
 }
 
 func (s *authService) Register(ctx context.Context, name, email, password string) error {
+	if s.authCfg.DisablePasswordLogin {
+		return errPasswordLoginDisabled
+	}
 	_, err := s.userRepo.FindByEmail(ctx, email)
 	if err == nil {
 		return domain.ErrConflict
@@ -54,45 +116,93 @@ func (s *authService) Register(ctx context.Context, name, email, password string
 	return s.email.SendVerificationEmail(email, token)
 }
 
-func (s *authService) Login(ctx context.Context, email, password string) (string, string, error) {
+func (s *authService) Login(ctx context.Context, email, password string, client domain.ClientInfo) (*domain.LoginResult, error) {
+	if s.authCfg.DisablePasswordLogin {
+		return nil, errPasswordLoginDisabled
+	}
+	attempt, err := s.throttle.reserve(ctx, client.IP, email)
+	if err != nil {
+		return nil, err
+	}
 	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
-		return "", "", domain.ErrUnauthorized
+		return nil, s.loginFailed(ctx, attempt, nil, domain.ErrUnauthorized)
 	}
 
 	if !user.IsEmailVerified {
-		return "", "", domain.ErrEmailNotVerified
+		if err := s.throttle.refund(ctx, attempt); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrEmailNotVerified
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return "", "", domain.ErrUnauthorized
+		if err := s.audit(ctx, user.ID.Hex(), domain.AuditLoginFailed); err != nil {
+			return nil, err
+		}
+		return nil, s.loginFailed(ctx, attempt, user, domain.ErrUnauthorized)
 	}
 
-	accessToken, err := s.generateJWT(user.ID.Hex(), s.cfg.AccessSecret, time.Duration(s.cfg.AccessExpiryMinutes)*time.Minute)
-	if err != nil {
-		return "", "", err
+	if err := s.throttle.refund(ctx, attempt); err != nil {
+		return nil, err
 	}
+	// With 2FA the failure count stands until the second step succeeds, so
+	// codes can't be guessed by interleaving correct passwords
+	if user.TOTPEnabled {
+		mfaToken, err := s.newMFAToken(user)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.LoginResult{MFARequired: true, MFAToken: mfaToken}, nil
+	}
+	if err := s.throttle.unlock(ctx, email); err != nil {
+		return nil, err
+	}
+	return s.startSession(ctx, user, client)
+}
 
-	refreshToken, err := s.generateJWT(user.ID.Hex(), s.cfg.RefreshSecret, time.Duration(s.cfg.RefreshExpiryDays)*24*time.Hour)
-	if err != nil {
-		return "", "", err
+func (s *authService) VerifyMFA(ctx context.Context, mfaToken, code string, client domain.ClientInfo) (*domain.LoginResult, error) {
+	var claims accessClaims
+	if err := s.parseJWT(mfaToken, s.cfg.RefreshSecret, &claims, jwt.WithAudience(mfaAudience)); err != nil {
+		return nil, domain.ErrTokenInvalid
 	}
 
-	user.RefreshToken = refreshToken
-	if err := s.userRepo.Update(ctx, user); err != nil {
-		return "", "", err
+	user, err := s.userRepo.FindByID(ctx, claims.Subject)
+	if err != nil {
+		return nil, domain.ErrTokenInvalid
+	}
+	// A password change or reset since the first step voids the challenge
+	if !user.TOTPEnabled || claims.Version != user.TokenVersion {
+		return nil, domain.ErrTokenInvalid
+	}
+	attempt, err := s.throttle.reserve(ctx, client.IP, user.Email)
+	if err != nil {
+		return nil, err
 	}
 
-	return accessToken, refreshToken, nil
+	if err := checkSecondFactor(ctx, s.userRepo, s.auditRepo, user, code); err != nil {
+		if errors.Is(err, domain.ErrUnauthorized) {
+			return nil, s.loginFailed(ctx, attempt, user, err)
+		}
+		return nil, errors.Join(err, s.throttle.refund(ctx, attempt))
+	}
+	if err := s.throttle.refund(ctx, attempt); err != nil {
+		return nil, err
+	}
+	if err := s.throttle.unlock(ctx, user.Email); err != nil {
+		return nil, err
+	}
+	return s.startSession(ctx, user, client)
 }
 
-func (s *authService) Logout(ctx context.Context, userID string) error {
-	user, err := s.userRepo.FindByID(ctx, userID)
-	if err != nil {
+// Logout ends only this device's session. Its access token stops working
+// at once; the user's other devices stay signed in.
+func (s *authService) Logout(ctx context.Context, userID, sessionID string) error {
+	if err := s.sessionRepo.Delete(ctx, userID, sessionID); err != nil && !errors.Is(err, domain.ErrNotFound) {
 		return err
 	}
-	user.RefreshToken = ""
-	return s.userRepo.Update(ctx, user)
+	s.sessions.Invalidate(sessionID)
+	return s.audit(ctx, userID, domain.AuditLogout)
 }
 
 func (s *authService) VerifyEmail(ctx context.Context, token string) error {
@@ -102,33 +212,62 @@ func (s *authService) VerifyEmail(ctx context.Context, token string) error {
 	}
 	user.IsEmailVerified = true
 	user.VerificationToken = ""
-	return s.userRepo.Update(ctx, user)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	// Finishes a sign-up prompted by an invitation
+	s.acceptPendingInvitations(ctx, user)
+	return nil
 }
 
-func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (string, error) {
-	claims, err := s.parseJWT(refreshToken, s.cfg.RefreshSecret)
-	if err != nil {
-		return "", domain.ErrTokenInvalid
+func (s *authService) RefreshToken(ctx context.Context, refreshToken string, client domain.ClientInfo) (string, string, error) {
+	var claims jwt.RegisteredClaims
+	if err := s.parseJWT(refreshToken, s.cfg.RefreshSecret, &claims); err != nil {
+		return "", "", domain.ErrTokenInvalid
 	}
+	userID := claims.Subject
 
-	userID, err := claims.GetSubject()
+	hash := hashToken(refreshToken)
+	session, err := s.sessionRepo.FindByTokenHash(ctx, hash)
+	if errors.Is(err, domain.ErrNotFound) {
+		return "", "", domain.ErrTokenInvalid
+	}
 	if err != nil {
-		return "", domain.ErrTokenInvalid
+		return "", "", err
+	}
+	if session.UserID.Hex() != userID {
+		return "", "", domain.ErrTokenInvalid
+	}
+	if session.TokenHash != hash {
+		return "", "", s.revokeReusedSession(ctx, session)
 	}
 
 	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
-		return "", domain.ErrUnauthorized
+		return "", "", domain.ErrUnauthorized
 	}
 
-	if user.RefreshToken != refreshToken {
-		return "", domain.ErrTokenInvalid
+	newRefreshToken, expiresAt, err := s.newRefreshToken(userID)
+	if err != nil {
+		return "", "", err
+	}
+	err = s.sessionRepo.Rotate(ctx, session.ID, hash, hashToken(newRefreshToken), client, expiresAt)
+	if errors.Is(err, domain.ErrNotFound) {
+		// Another request rotated this token first
+		return "", "", s.revokeReusedSession(ctx, session)
+	}
+	if err != nil {
+		return "", "", err
 	}
 
-	return s.generateJWT(userID, s.cfg.AccessSecret, time.Duration(s.cfg.AccessExpiryMinutes)*time.Minute)
+	accessToken, err := s.generateAccessToken(user, session.ID.Hex())
+	if err != nil {
+		return "", "", err
+	}
+	return accessToken, newRefreshToken, nil
 }
 
-func (s *authService) ChangePassword(ctx context.Context, userID, oldPassword, newPassword string) error {
+func (s *authService) ChangePassword(ctx context.Context, userID, sessionID, oldPassword, newPassword string) error {
 	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return err
@@ -144,10 +283,32 @@ func (s *authService) ChangePassword(ctx context.Context, userID, oldPassword, n
 	}
 
 	user.Password = string(hash)
-	return s.userRepo.Update(ctx, user)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	// Outstanding access tokens and MFA challenges stop working; this device
+	// picks up the new version on its next refresh
+	if err := s.userRepo.IncrementTokenVersion(ctx, userID); err != nil {
+		return err
+	}
+	s.versions.Invalidate(userID)
+	// Whoever knew the old password may be signed in elsewhere; this device
+	// stays signed in
+	if err := s.sessionRepo.DeleteOthers(ctx, userID, sessionID); err != nil {
+		return err
+	}
+	s.sessions.InvalidateUser(userID)
+	return s.audit(ctx, userID, domain.AuditPasswordChanged)
 }
 
-func (s *authService) ForgotPassword(ctx context.Context, email string) error {
+func (s *authService) ForgotPassword(ctx context.Context, email string, client domain.ClientInfo) error {
+	if s.authCfg.DisablePasswordLogin {
+		return errPasswordLoginDisabled
+	}
+	// Counted before the lookup so the limit doesn't reveal whether email exists
+	if err := s.throttle.limitEmails(ctx, "forgot-password", client.IP, email); err != nil {
+		return err
+	}
 	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
 		// Don't leak whether email exists
@@ -164,11 +325,17 @@ func (s *authService) ForgotPassword(ctx context.Context, email string) error {
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		return err
 	}
+	if err := s.audit(ctx, user.ID.Hex(), domain.AuditPasswordResetRequested); err != nil {
+		return err
+	}
 
 	return s.email.SendPasswordResetEmail(email, token)
 }
 
 func (s *authService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if s.authCfg.DisablePasswordLogin {
+		return errPasswordLoginDisabled
+	}
 	user, err := s.userRepo.FindByResetToken(ctx, token)
 	if err != nil {
 		return domain.ErrTokenInvalid
@@ -186,10 +353,98 @@ func (s *authService) ResetPassword(ctx context.Context, token, newPassword stri
 	user.Password = string(hash)
 	user.ResetToken = ""
 	user.ResetTokenExpiry = time.Time{}
-	return s.userRepo.Update(ctx, user)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	if err := s.userRepo.IncrementTokenVersion(ctx, user.ID.Hex()); err != nil {
+		return err
+	}
+	s.versions.Invalidate(user.ID.Hex())
+	// Whoever knew the old password may still be signed in
+	if err := s.sessionRepo.DeleteByUserID(ctx, user.ID.Hex()); err != nil {
+		return err
+	}
+	s.sessions.InvalidateUser(user.ID.Hex())
+	// Proving control of the email is enough to lift a lockout
+	if err := s.throttle.unlock(ctx, user.Email); err != nil {
+		return err
+	}
+	return s.audit(ctx, user.ID.Hex(), domain.AuditPasswordReset)
+}
+
+func (s *authService) RequestEmailChange(ctx context.Context, userID, password, newEmail string, client domain.ClientInfo) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return domain.ErrUnauthorized
+	}
+	if strings.EqualFold(newEmail, user.Email) {
+		return fmt.Errorf("new email is the current one: %w", domain.ErrInvalidInput)
+	}
+	if _, err := s.userRepo.FindByEmail(ctx, newEmail); err == nil {
+		return domain.ErrConflict
+	}
+	if err := s.throttle.limitEmails(ctx, "change-email", client.IP, user.Email); err != nil {
+		return err
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return err
+	}
+	user.PendingEmail = newEmail
+	user.EmailChangeToken = token
+	user.EmailChangeExpiry = time.Now().Add(emailChangeExpiry)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	changes := []domain.FieldChange{{Field: "email", From: user.Email, To: newEmail}}
+	if err := recordAudit(ctx, s.auditRepo, bson.ObjectID{}, userID, domain.AuditEmailChangeRequested, "user", userID, changes); err != nil {
+		return err
+	}
+	if err := s.email.SendEmailChangeEmail(newEmail, token); err != nil {
+		return err
+	}
+	return s.email.SendEmailChangeNotice(user.Email, newEmail)
+}
+
+func (s *authService) ConfirmEmailChange(ctx context.Context, token string) error {
+	user, err := s.userRepo.FindByEmailChangeToken(ctx, token)
+	if err != nil {
+		return domain.ErrTokenInvalid
+	}
+	if time.Now().After(user.EmailChangeExpiry) {
+		return domain.ErrTokenExpired
+	}
+
+	oldEmail := user.Email
+	user.Email = user.PendingEmail
+	// Following the link proves the new address is the user's
+	user.IsEmailVerified = true
+	user.VerificationToken = ""
+	user.PendingEmail = ""
+	user.EmailChangeToken = ""
+	user.EmailChangeExpiry = time.Time{}
+	// A reset link sent to the old address must not outlive the change
+	user.ResetToken = ""
+	user.ResetTokenExpiry = time.Time{}
+	// The unique index catches an account registered with the address since
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	changes := []domain.FieldChange{{Field: "email", From: oldEmail, To: user.Email}}
+	if err := recordAudit(ctx, s.auditRepo, bson.ObjectID{}, user.ID.Hex(), domain.AuditEmailChanged, "user", user.ID.Hex(), changes); err != nil {
+		return err
+	}
+	s.acceptPendingInvitations(ctx, user)
+	return nil
 }
 
-func (s *authService) ResendVerificationEmail(ctx context.Context, userID string) error {
+func (s *authService) ResendVerificationEmail(ctx context.Context, userID string, client domain.ClientInfo) error {
 	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return err
@@ -198,6 +453,9 @@ func (s *authService) ResendVerificationEmail(ctx context.Context, userID string
 	if user.IsEmailVerified {
 		return domain.ErrConflict
 	}
+	if err := s.throttle.limitEmails(ctx, "verify-email", client.IP, user.Email); err != nil {
+		return err
+	}
 
 	token, err := generateToken()
 	if err != nil {
@@ -212,33 +470,385 @@ func (s *authService) ResendVerificationEmail(ctx context.Context, userID string
 	return s.email.SendVerificationEmail(user.Email, token)
 }
 
+func (s *authService) UnlockAccount(ctx context.Context, token string) error {
+	user, err := s.userRepo.FindByUnlockToken(ctx, token)
+	if err != nil {
+		return domain.ErrTokenInvalid
+	}
+	if time.Now().After(user.UnlockTokenExpiry) {
+		return domain.ErrTokenInvalid
+	}
+	user.UnlockToken = ""
+	user.UnlockTokenExpiry = time.Time{}
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	if err := s.throttle.unlock(ctx, user.Email); err != nil {
+		return err
+	}
+	return s.audit(ctx, user.ID.Hex(), domain.AuditAccountUnlocked)
+}
+
+func (s *authService) AdminUnlockAccount(ctx context.Context, requesterID, userID string) error {
+	requester, err := s.userRepo.FindByID(ctx, requesterID)
+	if err != nil {
+		return err
+	}
+	if requester.Role != domain.RoleAdmin {
+		return domain.ErrForbidden
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	user.UnlockToken = ""
+	user.UnlockTokenExpiry = time.Time{}
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	if err := s.throttle.unlock(ctx, user.Email); err != nil {
+		return err
+	}
+	return recordAudit(ctx, s.auditRepo, bson.ObjectID{}, requesterID, domain.AuditAccountUnlocked, "user", userID, nil)
+}
+
 func (s *authService) GetCurrentUser(ctx context.Context, userID string) (*domain.User, error) {
 	return s.userRepo.FindByID(ctx, userID)
 }
 
+func (s *authService) EnrollTOTP(ctx context.Context, userID string) (*domain.TOTPEnrollment, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, fmt.Errorf("two-factor authentication is already enabled: %w", domain.ErrConflict)
+	}
+
+	// Enrolling again replaces a secret that was never confirmed
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	user.TOTPSecret = secret
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return &domain.TOTPEnrollment{Secret: secret, URI: totp.URI(totpIssuer, user.Email, secret)}, nil
+}
+
+func (s *authService) ConfirmTOTP(ctx context.Context, userID, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, fmt.Errorf("two-factor authentication is already enabled: %w", domain.ErrConflict)
+	}
+	if user.TOTPSecret == "" {
+		return nil, fmt.Errorf("enroll before confirming: %w", domain.ErrInvalidInput)
+	}
+
+	step, ok := totp.Validate(user.TOTPSecret, code, time.Now(), totpSkew)
+	if !ok {
+		return nil, fmt.Errorf("code is incorrect: %w", domain.ErrInvalidInput)
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	// The confirming code can't be replayed at sign-in
+	if err := s.userRepo.ClaimTOTPStep(ctx, userID, step); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("code was already used: %w", domain.ErrInvalidInput)
+		}
+		return nil, err
+	}
+	// Codes are in place before two-factor is switched on
+	if err := s.userRepo.SetRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	user.TOTPEnabled = true
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := s.audit(ctx, userID, domain.AuditTOTPEnabled); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *authService) DisableTOTP(ctx context.Context, userID, password, code string) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return fmt.Errorf("two-factor authentication is not enabled: %w", domain.ErrInvalidInput)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return domain.ErrUnauthorized
+	}
+	if err := checkSecondFactor(ctx, s.userRepo, s.auditRepo, user, code); err != nil {
+		return err
+	}
+
+	// The last TOTP step stays; steps only grow, so it never blocks a new secret
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	if err := s.userRepo.SetRecoveryCodes(ctx, userID, nil); err != nil {
+		return err
+	}
+	return s.audit(ctx, userID, domain.AuditTOTPDisabled)
+}
+
+func (s *authService) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TOTPEnabled {
+		return nil, fmt.Errorf("two-factor authentication is not enabled: %w", domain.ErrInvalidInput)
+	}
+	if err := checkSecondFactor(ctx, s.userRepo, s.auditRepo, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	if err := s.audit(ctx, userID, domain.AuditRecoveryCodesReset); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *authService) ListSessions(ctx context.Context, userID, currentSessionID string) ([]domain.Session, error) {
+	sessions, err := s.sessionRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID.Hex() == currentSessionID
+	}
+	return sessions, nil
+}
+
+func (s *authService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	if err := s.sessionRepo.Delete(ctx, userID, sessionID); err != nil {
+		return err
+	}
+	// Its access tokens stop working now, not when they expire
+	s.sessions.Invalidate(sessionID)
+	return recordAudit(ctx, s.auditRepo, bson.ObjectID{}, userID, domain.AuditSessionRevoked, "session", sessionID, nil)
+}
+
 // --- helpers ---
 
-func (s *authService) generateJWT(userID, secret string, expiry time.Duration) (string, error) {
+// audit records an account-level event where the user is both actor and target.
+func (s *authService) audit(ctx context.Context, userID string, action domain.AuditAction) error {
+	return recordAudit(ctx, s.auditRepo, bson.ObjectID{}, userID, action, "user", userID, nil)
+}
+
+// startSession signs the user in on a new device session.
+func (s *authService) startSession(ctx context.Context, user *domain.User, client domain.ClientInfo) (*domain.LoginResult, error) {
+	refreshToken, expiresAt, err := s.newRefreshToken(user.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	session := &domain.Session{
+		UserID:    user.ID,
+		TokenHash: hashToken(refreshToken),
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.generateAccessToken(user, session.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit(ctx, user.ID.Hex(), domain.AuditLogin); err != nil {
+		return nil, err
+	}
+	// Every way of signing in has verified the email by now
+	s.acceptPendingInvitations(ctx, user)
+	return &domain.LoginResult{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// acceptPendingInvitations joins the user to the projects their address was
+// invited to. It only logs a failure: the sign-in or verification it rides
+// on has already succeeded, and the invitations are tried again next time.
+func (s *authService) acceptPendingInvitations(ctx context.Context, user *domain.User) {
+	if err := s.invitations.AcceptPending(ctx, user); err != nil {
+		s.log.Error("failed to accept pending invitations", "user_id", user.ID.Hex(), "error", err)
+	}
+}
+
+// loginFailed settles a failed sign-in and returns cause, or
+// ErrAccountLocked if this failure locked the account. user is nil when no
+// account has the email; the lockout still applies so it doesn't reveal that.
+func (s *authService) loginFailed(ctx context.Context, attempt *loginAttempt, user *domain.User, cause error) error {
+	locked, err := s.throttle.fail(ctx, attempt)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return cause
+	}
+
+	if user != nil {
+		token, err := generateToken()
+		if err != nil {
+			return err
+		}
+		user.UnlockToken = token
+		// The link is no use once the lockout is over
+		user.UnlockTokenExpiry = time.Now().Add(s.throttle.lockout())
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		if err := s.audit(ctx, user.ID.Hex(), domain.AuditAccountLocked); err != nil {
+			return err
+		}
+		// The account is locked either way; the lockout runs out by itself
+		if err := s.email.SendAccountLockedEmail(user.Email, token); err != nil {
+			s.log.Error("failed to send account locked email", "user_id", user.ID.Hex(), "error", err)
+		}
+	}
+	return &domain.RetryAfterError{Err: domain.ErrAccountLocked, Wait: s.throttle.lockout()}
+}
+
+// checkSecondFactor accepts a current TOTP code that hasn't been used yet, or
+// an unused recovery code. Either is spent in the database before it is
+// accepted, so two requests racing with the same code can't both pass; user
+// is updated to match.
+func checkSecondFactor(ctx context.Context, userRepo domain.UserRepository, auditRepo domain.AuditRepository, user *domain.User, code string) error {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+
+	if step, ok := totp.Validate(user.TOTPSecret, code, time.Now(), totpSkew); ok && step > user.TOTPLastStep {
+		err := userRepo.ClaimTOTPStep(ctx, user.ID.Hex(), step)
+		if err == nil {
+			user.TOTPLastStep = step
+			return nil
+		}
+		if !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+	}
+
+	hash := hashToken(code)
+	if i := slices.Index(user.RecoveryCodes, hash); i >= 0 {
+		err := userRepo.UseRecoveryCode(ctx, user.ID.Hex(), hash)
+		if err == nil {
+			user.RecoveryCodes = slices.Delete(user.RecoveryCodes, i, i+1)
+			return recordAudit(ctx, auditRepo, bson.ObjectID{}, user.ID.Hex(), domain.AuditRecoveryCodeUsed, "user", user.ID.Hex(), nil)
+		}
+		if !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+	}
+
+	if err := recordAudit(ctx, auditRepo, bson.ObjectID{}, user.ID.Hex(), domain.AuditMFAFailed, "user", user.ID.Hex(), nil); err != nil {
+		return err
+	}
+	return fmt.Errorf("invalid two-factor code: %w", domain.ErrUnauthorized)
+}
+
+// newMFAToken signs the challenge handed out after the password step. It
+// uses the refresh secret and an audience so it can't pass as an access token.
+func (s *authService) newMFAToken(user *domain.User) (string, error) {
+	now := time.Now()
+	claims := accessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.ID.Hex(),
+			Audience:  jwt.ClaimStrings{mfaAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(mfaTokenExpiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Version: user.TokenVersion,
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.cfg.RefreshSecret))
+}
+
+// revokeReusedSession ends a session whose rotated refresh token was
+// presented again, since either the client or an attacker holds a stolen copy.
+func (s *authService) revokeReusedSession(ctx context.Context, session *domain.Session) error {
+	userID := session.UserID.Hex()
+	if err := s.sessionRepo.Delete(ctx, userID, session.ID.Hex()); err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+	s.sessions.Invalidate(session.ID.Hex())
+	if err := recordAudit(ctx, s.auditRepo, bson.ObjectID{}, userID, domain.AuditRefreshTokenReused, "session", session.ID.Hex(), nil); err != nil {
+		return err
+	}
+	return domain.ErrTokenInvalid
+}
+
+func (s *authService) generateAccessToken(user *domain.User, sessionID string) (string, error) {
+	id, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := accessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			Subject:   user.ID.Hex(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Duration(s.cfg.AccessExpiryMinutes) * time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		SessionID: sessionID,
+		Version:   user.TokenVersion,
+	}
+	return s.keys.Sign(claims)
+}
+
+// newRefreshToken signs a refresh token. The random ID makes every token
+// unique, even two issued in the same second.
+func (s *authService) newRefreshToken(userID string) (string, time.Time, error) {
+	id, err := generateToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(s.cfg.RefreshExpiryDays) * 24 * time.Hour)
 	claims := jwt.RegisteredClaims{
+		ID:        id,
 		Subject:   userID,
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(now),
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.cfg.RefreshSecret))
+	return token, expiresAt, err
 }
 
-func (s *authService) parseJWT(tokenStr, secret string) (jwt.Claims, error) {
-	token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (any, error) {
+func (s *authService) parseJWT(tokenStr, secret string, claims jwt.Claims, opts ...jwt.ParserOption) error {
+	token, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (any, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, domain.ErrTokenInvalid
 		}
 		return []byte(secret), nil
-	})
+	}, opts...)
 	if err != nil || !token.Valid {
-		return nil, domain.ErrTokenInvalid
+		return domain.ErrTokenInvalid
 	}
-	return token.Claims, nil
+	return nil
 }
 
 func generateToken() (string, error) {
@@ -247,4 +857,26 @@ func generateToken() (string, error) {
 		return "", fmt.Errorf("failed to generate token: %w", err)
 	}
 	return hex.EncodeToString(b), nil
+}
+
+// hashToken returns the stored form of a high-entropy token. No salt or
+// slow hash is needed since the token cannot be guessed.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateRecoveryCodes returns fresh recovery codes, formatted for the
+// user, along with the hashes to store.
+func generateRecoveryCodes() (codes, hashes []string, err error) {
+	for range recoveryCodeCount {
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		code := hex.EncodeToString(b)
+		codes = append(codes, code[:4]+"-"+code[4:8]+"-"+code[8:12]+"-"+code[12:])
+		hashes = append(hashes, hashToken(code))
+	}
+	return codes, hashes, nil
 }
\ No newline at end of file
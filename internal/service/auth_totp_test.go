@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/0DayMonxrch/project-management-system/internal/config"
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// totpUserRepo keeps recovery codes and the last TOTP step out of Update,
+// as the MongoDB repository does.
+type totpUserRepo struct {
+	deletionUserRepo
+}
+
+func (r *totpUserRepo) Update(ctx context.Context, user *domain.User) error {
+	stored := r.users[user.ID.Hex()]
+	copied := *user
+	copied.RecoveryCodes, copied.TOTPLastStep = stored.RecoveryCodes, stored.TOTPLastStep
+	r.users[user.ID.Hex()] = &copied
+	return nil
+}
+
+func (r *totpUserRepo) SetRecoveryCodes(ctx context.Context, id string, hashes []string) error {
+	r.users[id].RecoveryCodes = hashes
+	return nil
+}
+
+func TestConfirmTOTPWritesCodesAndStepOutsideUpdate(t *testing.T) {
+	secret := newTOTPSecret(t)
+	user := &domain.User{ID: bson.NewObjectID(), TOTPSecret: secret}
+	users := &totpUserRepo{deletionUserRepo{memUserRepo{users: map[string]*domain.User{user.ID.Hex(): user}}}}
+	svc := NewAuthService(users, memSessionRepo{}, nopAuditRepo{}, nil, nil, NewSessionCache(nil, 0), nil, nopInvitations{}, nil, nil, config.JWTConfig{}, config.AuthConfig{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	code := currentCode(t, secret)
+	codes, err := svc.ConfirmTOTP(ctx, user.ID.Hex(), code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := users.users[user.ID.Hex()]
+	if !stored.TOTPEnabled || len(stored.RecoveryCodes) != len(codes) || stored.TOTPLastStep == 0 {
+		t.Fatalf("stored enabled=%v codes=%d step=%d, want enabled with %d codes and the step claimed", stored.TOTPEnabled, len(stored.RecoveryCodes), stored.TOTPLastStep, len(codes))
+	}
+
+	// The confirming code can't be replayed as a second factor
+	if err := checkSecondFactor(ctx, users, nopAuditRepo{}, stored, code); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Fatalf("replayed code: %v, want ErrUnauthorized", err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// mentionPattern matches @handles such as @alice, @alice.smith or @AliceSmith.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w.-]+)`)
+
+type commentService struct {
+	commentRepo domain.CommentRepository
+	taskRepo    domain.TaskRepository
+	projectRepo domain.ProjectRepository
+	userRepo    domain.UserRepository
+}
+
+func NewCommentService(commentRepo domain.CommentRepository, taskRepo domain.TaskRepository, projectRepo domain.ProjectRepository, userRepo domain.UserRepository) domain.CommentService {
+	return &commentService{commentRepo: commentRepo, taskRepo: taskRepo, projectRepo: projectRepo, userRepo: userRepo}
+}
+
+func (s *commentService) CreateComment(ctx context.Context, projectID, taskID, requesterID, parentID, content string) (*domain.Comment, error) {
+	project, task, err := findProjectTask(ctx, s.projectRepo, s.taskRepo, projectID, taskID, requesterID, domain.PermCommentWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	requesterOID, _ := bson.ObjectIDFromHex(requesterID)
+	comment := &domain.Comment{
+		ProjectID: project.ID,
+		TaskID:    task.ID,
+		Content:   content,
+		CreatedBy: requesterOID,
+	}
+
+	// Replies are only one level deep
+	if parentID != "" {
+		parent, err := s.commentRepo.FindByID(ctx, parentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.TaskID != task.ID {
+			return nil, domain.ErrNotFound
+		}
+		if !parent.ParentID.IsZero() {
+			return nil, fmt.Errorf("cannot reply to a reply: %w", domain.ErrInvalidInput)
+		}
+		comment.ParentID = parent.ID
+	}
+
+	comment.Mentions, err = s.resolveMentions(ctx, project, content)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.commentRepo.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *commentService) ListComments(ctx context.Context, projectID, taskID, requesterID string) ([]domain.Comment, error) {
+	if _, _, err := findProjectTask(ctx, s.projectRepo, s.taskRepo, projectID, taskID, requesterID, domain.PermTaskView); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.FindByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nest replies under their parent, keeping creation order
+	threads := make([]domain.Comment, 0, len(comments))
+	index := make(map[bson.ObjectID]int, len(comments))
+	for _, c := range comments {
+		if c.ParentID.IsZero() {
+			index[c.ID] = len(threads)
+			threads = append(threads, c)
+		}
+	}
+	for _, c := range comments {
+		if i, ok := index[c.ParentID]; ok {
+			threads[i].Replies = append(threads[i].Replies, c)
+		}
+	}
+	return threads, nil
+}
+
+func (s *commentService) UpdateComment(ctx context.Context, projectID, taskID, commentID, requesterID, content string) (*domain.Comment, error) {
+	project, task, err := findProjectTask(ctx, s.projectRepo, s.taskRepo, projectID, taskID, requesterID, domain.PermCommentWrite)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := s.commentRepo.FindByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.TaskID != task.ID {
+		return nil, domain.ErrNotFound
+	}
+	if comment.CreatedBy.Hex() != requesterID {
+		return nil, domain.ErrForbidden
+	}
+
+	comment.Content = content
+	comment.Mentions, err = s.resolveMentions(ctx, project, content)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.commentRepo.Update(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *commentService) DeleteComment(ctx context.Context, projectID, taskID, commentID, requesterID string) error {
+	project, task, err := findProjectTask(ctx, s.projectRepo, s.taskRepo, projectID, taskID, requesterID, domain.PermTaskView)
+	if err != nil {
+		return err
+	}
+	comment, err := s.commentRepo.FindByID(ctx, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.TaskID != task.ID {
+		return domain.ErrNotFound
+	}
+	// Authors can delete their own comments; moderators can delete any
+	perm := domain.PermCommentWrite
+	if comment.CreatedBy.Hex() != requesterID {
+		perm = domain.PermCommentDeleteAny
+	}
+	if err := authorize(project, requesterID, perm); err != nil {
+		return err
+	}
+
+	if err := s.commentRepo.Delete(ctx, commentID); err != nil {
+		return err
+	}
+	if comment.ParentID.IsZero() {
+		return s.commentRepo.DeleteReplies(ctx, commentID)
+	}
+	return nil
+}
+
+// --- helpers ---
+
+// resolveMentions maps @handles in content to project members. A handle
+// matches a member's name with spaces removed, or the local part of their
+// email, case-insensitively. Unknown handles are ignored.
+func (s *commentService) resolveMentions(ctx context.Context, project *domain.Project, content string) ([]bson.ObjectID, error) {
+	mentions := []bson.ObjectID{}
+
+	matches := mentionPattern.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		return mentions, nil
+	}
+
+	ids := make([]string, len(project.Members))
+	for i, m := range project.Members {
+		ids[i] = m.UserID.Hex()
+	}
+	members, err := s.userRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	handles := make(map[string]bson.ObjectID, len(members)*2)
+	for _, u := range members {
+		handles[strings.ToLower(strings.Join(strings.Fields(u.Name), ""))] = u.ID
+		if local, _, ok := strings.Cut(u.Email, "@"); ok {
+			handles[strings.ToLower(local)] = u.ID
+		}
+	}
+
+	seen := make(map[bson.ObjectID]bool)
+	for _, m := range matches {
+		handle := strings.ToLower(strings.TrimRight(m[1], ".-"))
+		if id, ok := handles[handle]; ok && !seen[id] {
+			seen[id] = true
+			mentions = append(mentions, id)
+		}
+	}
+	return mentions, nil
+}
\ No newline at end of file
@@ -27,6 +27,30 @@ func (s *emailService) SendPasswordResetEmail(to, token string) error {
 	return s.send(to, subject, body)
 }
 
+func (s *emailService) SendAccountLockedEmail(to, token string) error {
+	subject := "Your account has been locked"
+	body := fmt.Sprintf("Your account was locked after too many failed sign-in attempts. Click the link to unlock it: http://localhost:8080/api/v1/auth/unlock/%s", token)
+	return s.send(to, subject, body)
+}
+
+func (s *emailService) SendEmailChangeEmail(to, token string) error {
+	subject := "Confirm your new email"
+	body := fmt.Sprintf("Click the link to confirm your new email address: http://localhost:8080/api/v1/auth/confirm-email-change/%s", token)
+	return s.send(to, subject, body)
+}
+
+func (s *emailService) SendEmailChangeNotice(to, newEmail string) error {
+	subject := "Your email is being changed"
+	body := fmt.Sprintf("A request was made to change your account email to %s. If this wasn't you, change your password now.", newEmail)
+	return s.send(to, subject, body)
+}
+
+func (s *emailService) SendInvitationEmail(to, projectName, token string) error {
+	subject := fmt.Sprintf("You've been invited to %s", projectName)
+	body := fmt.Sprintf("You've been invited to join the project %s on Project Camp. Click the link to view the invitation: http://localhost:8080/api/v1/invitations/%s\n\nSign up or sign in with this email address and you'll be added to the project.", projectName, token)
+	return s.send(to, subject, body)
+}
+
 func (s *emailService) send(to, subject, body string) error {
 	auth := smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
 	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s", s.cfg.From, to, subject, body)
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// eventAccessTTL bounds how long a stream goes on using a member's
+// permissions after their role was edited; role edits aren't announced.
+const eventAccessTTL = time.Minute
+
+type eventService struct {
+	projectRepo domain.ProjectRepository
+	bus         domain.EventBus
+}
+
+func NewEventService(projectRepo domain.ProjectRepository, bus domain.EventBus) domain.EventService {
+	return &eventService{projectRepo: projectRepo, bus: bus}
+}
+
+func (s *eventService) Subscribe(ctx context.Context, projectID, userID, lastEventID string) (<-chan domain.ProjectEvent, error) {
+	project, err := authorizeProject(ctx, s.projectRepo, projectID, userID, domain.PermProjectView)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	events, err := s.bus.Subscribe(ctx, projectID, lastEventID)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// Only pass on what the subscriber may see, and end the stream once
+	// they are no longer a member
+	out := make(chan domain.ProjectEvent)
+	go func() {
+		defer close(out)
+		defer cancel()
+		loadedAt := time.Now()
+		for e := range events {
+			if time.Since(loadedAt) > eventAccessTTL || e.Type == domain.EventMemberRoleChanged && e.TargetID.Hex() == userID {
+				if project, err = s.projectRepo.FindByID(ctx, projectID); err != nil {
+					return
+				}
+				loadedAt = time.Now()
+			}
+			if !project.Can(userID, e.Type.Permission()) {
+				if !isMember(project, userID) {
+					return
+				}
+				continue
+			}
+			select {
+			case out <- e:
+			case <-ctx.Done():
+				return
+			}
+			if e.Type == domain.EventMemberRemoved && e.TargetID.Hex() == userID {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// --- helpers ---
+
+// publishEvent announces a change on the project's event stream.
+func publishEvent(ctx context.Context, bus domain.EventBus, projectID bson.ObjectID, actorID string, eventType domain.EventType, targetID bson.ObjectID, changes []domain.FieldChange, payload any) {
+	actorOID, _ := bson.ObjectIDFromHex(actorID)
+	bus.Publish(ctx, domain.ProjectEvent{
+		ProjectID: projectID,
+		Type:      eventType,
+		ActorID:   actorOID,
+		TargetID:  targetID,
+		Changes:   changes,
+		Payload:   payload,
+		CreatedAt: time.Now(),
+	})
+}
\ No newline at end of file
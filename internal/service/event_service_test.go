@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type chanEventBus struct {
+	domain.EventBus
+	events chan domain.ProjectEvent
+}
+
+func (b chanEventBus) Subscribe(ctx context.Context, projectID, lastEventID string) (<-chan domain.ProjectEvent, error) {
+	return b.events, nil
+}
+
+// newTaskOnlyProject has a member whose custom role shows tasks but not
+// notes.
+func newTaskOnlyProject() (*domain.Project, bson.ObjectID) {
+	viewer := bson.NewObjectID()
+	return &domain.Project{
+		ID:      bson.NewObjectID(),
+		Roles:   []domain.ProjectRole{{Name: "task_viewer", Permissions: []domain.Permission{domain.PermProjectView, domain.PermTaskView, domain.PermWebhookManage}}},
+		Members: []domain.ProjectMember{{UserID: viewer, Role: "task_viewer"}},
+	}, viewer
+}
+
+func TestSubscribeSkipsEventsTheMemberCannotSee(t *testing.T) {
+	project, viewer := newTaskOnlyProject()
+	bus := chanEventBus{events: make(chan domain.ProjectEvent, 3)}
+	svc := NewEventService(newMemProjectRepo(project), bus)
+
+	events, err := svc.Subscribe(context.Background(), project.ID.Hex(), viewer.Hex(), "")
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	bus.events <- domain.ProjectEvent{ID: "1", Type: domain.EventNoteCreated}
+	bus.events <- domain.ProjectEvent{ID: "2", Type: domain.EventTaskCreated}
+	bus.events <- domain.ProjectEvent{ID: "3", Type: domain.EventMemberAdded}
+	close(bus.events)
+
+	var got []string
+	for e := range events {
+		got = append(got, e.ID)
+	}
+	if len(got) != 2 || got[0] != "2" || got[1] != "3" {
+		t.Fatalf("received events %v, want [2 3]", got)
+	}
+}
+
+func TestSubscribeRechecksPermissionsWhenRoleChanges(t *testing.T) {
+	project, viewer := newTaskOnlyProject()
+	projects := newMemProjectRepo(project)
+	bus := chanEventBus{events: make(chan domain.ProjectEvent, 2)}
+	svc := NewEventService(projects, bus)
+
+	events, err := svc.Subscribe(context.Background(), project.ID.Hex(), viewer.Hex(), "")
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := projects.UpdateMemberRole(context.Background(), project.ID, viewer, domain.RoleMember); err != nil {
+		t.Fatalf("UpdateMemberRole: %v", err)
+	}
+	bus.events <- domain.ProjectEvent{ID: "1", Type: domain.EventMemberRoleChanged, TargetID: viewer}
+	bus.events <- domain.ProjectEvent{ID: "2", Type: domain.EventNoteCreated}
+	close(bus.events)
+
+	var got []string
+	for e := range events {
+		got = append(got, e.ID)
+	}
+	if len(got) != 2 {
+		t.Fatalf("received events %v, want the note once the role allows it", got)
+	}
+}
\ No newline at end of file
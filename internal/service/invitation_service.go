@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const invitationExpiry = 7 * 24 * time.Hour
+
+type invitationService struct {
+	invitationRepo domain.InvitationRepository
+	projectRepo    domain.ProjectRepository
+	userRepo       domain.UserRepository
+	auditRepo      domain.AuditRepository
+	email          domain.EmailService
+	events         domain.EventBus
+}
+
+func NewInvitationService(invitationRepo domain.InvitationRepository, projectRepo domain.ProjectRepository, userRepo domain.UserRepository, auditRepo domain.AuditRepository, email domain.EmailService, events domain.EventBus) domain.InvitationService {
+	return &invitationService{
+		invitationRepo: invitationRepo,
+		projectRepo:    projectRepo,
+		userRepo:       userRepo,
+		auditRepo:      auditRepo,
+		email:          email,
+		events:         events,
+	}
+}
+
+func (s *invitationService) Invite(ctx context.Context, projectID, requesterID, email string, role domain.Role) (*domain.Invitation, error) {
+	project, err := s.findAdminProject(ctx, projectID, requesterID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkGrant(project, requesterID, role); err != nil {
+		return nil, err
+	}
+	if user, err := s.userRepo.FindByEmail(ctx, email); err == nil && isMember(project, user.ID.Hex()) {
+		return nil, fmt.Errorf("user is already a member: %w", domain.ErrConflict)
+	}
+
+	email = strings.ToLower(email)
+	pending, err := s.invitationRepo.FindPendingByEmail(ctx, email, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, inv := range pending {
+		if inv.ProjectID == project.ID {
+			return nil, fmt.Errorf("email already has a pending invitation, revoke it first: %w", domain.ErrConflict)
+		}
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+	requesterOID, _ := bson.ObjectIDFromHex(requesterID)
+	invitation := &domain.Invitation{
+		ProjectID: project.ID,
+		Email:     email,
+		Role:      role,
+		TokenHash: hashToken(token),
+		Status:    domain.InvitationPending,
+		InvitedBy: requesterOID,
+		ExpiresAt: time.Now().Add(invitationExpiry),
+	}
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		return nil, err
+	}
+	// Nobody else knows the token, so an invitation whose email never went
+	// out would only block inviting the address again until it expired
+	if err := s.email.SendInvitationEmail(email, project.Name, token); err != nil {
+		if delErr := s.invitationRepo.Delete(ctx, invitation.ID.Hex()); delErr != nil {
+			return nil, errors.Join(err, delErr)
+		}
+		return nil, err
+	}
+
+	changes := []domain.FieldChange{{Field: "email", To: email}, {Field: "role", To: string(role)}}
+	if err := recordAudit(ctx, s.auditRepo, project.ID, requesterID, domain.AuditInvitationCreated, "invitation", invitation.ID.Hex(), changes); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+func (s *invitationService) ListInvitations(ctx context.Context, projectID, requesterID string) ([]domain.Invitation, error) {
+	if _, err := s.findAdminProject(ctx, projectID, requesterID); err != nil {
+		return nil, err
+	}
+	return s.invitationRepo.FindPendingByProjectID(ctx, projectID, time.Now())
+}
+
+func (s *invitationService) RevokeInvitation(ctx context.Context, projectID, invitationID, requesterID string) error {
+	project, err := s.findAdminProject(ctx, projectID, requesterID)
+	if err != nil {
+		return err
+	}
+	invitation, err := s.invitationRepo.FindByID(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.ProjectID != project.ID {
+		return domain.ErrNotFound
+	}
+	if invitation.Status != domain.InvitationPending {
+		return fmt.Errorf("invitation is already %s: %w", invitation.Status, domain.ErrConflict)
+	}
+
+	invitation.Status = domain.InvitationRevoked
+	if err := s.invitationRepo.Update(ctx, invitation); err != nil {
+		return err
+	}
+	changes := []domain.FieldChange{{Field: "email", From: invitation.Email}}
+	return recordAudit(ctx, s.auditRepo, project.ID, requesterID, domain.AuditInvitationRevoked, "invitation", invitationID, changes)
+}
+
+func (s *invitationService) GetInvitation(ctx context.Context, token string) (*domain.InvitationPreview, error) {
+	invitation, err := s.findPending(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	project, err := s.projectRepo.FindByID(ctx, invitation.ProjectID.Hex())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.InvitationPreview{
+		ProjectID:   project.ID,
+		ProjectName: project.Name,
+		Email:       invitation.Email,
+		Role:        invitation.Role,
+		ExpiresAt:   invitation.ExpiresAt,
+	}, nil
+}
+
+func (s *invitationService) AcceptInvitation(ctx context.Context, token, userID string) (*domain.Project, error) {
+	invitation, err := s.findPending(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, fmt.Errorf("invitation was sent to a different email: %w", domain.ErrForbidden)
+	}
+
+	project, err := s.accept(ctx, invitation, user.ID)
+	if errors.Is(err, domain.ErrNotFound) {
+		// The project was deleted
+		return nil, domain.ErrTokenInvalid
+	}
+	return project, err
+}
+
+func (s *invitationService) AcceptPending(ctx context.Context, user *domain.User) error {
+	invitations, err := s.invitationRepo.FindPendingByEmail(ctx, strings.ToLower(user.Email), time.Now())
+	if err != nil {
+		return err
+	}
+	for _, inv := range invitations {
+		if _, err := s.accept(ctx, &inv, user.ID); err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// --- helpers ---
+
+func (s *invitationService) findAdminProject(ctx context.Context, projectID, requesterID string) (*domain.Project, error) {
+	return authorizeProject(ctx, s.projectRepo, projectID, requesterID, domain.PermMemberManage)
+}
+
+// findPending resolves a raw token to an invitation that can still be accepted.
+func (s *invitationService) findPending(ctx context.Context, token string) (*domain.Invitation, error) {
+	invitation, err := s.invitationRepo.FindByTokenHash(ctx, hashToken(token))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if invitation.Status != domain.InvitationPending {
+		return nil, domain.ErrTokenInvalid
+	}
+	if invitation.Expired(time.Now()) {
+		return nil, domain.ErrTokenExpired
+	}
+	return invitation, nil
+}
+
+// accept makes the invitee a member, on behalf of whoever invited them, and
+// marks the invitation used. Someone who joined another way since being
+// invited just has the invitation marked.
+func (s *invitationService) accept(ctx context.Context, invitation *domain.Invitation, userID bson.ObjectID) (*domain.Project, error) {
+	project, err := s.projectRepo.FindByID(ctx, invitation.ProjectID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if !isMember(project, userID.Hex()) {
+		if err := addMember(ctx, s.projectRepo, s.auditRepo, s.events, project, userID, invitation.Role, invitation.InvitedBy.Hex()); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	invitation.Status = domain.InvitationAccepted
+	invitation.AcceptedBy = userID
+	invitation.AcceptedAt = &now
+	if err := s.invitationRepo.Update(ctx, invitation); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.auditRepo, project.ID, userID.Hex(), domain.AuditInvitationAccepted, "invitation", invitation.ID.Hex(), nil); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
\ No newline at end of file
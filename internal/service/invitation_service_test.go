@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type memInvitationRepo struct {
+	domain.InvitationRepository
+	invitations map[bson.ObjectID]*domain.Invitation
+}
+
+func (r *memInvitationRepo) Create(ctx context.Context, invitation *domain.Invitation) error {
+	invitation.ID = bson.NewObjectID()
+	r.invitations[invitation.ID] = invitation
+	return nil
+}
+
+func (r *memInvitationRepo) FindPendingByEmail(ctx context.Context, email string, now time.Time) ([]domain.Invitation, error) {
+	var pending []domain.Invitation
+	for _, inv := range r.invitations {
+		if inv.Email == email && inv.Status == domain.InvitationPending && !inv.Expired(now) {
+			pending = append(pending, *inv)
+		}
+	}
+	return pending, nil
+}
+
+func (r *memInvitationRepo) Delete(ctx context.Context, id string) error {
+	oid, _ := bson.ObjectIDFromHex(id)
+	delete(r.invitations, oid)
+	return nil
+}
+
+type invitationMailer struct {
+	domain.EmailService
+	err  error
+	sent int
+}
+
+func (m *invitationMailer) SendInvitationEmail(to, projectName, token string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent++
+	return nil
+}
+
+func TestInviteDropsInvitationWhenEmailFails(t *testing.T) {
+	admin := bson.NewObjectID()
+	project := &domain.Project{ID: bson.NewObjectID(), Name: "Apollo", Members: []domain.ProjectMember{{UserID: admin, Role: domain.RoleAdmin}}}
+	invitations := &memInvitationRepo{invitations: map[bson.ObjectID]*domain.Invitation{}}
+	mailer := &invitationMailer{err: errors.New("smtp unavailable")}
+	users := &memUserRepo{users: map[string]*domain.User{}}
+	svc := NewInvitationService(invitations, newMemProjectRepo(project), users, nopAuditRepo{}, mailer, nopEventBus{})
+	ctx := context.Background()
+
+	if _, err := svc.Invite(ctx, project.ID.Hex(), admin.Hex(), "grace@example.com", domain.RoleMember); err == nil {
+		t.Fatal("Invite succeeded without sending the email")
+	}
+	if len(invitations.invitations) != 0 {
+		t.Fatal("the unsent invitation was kept")
+	}
+
+	// The address can be invited again once email works
+	mailer.err = nil
+	if _, err := svc.Invite(ctx, project.ID.Hex(), admin.Hex(), "grace@example.com", domain.RoleMember); err != nil {
+		t.Fatalf("Invite again: %v", err)
+	}
+	if mailer.sent != 1 || len(invitations.invitations) != 1 {
+		t.Fatalf("sent %d emails for %d invitations, want 1 each", mailer.sent, len(invitations.invitations))
+	}
+}
\ No newline at end of file
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type joinLinkService struct {
+	linkRepo    domain.JoinLinkRepository
+	projectRepo domain.ProjectRepository
+	auditRepo   domain.AuditRepository
+	events      domain.EventBus
+	log         *slog.Logger
+}
+
+func NewJoinLinkService(linkRepo domain.JoinLinkRepository, projectRepo domain.ProjectRepository, auditRepo domain.AuditRepository, events domain.EventBus, log *slog.Logger) domain.JoinLinkService {
+	return &joinLinkService{linkRepo: linkRepo, projectRepo: projectRepo, auditRepo: auditRepo, events: events, log: log}
+}
+
+func (s *joinLinkService) CreateJoinLink(ctx context.Context, projectID, requesterID string, role domain.Role, maxUses int, expiresAt *time.Time) (*domain.JoinLink, string, error) {
+	project, err := s.findAdminProject(ctx, projectID, requesterID)
+	if err != nil {
+		return nil, "", err
+	}
+	// Admin rights are only ever handed out one person at a time
+	if role == domain.RoleAdmin {
+		return nil, "", fmt.Errorf("join links can't grant %q: %w", domain.RoleAdmin, domain.ErrInvalidInput)
+	}
+	if err := checkGrant(project, requesterID, role); err != nil {
+		return nil, "", err
+	}
+	if maxUses < 0 {
+		return nil, "", fmt.Errorf("max_uses must not be negative: %w", domain.ErrInvalidInput)
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", fmt.Errorf("expires_at must be in the future: %w", domain.ErrInvalidInput)
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+	requesterOID, _ := bson.ObjectIDFromHex(requesterID)
+	link := &domain.JoinLink{
+		ProjectID: project.ID,
+		Role:      role,
+		Prefix:    token[:8],
+		TokenHash: hashToken(token),
+		MaxUses:   maxUses,
+		ExpiresAt: expiresAt,
+		CreatedBy: requesterOID,
+	}
+	if err := s.linkRepo.Create(ctx, link); err != nil {
+		return nil, "", err
+	}
+
+	changes := []domain.FieldChange{{Field: "role", To: string(role)}, {Field: "max_uses", To: strconv.Itoa(maxUses)}}
+	if expiresAt != nil {
+		changes = append(changes, domain.FieldChange{Field: "expires_at", To: expiresAt.Format(time.RFC3339)})
+	}
+	if err := recordAudit(ctx, s.auditRepo, project.ID, requesterID, domain.AuditJoinLinkCreated, "join_link", link.ID.Hex(), changes); err != nil {
+		return nil, "", err
+	}
+	return link, token, nil
+}
+
+func (s *joinLinkService) ListJoinLinks(ctx context.Context, projectID, requesterID string) ([]domain.JoinLink, error) {
+	if _, err := s.findAdminProject(ctx, projectID, requesterID); err != nil {
+		return nil, err
+	}
+	return s.linkRepo.FindByProjectID(ctx, projectID)
+}
+
+func (s *joinLinkService) RevokeJoinLink(ctx context.Context, projectID, linkID, requesterID string) error {
+	if _, err := s.findAdminProject(ctx, projectID, requesterID); err != nil {
+		return err
+	}
+	link, err := s.findLink(ctx, projectID, linkID)
+	if err != nil {
+		return err
+	}
+	if link.RevokedAt != nil {
+		return fmt.Errorf("join link is already revoked: %w", domain.ErrConflict)
+	}
+
+	// Kept rather than deleted so its uses stay attributable
+	if err := s.linkRepo.Revoke(ctx, link.ID, time.Now()); err != nil {
+		return err
+	}
+	changes := []domain.FieldChange{{Field: "role", From: string(link.Role)}}
+	return recordAudit(ctx, s.auditRepo, link.ProjectID, requesterID, domain.AuditJoinLinkRevoked, "join_link", linkID, changes)
+}
+
+func (s *joinLinkService) ListJoinLinkUses(ctx context.Context, projectID, linkID, requesterID string) ([]domain.JoinLinkUse, error) {
+	if _, err := s.findAdminProject(ctx, projectID, requesterID); err != nil {
+		return nil, err
+	}
+	if _, err := s.findLink(ctx, projectID, linkID); err != nil {
+		return nil, err
+	}
+	return s.linkRepo.FindUses(ctx, linkID)
+}
+
+func (s *joinLinkService) Join(ctx context.Context, token, userID string) (*domain.Project, error) {
+	userOID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+
+	link, err := s.linkRepo.FindByTokenHash(ctx, hashToken(token))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if link.RevokedAt != nil {
+		return nil, domain.ErrTokenInvalid
+	}
+	if link.ExpiresAt != nil && !time.Now().Before(*link.ExpiresAt) {
+		return nil, domain.ErrTokenExpired
+	}
+
+	project, err := s.projectRepo.FindByID(ctx, link.ProjectID.Hex())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Checked before claiming so members opening the link again don't use it up
+	if isMember(project, userID) {
+		return nil, fmt.Errorf("user is already a member: %w", domain.ErrConflict)
+	}
+
+	err = s.linkRepo.ClaimUse(ctx, link.ID, time.Now())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("join link has been used up: %w", domain.ErrTokenInvalid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := addMember(ctx, s.projectRepo, s.auditRepo, s.events, project, userOID, link.Role, link.CreatedBy.Hex()); err != nil {
+		if releaseErr := s.linkRepo.ReleaseUse(ctx, link.ID); releaseErr != nil {
+			return nil, errors.Join(err, releaseErr)
+		}
+		return nil, err
+	}
+
+	// The user has joined; a missing row in the link's history isn't worth
+	// telling them otherwise
+	use := &domain.JoinLinkUse{LinkID: link.ID, ProjectID: project.ID, UserID: userOID}
+	if err := s.linkRepo.CreateUse(ctx, use); err != nil {
+		s.log.Error("failed to record join link use", "link_id", link.ID.Hex(), "user_id", userID, "error", err)
+	}
+	return project, nil
+}
+
+// --- helpers ---
+
+func (s *joinLinkService) findAdminProject(ctx context.Context, projectID, requesterID string) (*domain.Project, error) {
+	return authorizeProject(ctx, s.projectRepo, projectID, requesterID, domain.PermMemberManage)
+}
+
+func (s *joinLinkService) findLink(ctx context.Context, projectID, linkID string) (*domain.JoinLink, error) {
+	link, err := s.linkRepo.FindByID(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link.ProjectID.Hex() != projectID {
+		return nil, domain.ErrNotFound
+	}
+	return link, nil
+}
\ No newline at end of file
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type memJoinLinkRepo struct {
+	domain.JoinLinkRepository
+	mu     sync.Mutex
+	link   domain.JoinLink
+	uses   []domain.JoinLinkUse
+	useErr error
+}
+
+func (r *memJoinLinkRepo) FindByTokenHash(ctx context.Context, hash string) (*domain.JoinLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if hash != r.link.TokenHash {
+		return nil, domain.ErrNotFound
+	}
+	copied := r.link
+	return &copied, nil
+}
+
+func (r *memJoinLinkRepo) ClaimUse(ctx context.Context, id bson.ObjectID, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.link.MaxUses > 0 && r.link.Uses >= r.link.MaxUses {
+		return domain.ErrNotFound
+	}
+	r.link.Uses++
+	return nil
+}
+
+func (r *memJoinLinkRepo) ReleaseUse(ctx context.Context, id bson.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.link.Uses--
+	return nil
+}
+
+func (r *memJoinLinkRepo) CreateUse(ctx context.Context, use *domain.JoinLinkUse) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.useErr != nil {
+		return r.useErr
+	}
+	r.uses = append(r.uses, *use)
+	return nil
+}
+
+func newJoinFixture(maxUses int) (domain.JoinLinkService, *memJoinLinkRepo, *memProjectRepo, *domain.Project) {
+	admin := bson.NewObjectID()
+	project := &domain.Project{ID: bson.NewObjectID(), Members: []domain.ProjectMember{{UserID: admin, Role: domain.RoleAdmin}}}
+	projects := newMemProjectRepo(project)
+	links := &memJoinLinkRepo{link: domain.JoinLink{ID: bson.NewObjectID(), ProjectID: project.ID, Role: domain.RoleMember, TokenHash: hashToken("join"), MaxUses: maxUses, CreatedBy: admin}}
+	svc := NewJoinLinkService(links, projects, nopAuditRepo{}, nopEventBus{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return svc, links, projects, project
+}
+
+func TestJoinAddsUserOnceWhenRacing(t *testing.T) {
+	svc, links, projects, project := newJoinFixture(0)
+	user := bson.NewObjectID()
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		joined int
+	)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.Join(context.Background(), "join", user.Hex())
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				joined++
+			case !errors.Is(err, domain.ErrConflict):
+				t.Errorf("Join: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if joined != 1 {
+		t.Fatalf("%d joins succeeded, want 1", joined)
+	}
+	if n := len(projects.members(project.ID)); n != 2 {
+		t.Fatalf("project has %d members, want 2", n)
+	}
+	// Joins that lost the race gave their use back
+	if links.link.Uses != 1 {
+		t.Fatalf("link uses = %d, want 1", links.link.Uses)
+	}
+}
+
+func TestJoinSucceedsWhenUseIsNotRecorded(t *testing.T) {
+	svc, links, projects, project := newJoinFixture(1)
+	links.useErr = errors.New("database unavailable")
+	user := bson.NewObjectID()
+
+	joined, err := svc.Join(context.Background(), "join", user.Hex())
+	if err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	if !isMember(joined, user.Hex()) || len(projects.members(project.ID)) != 2 {
+		t.Fatal("user was not added")
+	}
+	if links.link.Uses != 1 {
+		t.Fatalf("link uses = %d, want the join to keep its use", links.link.Uses)
+	}
+}
\ No newline at end of file
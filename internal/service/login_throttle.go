@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/config"
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+)
+
+// loginThrottle slows down and then locks out repeated failed sign-ins. It
+// counts failures per account and per client IP, so neither guessing one
+// account's password nor spraying many accounts from one address scales.
+type loginThrottle struct {
+	store domain.AttemptStore
+	cfg   config.ThrottleConfig
+}
+
+// loginAttempt is a sign-in that reserve has already counted as failed.
+type loginAttempt struct {
+	ip    string
+	email string
+	// ipFailures and failures are the counts this attempt brought the IP
+	// and account to; zero if it wasn't counted against them
+	ipFailures int
+	failures   int
+}
+
+// reserve rejects an attempt while the IP or account is locked out, or
+// before the account's delay since its last failure has passed. Otherwise
+// it counts the attempt as failed up front, so requests racing past these
+// checks while the password hash is compared can't go over the limits;
+// refund the attempt if it succeeds. Unknown emails are throttled the same
+// way, so responses don't reveal which accounts exist.
+func (t *loginThrottle) reserve(ctx context.Context, ip, email string) (*loginAttempt, error) {
+	now := time.Now()
+	if ip != "" {
+		record, err := t.store.Get(ctx, ipThrottleKey("login", ip))
+		if err != nil {
+			return nil, err
+		}
+		if record.Locked(now) {
+			return nil, &domain.RetryAfterError{Err: domain.ErrTooManyAttempts, Wait: record.LockedUntil.Sub(now)}
+		}
+	}
+
+	record, err := t.store.Get(ctx, accountThrottleKey("login", email))
+	if err != nil {
+		return nil, err
+	}
+	if record.Locked(now) {
+		return nil, &domain.RetryAfterError{Err: domain.ErrAccountLocked, Wait: record.LockedUntil.Sub(now)}
+	}
+	if wait := record.LastFailure.Add(t.delay(record.Failures)).Sub(now); wait > 0 {
+		return nil, &domain.RetryAfterError{Err: domain.ErrTooManyAttempts, Wait: wait}
+	}
+
+	attempt := &loginAttempt{ip: ip, email: email}
+	if ip != "" {
+		record, err := t.store.Fail(ctx, ipThrottleKey("login", ip), t.window())
+		if err != nil {
+			return nil, err
+		}
+		attempt.ipFailures = record.Failures
+		// Attempts still in flight have taken the IP's last tries
+		if t.cfg.IPMaxFailures > 0 && record.Failures > t.cfg.IPMaxFailures {
+			return nil, t.reject(ctx, attempt, domain.ErrTooManyAttempts)
+		}
+	}
+
+	record, err = t.store.Fail(ctx, accountThrottleKey("login", email), t.window())
+	if err != nil {
+		return nil, err
+	}
+	attempt.failures = record.Failures
+	if t.cfg.MaxFailures > 0 && record.Failures > t.cfg.MaxFailures {
+		return nil, t.reject(ctx, attempt, domain.ErrAccountLocked)
+	}
+	return attempt, nil
+}
+
+// fail locks out the IP or account if the failed attempt reached its limit,
+// and reports whether the account was locked.
+func (t *loginThrottle) fail(ctx context.Context, attempt *loginAttempt) (bool, error) {
+	lockedUntil := time.Now().Add(t.lockout())
+	if t.cfg.IPMaxFailures > 0 && attempt.ipFailures >= t.cfg.IPMaxFailures {
+		if err := t.store.Lock(ctx, ipThrottleKey("login", attempt.ip), lockedUntil); err != nil {
+			return false, err
+		}
+	}
+	if t.cfg.MaxFailures == 0 || attempt.failures < t.cfg.MaxFailures {
+		return false, nil
+	}
+	return true, t.store.Lock(ctx, accountThrottleKey("login", attempt.email), lockedUntil)
+}
+
+// refund takes back the failures reserve counted for an attempt that
+// didn't fail.
+func (t *loginThrottle) refund(ctx context.Context, attempt *loginAttempt) error {
+	if attempt.ipFailures > 0 {
+		if err := t.store.Refund(ctx, ipThrottleKey("login", attempt.ip)); err != nil {
+			return err
+		}
+	}
+	if attempt.failures > 0 {
+		return t.store.Refund(ctx, accountThrottleKey("login", attempt.email))
+	}
+	return nil
+}
+
+// reject refunds an attempt that went over a limit before it was tried.
+func (t *loginThrottle) reject(ctx context.Context, attempt *loginAttempt, cause error) error {
+	if err := t.refund(ctx, attempt); err != nil {
+		return err
+	}
+	return &domain.RetryAfterError{Err: cause, Wait: t.lockout()}
+}
+
+// unlock clears the account's failures and any lockout. The IP's count is
+// left alone, since one good password says nothing about other accounts.
+func (t *loginThrottle) unlock(ctx context.Context, email string) error {
+	return t.store.Reset(ctx, accountThrottleKey("login", email))
+}
+
+// limitEmails counts an email sent for purpose, rejecting it once the
+// account or the IP has had MaxEmails of them this window.
+func (t *loginThrottle) limitEmails(ctx context.Context, purpose, ip, email string) error {
+	if t.cfg.MaxEmails == 0 {
+		return nil
+	}
+	keys := []string{accountThrottleKey(purpose, email)}
+	if ip != "" {
+		keys = append(keys, ipThrottleKey(purpose, ip))
+	}
+
+	now := time.Now()
+	for _, key := range keys {
+		record, err := t.store.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if record.Failures >= t.cfg.MaxEmails {
+			return &domain.RetryAfterError{Err: domain.ErrTooManyAttempts, Wait: record.ExpiresAt.Sub(now)}
+		}
+	}
+	for _, key := range keys {
+		if _, err := t.store.Fail(ctx, key, t.window()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// delay is how long an account must wait after its last failure: nothing
+// for the first FreeAttempts, then doubling up to the lockout duration.
+func (t *loginThrottle) delay(failures int) time.Duration {
+	if failures < t.cfg.FreeAttempts || t.cfg.BaseDelaySeconds == 0 {
+		return 0
+	}
+	wait := time.Duration(t.cfg.BaseDelaySeconds) * time.Second << min(failures-t.cfg.FreeAttempts, 20)
+	return min(wait, t.lockout())
+}
+
+func (t *loginThrottle) window() time.Duration {
+	return time.Duration(t.cfg.WindowMinutes) * time.Minute
+}
+
+func (t *loginThrottle) lockout() time.Duration {
+	return time.Duration(t.cfg.LockoutMinutes) * time.Minute
+}
+
+func accountThrottleKey(purpose, email string) string {
+	return purpose + ":account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipThrottleKey(purpose, ip string) string {
+	return purpose + ":ip:" + ip
+}
\ No newline at end of file
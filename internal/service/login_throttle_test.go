@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/config"
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"github.com/0DayMonxrch/project-management-system/internal/jwtkeys"
+	"github.com/0DayMonxrch/project-management-system/internal/throttle"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testThrottleConfig = config.ThrottleConfig{
+	WindowMinutes:  15,
+	MaxFailures:    3,
+	IPMaxFailures:  10,
+	LockoutMinutes: 15,
+}
+
+func TestReserveCapsConcurrentAttempts(t *testing.T) {
+	store := throttle.NewMemoryStore()
+	th := &loginThrottle{store: store, cfg: testThrottleConfig}
+	ctx := context.Background()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		admitted int
+	)
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := th.reserve(ctx, "198.51.100.1", "ada@example.com"); err == nil {
+				mu.Lock()
+				admitted++
+				mu.Unlock()
+			} else if !errors.Is(err, domain.ErrAccountLocked) {
+				t.Errorf("reserve: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if admitted != testThrottleConfig.MaxFailures {
+		t.Fatalf("%d attempts got past the throttle, want %d", admitted, testThrottleConfig.MaxFailures)
+	}
+	// Rejected attempts were never tried, so they don't count against the IP
+	record, _ := store.Get(ctx, ipThrottleKey("login", "198.51.100.1"))
+	if record.Failures != testThrottleConfig.MaxFailures {
+		t.Fatalf("IP failures = %d, want %d", record.Failures, testThrottleConfig.MaxFailures)
+	}
+}
+
+func TestRefundTakesBackReservation(t *testing.T) {
+	store := throttle.NewMemoryStore()
+	th := &loginThrottle{store: store, cfg: testThrottleConfig}
+	ctx := context.Background()
+
+	for range testThrottleConfig.MaxFailures + 2 {
+		attempt, err := th.reserve(ctx, "198.51.100.1", "ada@example.com")
+		if err != nil {
+			t.Fatalf("reserve: %v", err)
+		}
+		if err := th.refund(ctx, attempt); err != nil {
+			t.Fatalf("refund: %v", err)
+		}
+	}
+	for _, key := range []string{ipThrottleKey("login", "198.51.100.1"), accountThrottleKey("login", "ada@example.com")} {
+		if record, _ := store.Get(ctx, key); record.Failures != 0 {
+			t.Errorf("%s failures = %d after refunds", key, record.Failures)
+		}
+	}
+}
+
+type failingEmail struct {
+	domain.EmailService
+}
+
+func (failingEmail) SendAccountLockedEmail(to, token string) error {
+	return errors.New("smtp unavailable")
+}
+
+func TestLoginLocksAccountWhenEmailFails(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &domain.User{ID: bson.NewObjectID(), Email: "ada@example.com", Password: string(hash), IsEmailVerified: true}
+	users := &memUserRepo{users: map[string]*domain.User{user.ID.Hex(): user}}
+
+	jwtCfg := config.JWTConfig{AccessSecret: "access", RefreshSecret: "refresh", AccessExpiryMinutes: 15, RefreshExpiryDays: 7}
+	keys, err := jwtkeys.Load(jwtCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authCfg := config.AuthConfig{Throttle: testThrottleConfig}
+	svc := NewAuthService(users, memSessionRepo{}, nopAuditRepo{}, failingEmail{}, nil, NewSessionCache(nil, 0), throttle.NewMemoryStore(), nopInvitations{}, nil, keys, jwtCfg, authCfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	for i := 1; i < testThrottleConfig.MaxFailures; i++ {
+		if _, err := svc.Login(ctx, user.Email, "wrong", domain.ClientInfo{}); !errors.Is(err, domain.ErrUnauthorized) {
+			t.Fatalf("attempt %d: %v, want ErrUnauthorized", i, err)
+		}
+	}
+	if _, err := svc.Login(ctx, user.Email, "wrong", domain.ClientInfo{}); !errors.Is(err, domain.ErrAccountLocked) {
+		t.Fatalf("locking attempt: %v, want ErrAccountLocked despite the email failing", err)
+	}
+	if _, err := svc.Login(ctx, user.Email, "correct horse", domain.ClientInfo{}); !errors.Is(err, domain.ErrAccountLocked) {
+		t.Fatalf("login while locked: %v, want ErrAccountLocked", err)
+	}
+
+	locked := users.users[user.ID.Hex()]
+	if locked.UnlockToken == "" {
+		t.Fatal("no unlock token was issued")
+	}
+	if until := time.Until(locked.UnlockTokenExpiry); until <= 0 || until > 15*time.Minute {
+		t.Fatalf("unlock token expires in %v, want within the lockout", until)
+	}
+
+	// An expired link doesn't lift the lockout
+	locked.UnlockTokenExpiry = time.Now().Add(-time.Second)
+	if err := svc.UnlockAccount(ctx, locked.UnlockToken); !errors.Is(err, domain.ErrTokenInvalid) {
+		t.Fatalf("UnlockAccount with expired token: %v, want ErrTokenInvalid", err)
+	}
+}
\ No newline at end of file
@@ -10,20 +10,18 @@ import (
 type noteService struct {
 	noteRepo    domain.NoteRepository
 	projectRepo domain.ProjectRepository
+	auditRepo   domain.AuditRepository
+	events      domain.EventBus
 }
 
-func NewNoteService(noteRepo domain.NoteRepository, projectRepo domain.ProjectRepository) domain.NoteService {
-	return &noteService{noteRepo: noteRepo, projectRepo: projectRepo}
+func NewNoteService(noteRepo domain.NoteRepository, projectRepo domain.ProjectRepository, auditRepo domain.AuditRepository, events domain.EventBus) domain.NoteService {
+	return &noteService{noteRepo: noteRepo, projectRepo: projectRepo, auditRepo: auditRepo, events: events}
 }
 
 func (s *noteService) CreateNote(ctx context.Context, projectID, requesterID, title, content string) (*domain.Note, error) {
-	project, err := s.projectRepo.FindByID(ctx, projectID)
-	if err != nil {
+	if _, err := authorizeProject(ctx, s.projectRepo, projectID, requesterID, domain.PermNoteWrite); err != nil {
 		return nil, err
 	}
-	if !hasRole(project, requesterID, domain.RoleAdmin) {
-		return nil, domain.ErrForbidden
-	}
 
 	projectOID, _ := bson.ObjectIDFromHex(projectID)
 	requesterOID, _ := bson.ObjectIDFromHex(requesterID)
@@ -38,56 +36,90 @@ func (s *noteService) CreateNote(ctx context.Context, projectID, requesterID, ti
 	if err := s.noteRepo.Create(ctx, note); err != nil {
 		return nil, err
 	}
-	return note, nil
-}
-
-func (s *noteService) GetNote(ctx context.Context, projectID, noteID string) (*domain.Note, error) {
-	note, err := s.noteRepo.FindByID(ctx, noteID)
-	if err != nil {
+	changes := []domain.FieldChange{{Field: "title", To: title}, {Field: "content", To: contentDigest(content)}}
+	if err := recordAudit(ctx, s.auditRepo, note.ProjectID, requesterID, domain.AuditNoteCreated, "note", note.ID.Hex(), changes); err != nil {
 		return nil, err
 	}
-	if note.ProjectID.Hex() != projectID {
-		return nil, domain.ErrNotFound
-	}
+	publishEvent(ctx, s.events, note.ProjectID, requesterID, domain.EventNoteCreated, note.ID, changes, *note)
 	return note, nil
 }
 
-func (s *noteService) ListNotes(ctx context.Context, projectID string) ([]domain.Note, error) {
-	return s.noteRepo.FindByProjectID(ctx, projectID)
+func (s *noteService) GetNote(ctx context.Context, projectID, noteID, requesterID string) (*domain.Note, error) {
+	return s.findProjectNote(ctx, projectID, noteID, requesterID, domain.PermNoteView)
 }
 
-func (s *noteService) UpdateNote(ctx context.Context, noteID, requesterID, title, content string) (*domain.Note, error) {
-	note, err := s.noteRepo.FindByID(ctx, noteID)
-	if err != nil {
+func (s *noteService) ListNotes(ctx context.Context, projectID, requesterID string, page domain.PageRequest) (*domain.Page[domain.Note], error) {
+	if _, err := authorizeProject(ctx, s.projectRepo, projectID, requesterID, domain.PermNoteView); err != nil {
 		return nil, err
 	}
-	project, err := s.projectRepo.FindByID(ctx, note.ProjectID.Hex())
+	page, err := normalizePage(page, "created_at", "updated_at", "title")
 	if err != nil {
 		return nil, err
 	}
-	if !hasRole(project, requesterID, domain.RoleAdmin) {
-		return nil, domain.ErrForbidden
+	return s.noteRepo.FindByProjectID(ctx, projectID, page)
+}
+
+func (s *noteService) UpdateNote(ctx context.Context, projectID, noteID, requesterID, title, content string) (*domain.Note, error) {
+	note, err := s.findProjectNote(ctx, projectID, noteID, requesterID, domain.PermNoteWrite)
+	if err != nil {
+		return nil, err
 	}
 
+	var changes []domain.FieldChange
+	changes = appendChange(changes, "title", note.Title, title)
+	changes = appendChange(changes, "content", contentDigest(note.Content), contentDigest(content))
+
 	note.Title = title
 	note.Content = content
 	if err := s.noteRepo.Update(ctx, note); err != nil {
 		return nil, err
 	}
+	if err := recordAudit(ctx, s.auditRepo, note.ProjectID, requesterID, domain.AuditNoteUpdated, "note", noteID, changes); err != nil {
+		return nil, err
+	}
+	publishEvent(ctx, s.events, note.ProjectID, requesterID, domain.EventNoteUpdated, note.ID, changes, *note)
 	return note, nil
 }
 
-func (s *noteService) DeleteNote(ctx context.Context, noteID, requesterID string) error {
-	note, err := s.noteRepo.FindByID(ctx, noteID)
+func (s *noteService) DeleteNote(ctx context.Context, projectID, noteID, requesterID string) error {
+	note, err := s.findProjectNote(ctx, projectID, noteID, requesterID, domain.PermNoteWrite)
 	if err != nil {
 		return err
 	}
-	project, err := s.projectRepo.FindByID(ctx, note.ProjectID.Hex())
-	if err != nil {
+	if err := s.noteRepo.Delete(ctx, noteID); err != nil {
 		return err
 	}
-	if !hasRole(project, requesterID, domain.RoleAdmin) {
-		return domain.ErrForbidden
+	changes := []domain.FieldChange{{Field: "title", From: note.Title}, {Field: "content", From: contentDigest(note.Content)}}
+	if err := recordAudit(ctx, s.auditRepo, note.ProjectID, requesterID, domain.AuditNoteDeleted, "note", noteID, changes); err != nil {
+		return err
+	}
+	publishEvent(ctx, s.events, note.ProjectID, requesterID, domain.EventNoteDeleted, note.ID, changes, nil)
+	return nil
+}
+
+// --- helpers ---
+
+// contentDigest stands in for note content in the audit log, which shows
+// that the content changed without keeping a copy of it.
+func contentDigest(content string) string {
+	if content == "" {
+		return ""
 	}
-	return s.noteRepo.Delete(ctx, noteID)
+	return "sha256:" + hashToken(content)[:16]
+}
+
+// findProjectNote loads a note, checking that it belongs to the project and
+// that the requester's role there grants perm.
+func (s *noteService) findProjectNote(ctx context.Context, projectID, noteID, requesterID string, perm domain.Permission) (*domain.Note, error) {
+	if _, err := authorizeProject(ctx, s.projectRepo, projectID, requesterID, perm); err != nil {
+		return nil, err
+	}
+	note, err := s.noteRepo.FindByID(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if note.ProjectID.Hex() != projectID {
+		return nil, domain.ErrNotFound
+	}
+	return note, nil
 }
\ No newline at end of file
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+	defaultPageSort = "created_at"
+)
+
+// normalizePage fills in page defaults and rejects sort fields that are not
+// in allowed.
+func normalizePage(page domain.PageRequest, allowed ...string) (domain.PageRequest, error) {
+	page.Limit = clampPageSize(page.Limit, defaultPageSize, maxPageSize)
+	if page.Sort == "" {
+		page.Sort = defaultPageSort
+	}
+	if !slices.Contains(allowed, strings.TrimPrefix(page.Sort, "-")) {
+		return page, fmt.Errorf("sort must be one of: %s: %w", strings.Join(allowed, ", "), domain.ErrInvalidInput)
+	}
+	return page, nil
+}
+
+func clampPageSize(limit, def, max int) int {
+	if limit <= 0 {
+		return def
+	}
+	if limit > max {
+		return max
+	}
+	return limit
+}
\ No newline at end of file
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+)
+
+// authorizeProject loads a project, unless the request already carries it,
+// and checks that userID's role in it grants perm.
+func authorizeProject(ctx context.Context, projectRepo domain.ProjectRepository, projectID, userID string, perm domain.Permission) (*domain.Project, error) {
+	project, ok := domain.ProjectFromContext(ctx, projectID)
+	if !ok {
+		var err error
+		if project, err = projectRepo.FindByID(ctx, projectID); err != nil {
+			return nil, err
+		}
+	}
+	if err := authorize(project, userID, perm); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+func authorize(p *domain.Project, userID string, perm domain.Permission) error {
+	if !p.Can(userID, perm) {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+// checkGrant makes sure role exists in the project and that requesterID
+// holds every permission it carries, so nobody can hand out more than they
+// have.
+func checkGrant(p *domain.Project, requesterID string, role domain.Role) error {
+	r, ok := p.FindRole(role)
+	if !ok {
+		return fmt.Errorf("unknown role %q: %w", role, domain.ErrInvalidInput)
+	}
+	return checkPermissions(p, requesterID, r.Permissions)
+}
+
+// checkOutranks makes sure requesterID holds every permission of member's
+// role before changing or removing them.
+func checkOutranks(p *domain.Project, requesterID string, member domain.ProjectMember) error {
+	r, ok := p.FindRole(member.Role)
+	if !ok {
+		return nil
+	}
+	return checkPermissions(p, requesterID, r.Permissions)
+}
+
+func checkPermissions(p *domain.Project, requesterID string, perms []domain.Permission) error {
+	for _, perm := range perms {
+		if !p.Can(requesterID, perm) {
+			return fmt.Errorf("role carries %s, which you don't hold: %w", perm, domain.ErrForbidden)
+		}
+	}
+	return nil
+}
\ No newline at end of file
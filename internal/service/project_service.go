@@ -2,19 +2,29 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/0DayMonxrch/project-management-system/internal/domain"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 type projectService struct {
-	projectRepo domain.ProjectRepository
-	userRepo    domain.UserRepository
+	projectRepo    domain.ProjectRepository
+	userRepo       domain.UserRepository
+	taskRepo       domain.TaskRepository
+	invitationRepo domain.InvitationRepository
+	joinLinkRepo   domain.JoinLinkRepository
+	auditRepo      domain.AuditRepository
+	events         domain.EventBus
 }
 
-func NewProjectService(projectRepo domain.ProjectRepository, userRepo domain.UserRepository) domain.ProjectService {
-	return &projectService{projectRepo: projectRepo, userRepo: userRepo}
+func NewProjectService(projectRepo domain.ProjectRepository, userRepo domain.UserRepository, taskRepo domain.TaskRepository, invitationRepo domain.InvitationRepository, joinLinkRepo domain.JoinLinkRepository, auditRepo domain.AuditRepository, events domain.EventBus) domain.ProjectService {
+	return &projectService{projectRepo: projectRepo, userRepo: userRepo, taskRepo: taskRepo, invitationRepo: invitationRepo, joinLinkRepo: joinLinkRepo, auditRepo: auditRepo, events: events}
 }
 
 func (s *projectService) CreateProject(ctx context.Context, userID, name, description string) (*domain.Project, error) {
@@ -30,127 +40,441 @@ func (s *projectService) CreateProject(ctx context.Context, userID, name, descri
 		Members: []domain.ProjectMember{
 			{UserID: oid, Role: domain.RoleAdmin},
 		},
+		Workflow: domain.DefaultWorkflow(),
+		Roles:    []domain.ProjectRole{},
 	}
 
 	if err := s.projectRepo.Create(ctx, project); err != nil {
 		return nil, err
 	}
+	changes := []domain.FieldChange{{Field: "name", To: name}, {Field: "description", To: description}}
+	if err := recordAudit(ctx, s.auditRepo, project.ID, userID, domain.AuditProjectCreated, "project", project.ID.Hex(), changes); err != nil {
+		return nil, err
+	}
 	return project, nil
 }
 
 func (s *projectService) GetProject(ctx context.Context, projectID, userID string) (*domain.Project, error) {
-	project, err := s.projectRepo.FindByID(ctx, projectID)
+	return authorizeProject(ctx, s.projectRepo, projectID, userID, domain.PermProjectView)
+}
+
+func (s *projectService) ListProjects(ctx context.Context, userID string, page domain.PageRequest) (*domain.Page[domain.Project], error) {
+	page, err := normalizePage(page, "created_at", "updated_at", "name")
 	if err != nil {
 		return nil, err
 	}
-	if !isMember(project, userID) {
-		return nil, domain.ErrForbidden
-	}
-	return project, nil
-}
-
-func (s *projectService) ListProjects(ctx context.Context, userID string) ([]domain.Project, error) {
-	return s.projectRepo.FindByUserID(ctx, userID)
+	return s.projectRepo.FindByUserID(ctx, userID, page)
 }
 
 func (s *projectService) UpdateProject(ctx context.Context, projectID, userID, name, description string) (*domain.Project, error) {
-	project, err := s.projectRepo.FindByID(ctx, projectID)
+	project, err := authorizeProject(ctx, s.projectRepo, projectID, userID, domain.PermProjectUpdate)
 	if err != nil {
 		return nil, err
 	}
-	if !hasRole(project, userID, domain.RoleAdmin) {
-		return nil, domain.ErrForbidden
-	}
+
+	var changes []domain.FieldChange
+	changes = appendChange(changes, "name", project.Name, name)
+	changes = appendChange(changes, "description", project.Description, description)
 
 	project.Name = name
 	project.Description = description
 	if err := s.projectRepo.Update(ctx, project); err != nil {
 		return nil, err
 	}
+	if err := recordAudit(ctx, s.auditRepo, project.ID, userID, domain.AuditProjectUpdated, "project", projectID, changes); err != nil {
+		return nil, err
+	}
 	return project, nil
 }
 
 func (s *projectService) DeleteProject(ctx context.Context, projectID, userID string) error {
-	project, err := s.projectRepo.FindByID(ctx, projectID)
+	project, err := authorizeProject(ctx, s.projectRepo, projectID, userID, domain.PermProjectDelete)
 	if err != nil {
 		return err
 	}
-	if !hasRole(project, userID, domain.RoleAdmin) {
-		return domain.ErrForbidden
+	if err := s.projectRepo.Delete(ctx, projectID); err != nil {
+		return err
 	}
-	return s.projectRepo.Delete(ctx, projectID)
+	changes := []domain.FieldChange{{Field: "name", From: project.Name}}
+	return recordAudit(ctx, s.auditRepo, project.ID, userID, domain.AuditProjectDeleted, "project", projectID, changes)
 }
 
 func (s *projectService) AddMember(ctx context.Context, projectID, requesterID, email string, role domain.Role) error {
-	project, err := s.projectRepo.FindByID(ctx, projectID)
+	project, err := authorizeProject(ctx, s.projectRepo, projectID, requesterID, domain.PermMemberManage)
 	if err != nil {
 		return err
 	}
-	if !hasRole(project, requesterID, domain.RoleAdmin) {
-		return domain.ErrForbidden
+	if err := checkGrant(project, requesterID, role); err != nil {
+		return err
 	}
 
 	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
-		return fmt.Errorf("user with email not found: %w", domain.ErrNotFound)
+		return fmt.Errorf("user with email not found, send an invitation instead: %w", domain.ErrNotFound)
 	}
+	return addMember(ctx, s.projectRepo, s.auditRepo, s.events, project, user.ID, role, requesterID)
+}
 
-	for _, m := range project.Members {
-		if m.UserID == user.ID {
-			return domain.ErrConflict
-		}
+func (s *projectService) ListMembers(ctx context.Context, projectID, requesterID string) ([]domain.ProjectMember, error) {
+	project, err := authorizeProject(ctx, s.projectRepo, projectID, requesterID, domain.PermProjectView)
+	if err != nil {
+		return nil, err
+	}
+	return project.Members, nil
+}
+
+func (s *projectService) UpdateMemberRole(ctx context.Context, projectID, requesterID, targetUserID string, role domain.Role) error {
+	project, err := authorizeProject(ctx, s.projectRepo, projectID, requesterID, domain.PermMemberManage)
+	if err != nil {
+		return err
 	}
 
-	project.Members = append(project.Members, domain.ProjectMember{UserID: user.ID, Role: role})
-	return s.projectRepo.Update(ctx, project)
+	i := memberIndex(project, targetUserID)
+	if i < 0 {
+		return domain.ErrNotFound
+	}
+	if err := checkOutranks(project, requesterID, project.Members[i]); err != nil {
+		return err
+	}
+	if err := checkGrant(project, requesterID, role); err != nil {
+		return err
+	}
+	if role != domain.RoleAdmin && isSoleAdmin(project, targetUserID) {
+		return errLastAdmin
+	}
+	return s.changeRole(ctx, project, i, role, requesterID)
 }
 
-func (s *projectService) ListMembers(ctx context.Context, projectID string) ([]domain.ProjectMember, error) {
-	project, err := s.projectRepo.FindByID(ctx, projectID)
+func (s *projectService) RemoveMember(ctx context.Context, projectID, requesterID, targetUserID string) error {
+	project, err := authorizeProject(ctx, s.projectRepo, projectID, requesterID, domain.PermMemberManage)
+	if err != nil {
+		return err
+	}
+
+	i := memberIndex(project, targetUserID)
+	if i < 0 {
+		return domain.ErrNotFound
+	}
+	if err := checkOutranks(project, requesterID, project.Members[i]); err != nil {
+		return err
+	}
+	if isSoleAdmin(project, targetUserID) {
+		return errLastAdmin
+	}
+
+	m := project.Members[i]
+	if err := s.projectRepo.RemoveMember(ctx, project.ID, m.UserID); err != nil {
+		return memberWriteError(err)
+	}
+	project.Members = slices.Delete(project.Members, i, i+1)
+	changes := []domain.FieldChange{{Field: "role", From: string(m.Role)}}
+	if err := recordAudit(ctx, s.auditRepo, project.ID, requesterID, domain.AuditMemberRemoved, "user", targetUserID, changes); err != nil {
+		return err
+	}
+	publishEvent(ctx, s.events, project.ID, requesterID, domain.EventMemberRemoved, m.UserID, changes, nil)
+	return nil
+}
+
+func (s *projectService) TransferOwnership(ctx context.Context, projectID, requesterID, newOwnerID string) (*domain.Project, error) {
+	project, err := authorizeProject(ctx, s.projectRepo, projectID, requesterID, domain.PermMemberManage)
 	if err != nil {
 		return nil, err
 	}
-	return project.Members, nil
+	// The new owner is made admin, so only those who could grant that may transfer
+	if err := checkGrant(project, requesterID, domain.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	i := memberIndex(project, newOwnerID)
+	if i < 0 {
+		return nil, fmt.Errorf("new owner must be a member of the project: %w", domain.ErrInvalidInput)
+	}
+	if project.CreatedBy.Hex() == newOwnerID {
+		return project, nil
+	}
+
+	if err := s.setOwner(ctx, project, i, requesterID); err != nil {
+		return nil, err
+	}
+	return project, nil
 }
 
-func (s *projectService) UpdateMemberRole(ctx context.Context, projectID, requesterID, targetUserID string, role domain.Role) error {
+func (s *projectService) ListOrphanedProjects(ctx context.Context, requesterID string, page domain.PageRequest) (*domain.Page[domain.Project], error) {
+	if err := s.requireGlobalAdmin(ctx, requesterID); err != nil {
+		return nil, err
+	}
+	page, err := normalizePage(page, "created_at", "updated_at", "name")
+	if err != nil {
+		return nil, err
+	}
+	return s.projectRepo.FindWithoutAdmin(ctx, page)
+}
+
+func (s *projectService) RecoverProject(ctx context.Context, projectID, requesterID, newAdminID string) (*domain.Project, error) {
+	if err := s.requireGlobalAdmin(ctx, requesterID); err != nil {
+		return nil, err
+	}
 	project, err := s.projectRepo.FindByID(ctx, projectID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if !hasRole(project, requesterID, domain.RoleAdmin) {
-		return domain.ErrForbidden
+	if slices.ContainsFunc(project.Members, func(m domain.ProjectMember) bool { return m.Role == domain.RoleAdmin }) {
+		return nil, fmt.Errorf("project still has an admin: %w", domain.ErrConflict)
+	}
+	user, err := s.userRepo.FindByID(ctx, newAdminID)
+	if err != nil {
+		return nil, err
 	}
 
-	for i, m := range project.Members {
-		if m.UserID.Hex() == targetUserID {
-			project.Members[i].Role = role
-			return s.projectRepo.Update(ctx, project)
+	if !isMember(project, newAdminID) {
+		if err := addMember(ctx, s.projectRepo, s.auditRepo, s.events, project, user.ID, domain.RoleAdmin, requesterID); err != nil {
+			return nil, err
 		}
 	}
-	return domain.ErrNotFound
+	if err := s.setOwner(ctx, project, memberIndex(project, newAdminID), requesterID); err != nil {
+		return nil, err
+	}
+	changes := []domain.FieldChange{{Field: "admin", To: newAdminID}}
+	if err := recordAudit(ctx, s.auditRepo, project.ID, requesterID, domain.AuditProjectRecovered, "project", projectID, changes); err != nil {
+		return nil, err
+	}
+	return project, nil
 }
 
-func (s *projectService) RemoveMember(ctx context.Context, projectID, requesterID, targetUserID string) error {
-	project, err := s.projectRepo.FindByID(ctx, projectID)
+func (s *projectService) GetWorkflow(ctx context.Context, projectID, userID string) (*domain.Workflow, error) {
+	project, err := authorizeProject(ctx, s.projectRepo, projectID, userID, domain.PermProjectView)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if !hasRole(project, requesterID, domain.RoleAdmin) {
-		return domain.ErrForbidden
+	return &project.Workflow, nil
+}
+
+func (s *projectService) UpdateWorkflow(ctx context.Context, projectID, userID string, workflow domain.Workflow) (*domain.Workflow, error) {
+	project, err := authorizeProject(ctx, s.projectRepo, projectID, userID, domain.PermProjectUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateWorkflow(workflow); err != nil {
+		return nil, err
+	}
+
+	// Refuse to drop a status that tasks are still sitting in
+	var removed []domain.TaskStatus
+	for _, st := range project.Workflow.Statuses {
+		if !slices.Contains(workflow.Statuses, st) {
+			removed = append(removed, st)
+		}
+	}
+	if len(removed) > 0 {
+		count, err := s.taskRepo.CountByStatus(ctx, projectID, removed)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("%d task(s) still use a removed status, move them first: %w", count, domain.ErrConflict)
+		}
 	}
 
-	for i, m := range project.Members {
-		if m.UserID.Hex() == targetUserID {
-			project.Members = append(project.Members[:i], project.Members[i+1:]...)
-			return s.projectRepo.Update(ctx, project)
+	changes := appendChange(nil, "workflow", formatWorkflow(project.Workflow), formatWorkflow(workflow))
+
+	project.Workflow = workflow
+	if err := s.projectRepo.Update(ctx, project); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.auditRepo, project.ID, userID, domain.AuditWorkflowUpdated, "project", projectID, changes); err != nil {
+		return nil, err
+	}
+	return &project.Workflow, nil
+}
+
+func (s *projectService) ListRoles(ctx context.Context, projectID, requesterID string) ([]domain.ProjectRole, error) {
+	project, err := authorizeProject(ctx, s.projectRepo, projectID, requesterID, domain.PermProjectView)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Concat(domain.BuiltinRoles, project.Roles), nil
+}
+
+func (s *projectService) SaveRole(ctx context.Context, projectID, requesterID string, name domain.Role, permissions []domain.Permission) (*domain.ProjectRole, error) {
+	project, err := authorizeProject(ctx, s.projectRepo, projectID, requesterID, domain.PermRoleManage)
+	if err != nil {
+		return nil, err
+	}
+	if !roleNamePattern.MatchString(string(name)) {
+		return nil, fmt.Errorf("role name must be lower-case letters, digits and underscores: %w", domain.ErrInvalidInput)
+	}
+	for _, perm := range permissions {
+		if !slices.Contains(domain.Permissions, perm) {
+			return nil, fmt.Errorf("unknown permission %q: %w", perm, domain.ErrInvalidInput)
 		}
 	}
-	return domain.ErrNotFound
+	permissions = append([]domain.Permission{}, slices.Compact(slices.Sorted(slices.Values(permissions)))...)
+	if err := checkPermissions(project, requesterID, permissions); err != nil {
+		return nil, err
+	}
+
+	existing, ok := project.FindRole(name)
+	if ok && existing.BuiltIn {
+		return nil, fmt.Errorf("built-in role %q can't be changed: %w", name, domain.ErrConflict)
+	}
+	action, from := domain.AuditRoleCreated, ""
+	if ok {
+		// Editing a role changes what its holders can do, so the same ceiling applies
+		if err := checkPermissions(project, requesterID, existing.Permissions); err != nil {
+			return nil, err
+		}
+		action, from = domain.AuditRoleUpdated, formatPermissions(existing.Permissions)
+		existing.Permissions = permissions
+	} else {
+		project.Roles = append(project.Roles, domain.ProjectRole{Name: name, Permissions: permissions})
+	}
+
+	if err := s.projectRepo.Update(ctx, project); err != nil {
+		return nil, err
+	}
+	changes := appendChange(nil, "permissions", from, formatPermissions(permissions))
+	if err := recordAudit(ctx, s.auditRepo, project.ID, requesterID, action, "role", string(name), changes); err != nil {
+		return nil, err
+	}
+	role, _ := project.FindRole(name)
+	return role, nil
+}
+
+func (s *projectService) DeleteRole(ctx context.Context, projectID, requesterID string, name domain.Role) error {
+	project, err := authorizeProject(ctx, s.projectRepo, projectID, requesterID, domain.PermRoleManage)
+	if err != nil {
+		return err
+	}
+	role, ok := project.FindRole(name)
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if role.BuiltIn {
+		return fmt.Errorf("built-in role %q can't be deleted: %w", name, domain.ErrConflict)
+	}
+	if err := checkPermissions(project, requesterID, role.Permissions); err != nil {
+		return err
+	}
+	if slices.ContainsFunc(project.Members, func(m domain.ProjectMember) bool { return m.Role == name }) {
+		return fmt.Errorf("role %q is still held by members, change their role first: %w", name, domain.ErrConflict)
+	}
+	// Otherwise they would go on handing out a role that no longer exists
+	now := time.Now()
+	invitations, err := s.invitationRepo.FindPendingByProjectID(ctx, projectID, now)
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(invitations, func(inv domain.Invitation) bool { return inv.Role == name }) {
+		return fmt.Errorf("role %q is offered by pending invitations, revoke them first: %w", name, domain.ErrConflict)
+	}
+	links, err := s.joinLinkRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(links, func(l domain.JoinLink) bool { return l.Role == name && l.Usable(now) }) {
+		return fmt.Errorf("role %q is offered by active join links, revoke them first: %w", name, domain.ErrConflict)
+	}
+
+	changes := []domain.FieldChange{{Field: "permissions", From: formatPermissions(role.Permissions)}}
+	project.Roles = slices.DeleteFunc(project.Roles, func(r domain.ProjectRole) bool { return r.Name == name })
+	if err := s.projectRepo.Update(ctx, project); err != nil {
+		return err
+	}
+	return recordAudit(ctx, s.auditRepo, project.ID, requesterID, domain.AuditRoleDeleted, "role", string(name), changes)
 }
 
 // --- helpers ---
 
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,31}$`)
+
+func formatPermissions(perms []domain.Permission) string {
+	names := make([]string, len(perms))
+	for i, perm := range perms {
+		names[i] = string(perm)
+	}
+	return strings.Join(names, ",")
+}
+
+var errLastAdmin = fmt.Errorf("a project needs at least one admin, make another member admin first: %w", domain.ErrConflict)
+
+// memberWriteError explains a member write refused because another request
+// took away the other admins after the project was loaded.
+func memberWriteError(err error) error {
+	if errors.Is(err, domain.ErrConflict) {
+		return errLastAdmin
+	}
+	return err
+}
+
+// changeRole sets the role of the i-th member.
+func (s *projectService) changeRole(ctx context.Context, project *domain.Project, i int, role domain.Role, actorID string) error {
+	m := project.Members[i]
+	if err := s.projectRepo.UpdateMemberRole(ctx, project.ID, m.UserID, role); err != nil {
+		return memberWriteError(err)
+	}
+	project.Members[i].Role = role
+	changes := appendChange(nil, "role", string(m.Role), string(role))
+	if err := recordAudit(ctx, s.auditRepo, project.ID, actorID, domain.AuditMemberRoleChanged, "user", m.UserID.Hex(), changes); err != nil {
+		return err
+	}
+	publishEvent(ctx, s.events, project.ID, actorID, domain.EventMemberRoleChanged, m.UserID, changes, project.Members[i])
+	return nil
+}
+
+// setOwner makes the i-th member the project's owner, promoting them to
+// admin if need be. The previous owner keeps their role. Only the owner is
+// written, and only while they are still an admin, so a demotion or removal
+// that lands in between isn't undone.
+func (s *projectService) setOwner(ctx context.Context, project *domain.Project, i int, actorID string) error {
+	if project.Members[i].Role != domain.RoleAdmin {
+		if err := s.changeRole(ctx, project, i, domain.RoleAdmin, actorID); err != nil {
+			return err
+		}
+	}
+	previous := project.CreatedBy
+	if err := s.projectRepo.SetOwner(ctx, project.ID, project.Members[i].UserID); err != nil {
+		return err
+	}
+	project.CreatedBy = project.Members[i].UserID
+
+	changes := appendChange(nil, "owner", previous.Hex(), project.CreatedBy.Hex())
+	return recordAudit(ctx, s.auditRepo, project.ID, actorID, domain.AuditOwnershipTransferred, "project", project.ID.Hex(), changes)
+}
+
+func (s *projectService) requireGlobalAdmin(ctx context.Context, userID string) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Role != domain.RoleAdmin {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+// addMember adds userID to the project with role on actorID's behalf. Every
+// way of joining a project goes through here, so all are audited and
+// announced alike.
+func addMember(ctx context.Context, projectRepo domain.ProjectRepository, auditRepo domain.AuditRepository, events domain.EventBus, project *domain.Project, userID bson.ObjectID, role domain.Role, actorID string) error {
+	if isMember(project, userID.Hex()) {
+		return fmt.Errorf("user is already a member: %w", domain.ErrConflict)
+	}
+
+	// The write itself checks membership, so two joins racing for the same
+	// user can't both add them
+	member := domain.ProjectMember{UserID: userID, Role: role}
+	if err := projectRepo.AddMember(ctx, project.ID, member); err != nil {
+		return err
+	}
+	project.Members = append(project.Members, member)
+	changes := []domain.FieldChange{{Field: "role", To: string(role)}}
+	if err := recordAudit(ctx, auditRepo, project.ID, actorID, domain.AuditMemberAdded, "user", userID.Hex(), changes); err != nil {
+		return err
+	}
+	publishEvent(ctx, events, project.ID, actorID, domain.EventMemberAdded, userID, changes, member)
+	return nil
+}
+
 func isMember(p *domain.Project, userID string) bool {
 	for _, m := range p.Members {
 		if m.UserID.Hex() == userID {
@@ -167,4 +491,19 @@ func hasRole(p *domain.Project, userID string, role domain.Role) bool {
 		}
 	}
 	return false
+}
+
+func memberIndex(p *domain.Project, userID string) int {
+	return slices.IndexFunc(p.Members, func(m domain.ProjectMember) bool { return m.UserID.Hex() == userID })
+}
+
+// isSoleAdmin reports whether userID is the project's only admin.
+func isSoleAdmin(p *domain.Project, userID string) bool {
+	admins := 0
+	for _, m := range p.Members {
+		if m.Role == domain.RoleAdmin {
+			admins++
+		}
+	}
+	return admins == 1 && hasRole(p, userID, domain.RoleAdmin)
 }
\ No newline at end of file
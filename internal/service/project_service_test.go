@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// memProjectRepo applies each write atomically under its lock, the way the
+// single-document updates of the Mongo repository do.
+type memProjectRepo struct {
+	domain.ProjectRepository
+	mu       sync.Mutex
+	projects map[bson.ObjectID]*domain.Project
+}
+
+func newMemProjectRepo(projects ...*domain.Project) *memProjectRepo {
+	r := &memProjectRepo{projects: map[bson.ObjectID]*domain.Project{}}
+	for _, p := range projects {
+		r.projects[p.ID] = p
+	}
+	return r
+}
+
+func (r *memProjectRepo) FindByID(ctx context.Context, id string) (*domain.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	oid, _ := bson.ObjectIDFromHex(id)
+	p, ok := r.projects[oid]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *p
+	copied.Members = slices.Clone(p.Members)
+	copied.Roles = slices.Clone(p.Roles)
+	return &copied, nil
+}
+
+func (r *memProjectRepo) Update(ctx context.Context, project *domain.Project) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *project
+	// Like the repository, a save leaves the members and owner as stored
+	if stored, ok := r.projects[project.ID]; ok {
+		copied.Members, copied.CreatedBy = stored.Members, stored.CreatedBy
+	}
+	r.projects[project.ID] = &copied
+	return nil
+}
+
+func (r *memProjectRepo) AddMember(ctx context.Context, projectID bson.ObjectID, member domain.ProjectMember) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.projects[projectID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if isMember(p, member.UserID.Hex()) {
+		return fmt.Errorf("user is already a member: %w", domain.ErrConflict)
+	}
+	p.Members = append(p.Members, member)
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *memProjectRepo) UpdateMemberRole(ctx context.Context, projectID, userID bson.ObjectID, role domain.Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, i, err := r.member(projectID, userID, role != domain.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	p.Members[i].Role = role
+	return nil
+}
+
+func (r *memProjectRepo) RemoveMember(ctx context.Context, projectID, userID bson.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, i, err := r.member(projectID, userID, true)
+	if err != nil {
+		return err
+	}
+	p.Members = slices.Delete(p.Members, i, i+1)
+	return nil
+}
+
+func (r *memProjectRepo) SetOwner(ctx context.Context, projectID, userID bson.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.projects[projectID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if !hasRole(p, userID.Hex(), domain.RoleAdmin) {
+		return fmt.Errorf("new owner is no longer an admin of the project: %w", domain.ErrConflict)
+	}
+	p.CreatedBy = userID
+	return nil
+}
+
+func (r *memProjectRepo) AnonymizeUser(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.projects {
+		if p.CreatedBy.Hex() == userID {
+			p.CreatedBy = bson.ObjectID{}
+		}
+	}
+	return nil
+}
+
+// member finds userID in the project, refusing with ErrConflict if
+// keepAdmin is set and they are its only admin.
+func (r *memProjectRepo) member(projectID, userID bson.ObjectID, keepAdmin bool) (*domain.Project, int, error) {
+	p, ok := r.projects[projectID]
+	if !ok {
+		return nil, 0, domain.ErrNotFound
+	}
+	i := memberIndex(p, userID.Hex())
+	if i < 0 {
+		return nil, 0, domain.ErrNotFound
+	}
+	if keepAdmin && isSoleAdmin(p, userID.Hex()) {
+		return nil, 0, fmt.Errorf("project would be left without an admin: %w", domain.ErrConflict)
+	}
+	return p, i, nil
+}
+
+func (r *memProjectRepo) members(projectID bson.ObjectID) []domain.ProjectMember {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.projects[projectID].Members)
+}
+
+type nopEventBus struct {
+	domain.EventBus
+}
+
+func (nopEventBus) Publish(ctx context.Context, event domain.ProjectEvent) {}
+
+// staleProjectRepo hands out the project as it was when the test started,
+// like a request that loaded it just before another one changed it.
+type staleProjectRepo struct {
+	*memProjectRepo
+	snapshot domain.Project
+}
+
+func (r *staleProjectRepo) FindByID(ctx context.Context, id string) (*domain.Project, error) {
+	copied := r.snapshot
+	copied.Members = slices.Clone(r.snapshot.Members)
+	return &copied, nil
+}
+
+func (r *staleProjectRepo) FindByUserID(ctx context.Context, userID string, page domain.PageRequest) (*domain.Page[domain.Project], error) {
+	return &domain.Page[domain.Project]{Items: []domain.Project{r.snapshot}}, nil
+}
+
+func newTwoAdminProject() (*staleProjectRepo, *domain.Project, bson.ObjectID, bson.ObjectID) {
+	a, b := bson.NewObjectID(), bson.NewObjectID()
+	project := &domain.Project{ID: bson.NewObjectID(), CreatedBy: a, Members: []domain.ProjectMember{
+		{UserID: a, Role: domain.RoleAdmin},
+		{UserID: b, Role: domain.RoleAdmin},
+	}}
+	repo := &staleProjectRepo{memProjectRepo: newMemProjectRepo(project), snapshot: *project}
+	repo.snapshot.Members = slices.Clone(project.Members)
+	return repo, project, a, b
+}
+
+func TestAdminsCannotDemoteEachOtherAtOnce(t *testing.T) {
+	repo, project, a, b := newTwoAdminProject()
+	svc := NewProjectService(repo, nil, nil, nil, nil, nopAuditRepo{}, nopEventBus{})
+	ctx := context.Background()
+
+	if err := svc.UpdateMemberRole(ctx, project.ID.Hex(), a.Hex(), b.Hex(), domain.RoleMember); err != nil {
+		t.Fatalf("first demotion: %v", err)
+	}
+	// b still looks like an admin to a request that loaded the project earlier
+	err := svc.UpdateMemberRole(ctx, project.ID.Hex(), b.Hex(), a.Hex(), domain.RoleMember)
+	if !errors.Is(err, errLastAdmin) {
+		t.Fatalf("second demotion error = %v, want errLastAdmin", err)
+	}
+	if !isSoleAdmin(&domain.Project{Members: repo.members(project.ID)}, a.Hex()) {
+		t.Fatalf("members = %+v, want a as the only admin", repo.members(project.ID))
+	}
+}
+
+func TestProjectSaveKeepsMembers(t *testing.T) {
+	repo, project, a, b := newTwoAdminProject()
+	svc := NewProjectService(repo, nil, nil, nil, nil, nopAuditRepo{}, nopEventBus{})
+	ctx := context.Background()
+
+	// Other requests change the members after this one loaded the project
+	c := bson.NewObjectID()
+	if err := repo.AddMember(ctx, project.ID, domain.ProjectMember{UserID: c, Role: domain.RoleMember}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.RemoveMember(ctx, project.ID, b); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.UpdateProject(ctx, project.ID.Hex(), a.Hex(), "Renamed", ""); err != nil {
+		t.Fatal(err)
+	}
+	saved := &domain.Project{Members: repo.members(project.ID)}
+	if !isMember(saved, c.Hex()) || isMember(saved, b.Hex()) {
+		t.Fatalf("members = %+v, want c kept and b still removed", saved.Members)
+	}
+}
+
+func TestTransferOwnershipDoesNotUndoADemotion(t *testing.T) {
+	repo, project, a, b := newTwoAdminProject()
+	svc := NewProjectService(repo, nil, nil, nil, nil, nopAuditRepo{}, nopEventBus{})
+	ctx := context.Background()
+
+	// b is demoted after the transfer loaded the project
+	if err := repo.UpdateMemberRole(ctx, project.ID, b, domain.RoleMember); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := svc.TransferOwnership(ctx, project.ID.Hex(), a.Hex(), b.Hex())
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("transfer error = %v, want ErrConflict", err)
+	}
+	stored := repo.projects[project.ID]
+	if stored.CreatedBy != a || hasRole(stored, b.Hex(), domain.RoleAdmin) {
+		t.Fatalf("owner %s, members %+v, want a still owner and b still demoted", stored.CreatedBy.Hex(), stored.Members)
+	}
+}
+
+func TestAdminsCannotRemoveEachOtherAtOnce(t *testing.T) {
+	repo, project, a, b := newTwoAdminProject()
+	svc := NewProjectService(repo, nil, nil, nil, nil, nopAuditRepo{}, nopEventBus{})
+	ctx := context.Background()
+
+	if err := svc.RemoveMember(ctx, project.ID.Hex(), a.Hex(), b.Hex()); err != nil {
+		t.Fatalf("first removal: %v", err)
+	}
+	err := svc.RemoveMember(ctx, project.ID.Hex(), b.Hex(), a.Hex())
+	if !errors.Is(err, errLastAdmin) {
+		t.Fatalf("second removal error = %v, want errLastAdmin", err)
+	}
+	if members := repo.members(project.ID); len(members) != 1 || members[0].UserID != a {
+		t.Fatalf("members = %+v, want only a", members)
+	}
+}
+
+type pendingInvitationRepo struct {
+	domain.InvitationRepository
+	invitations []domain.Invitation
+}
+
+func (r pendingInvitationRepo) FindPendingByProjectID(ctx context.Context, projectID string, now time.Time) ([]domain.Invitation, error) {
+	return r.invitations, nil
+}
+
+type projectLinkRepo struct {
+	domain.JoinLinkRepository
+	links []domain.JoinLink
+}
+
+func (r projectLinkRepo) FindByProjectID(ctx context.Context, projectID string) ([]domain.JoinLink, error) {
+	return r.links, nil
+}
+
+func TestDeleteRoleRefusesWhileOffered(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name        string
+		invitations []domain.Invitation
+		links       []domain.JoinLink
+		wantErr     error
+	}{
+		{name: "unused", wantErr: nil},
+		{name: "pending invitation", invitations: []domain.Invitation{{Role: "reviewer"}}, wantErr: domain.ErrConflict},
+		{name: "invitation for another role", invitations: []domain.Invitation{{Role: domain.RoleMember}}, wantErr: nil},
+		{name: "active join link", links: []domain.JoinLink{{Role: "reviewer"}}, wantErr: domain.ErrConflict},
+		{name: "revoked join link", links: []domain.JoinLink{{Role: "reviewer", RevokedAt: &past}}, wantErr: nil},
+		{name: "expired join link", links: []domain.JoinLink{{Role: "reviewer", ExpiresAt: &past}}, wantErr: nil},
+		{name: "used up join link", links: []domain.JoinLink{{Role: "reviewer", MaxUses: 1, Uses: 1}}, wantErr: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admin := bson.NewObjectID()
+			project := &domain.Project{
+				ID:      bson.NewObjectID(),
+				Roles:   []domain.ProjectRole{{Name: "reviewer", Permissions: []domain.Permission{domain.PermProjectView}}},
+				Members: []domain.ProjectMember{{UserID: admin, Role: domain.RoleAdmin}},
+			}
+			projects := newMemProjectRepo(project)
+			svc := NewProjectService(projects, nil, nil, pendingInvitationRepo{invitations: tt.invitations}, projectLinkRepo{links: tt.links}, nopAuditRepo{}, nopEventBus{})
+
+			err := svc.DeleteRole(context.Background(), project.ID.Hex(), admin.Hex(), "reviewer")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DeleteRole error = %v, want %v", err, tt.wantErr)
+			}
+			stored, _ := projects.FindByID(context.Background(), project.ID.Hex())
+			if _, kept := stored.FindRole("reviewer"); kept != (tt.wantErr != nil) {
+				t.Fatalf("role kept = %v", kept)
+			}
+		})
+	}
+}
\ No newline at end of file
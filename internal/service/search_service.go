@@ -0,0 +1,266 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	maxQueryLength     = 256
+
+	// snippetRadius is roughly how many bytes of context a snippet keeps on
+	// either side of the first match.
+	snippetRadius = 80
+)
+
+type searchService struct {
+	projectRepo domain.ProjectRepository
+	taskRepo    domain.TaskRepository
+	noteRepo    domain.NoteRepository
+}
+
+func NewSearchService(projectRepo domain.ProjectRepository, taskRepo domain.TaskRepository, noteRepo domain.NoteRepository) domain.SearchService {
+	return &searchService{projectRepo: projectRepo, taskRepo: taskRepo, noteRepo: noteRepo}
+}
+
+func (s *searchService) Search(ctx context.Context, userID, query string, limit int) ([]domain.SearchHit, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("q is required: %w", domain.ErrInvalidInput)
+	}
+	if len(query) > maxQueryLength {
+		return nil, fmt.Errorf("q must be at most %d characters: %w", maxQueryLength, domain.ErrInvalidInput)
+	}
+	limit = clampPageSize(limit, defaultSearchLimit, maxSearchLimit)
+
+	taskProjectIDs, noteProjectIDs, err := s.searchableProjectIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.taskRepo.Search(ctx, taskProjectIDs, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.noteRepo.Search(ctx, noteProjectIDs, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := searchTerms(query)
+	hits := []domain.SearchHit{}
+	for _, m := range tasks {
+		hits = append(hits, taskHits(m, terms)...)
+	}
+	for _, m := range notes {
+		snippet, _ := bestSnippet(terms, m.Item.Content, m.Item.Title)
+		hits = append(hits, domain.SearchHit{
+			Type:      domain.SearchHitNote,
+			ID:        m.Item.ID,
+			ProjectID: m.Item.ProjectID,
+			Title:     m.Item.Title,
+			Snippet:   snippet,
+			Score:     m.Score,
+		})
+	}
+
+	// Tasks and notes are scored on the same scale, so they can be merged
+	slices.SortStableFunc(hits, func(a, b domain.SearchHit) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+// --- helpers ---
+
+// searchableProjectIDs walks every page of the user's projects, splitting
+// them by whether the user's role there lets them view tasks and notes.
+func (s *searchService) searchableProjectIDs(ctx context.Context, userID string) (tasks, notes []string, err error) {
+	page := domain.PageRequest{Limit: maxPageSize, Sort: defaultPageSort}
+	for {
+		projects, err := s.projectRepo.FindByUserID(ctx, userID, page)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, p := range projects.Items {
+			if p.Can(userID, domain.PermTaskView) {
+				tasks = append(tasks, p.ID.Hex())
+			}
+			if p.Can(userID, domain.PermNoteView) {
+				notes = append(notes, p.ID.Hex())
+			}
+		}
+		if projects.NextCursor == "" {
+			return tasks, notes, nil
+		}
+		page.Cursor = projects.NextCursor
+	}
+}
+
+// taskHits turns one matching task document into a hit for the task itself
+// and one per matching subtask. MongoDB only scores whole documents, so
+// every hit shares the task's score.
+func taskHits(m domain.TextMatch[domain.Task], terms []string) []domain.SearchHit {
+	task := m.Item
+	var hits []domain.SearchHit
+
+	for _, st := range task.SubTasks {
+		if snippet, ok := highlight(st.Title, terms); ok {
+			hits = append(hits, domain.SearchHit{
+				Type:      domain.SearchHitSubTask,
+				ID:        st.ID,
+				ProjectID: task.ProjectID,
+				TaskID:    task.ID,
+				Title:     st.Title,
+				Snippet:   snippet,
+				Score:     m.Score,
+			})
+		}
+	}
+
+	snippet, ok := bestSnippet(terms, task.Description, task.Title)
+	// Stemmed matches may not highlight; keep the task rather than drop it
+	if ok || len(hits) == 0 {
+		hits = append([]domain.SearchHit{{
+			Type:      domain.SearchHitTask,
+			ID:        task.ID,
+			ProjectID: task.ProjectID,
+			Title:     task.Title,
+			Snippet:   snippet,
+			Score:     m.Score,
+		}}, hits...)
+	}
+	return hits
+}
+
+// searchTerms extracts the lowercased words of a query, skipping negated
+// terms ("-word") since they never appear in results.
+func searchTerms(query string) []string {
+	var terms []string
+	for _, field := range strings.Fields(strings.ToLower(query)) {
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+		terms = append(terms, strings.FieldsFunc(field, isNotWordRune)...)
+	}
+	return terms
+}
+
+// highlight returns an HTML-escaped excerpt of text around the first word
+// matching one of terms, with matching words wrapped in <mark>. If nothing
+// matches it returns the start of text and false.
+func highlight(text string, terms []string) (string, bool) {
+	type span struct{ start, end int }
+	var marks []span
+	for start := 0; start < len(text); {
+		r, size := utf8.DecodeRuneInString(text[start:])
+		if isNotWordRune(r) {
+			start += size
+			continue
+		}
+		end := start + size
+		for end < len(text) {
+			r, size := utf8.DecodeRuneInString(text[end:])
+			if isNotWordRune(r) {
+				break
+			}
+			end += size
+		}
+		word := strings.ToLower(text[start:end])
+		if slices.ContainsFunc(terms, func(t string) bool { return wordMatches(word, t) }) {
+			marks = append(marks, span{start, end})
+		}
+		start = end
+	}
+
+	from, to := 0, min(len(text), 2*snippetRadius)
+	if len(marks) > 0 {
+		from = max(0, marks[0].start-snippetRadius)
+		to = min(len(text), marks[0].end+snippetRadius)
+	}
+	from, to = wordBoundary(text, from, -1), wordBoundary(text, to, 1)
+	for from > 0 && from < to {
+		r, size := utf8.DecodeRuneInString(text[from:])
+		if !isNotWordRune(r) {
+			break
+		}
+		from += size
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, m := range marks {
+		if m.start < from || m.end > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:m.start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[m.start:m.end]))
+		b.WriteString("</mark>")
+		pos = m.end
+	}
+	b.WriteString(html.EscapeString(text[pos:to]))
+	if to < len(text) {
+		b.WriteString("…")
+	}
+	return b.String(), len(marks) > 0
+}
+
+// bestSnippet highlights the first of texts that contains a match, falling
+// back to the first non-empty one.
+func bestSnippet(terms []string, texts ...string) (string, bool) {
+	fallback := ""
+	for _, text := range texts {
+		snippet, ok := highlight(text, terms)
+		if ok {
+			return snippet, true
+		}
+		if fallback == "" {
+			fallback = snippet
+		}
+	}
+	return fallback, false
+}
+
+// wordMatches approximates MongoDB's stemming: a word matches a term when
+// one is a prefix of the other, or when they share a long enough prefix.
+func wordMatches(word, term string) bool {
+	n := 0
+	for n < len(word) && n < len(term) && word[n] == term[n] {
+		n++
+	}
+	return word == term || (n >= 3 && (n == len(term) || n == len(word))) || n >= 5
+}
+
+// wordBoundary moves i to the nearest rune boundary that does not split a
+// word, searching backwards (dir < 0) or forwards (dir > 0).
+func wordBoundary(text string, i, dir int) int {
+	for i > 0 && i < len(text) {
+		r, _ := utf8.DecodeRuneInString(text[i:])
+		if utf8.RuneStart(text[i]) && isNotWordRune(r) {
+			break
+		}
+		i += dir
+	}
+	return i
+}
+
+func isNotWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
\ No newline at end of file
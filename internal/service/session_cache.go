@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+)
+
+type cachedSession struct {
+	userID    string
+	live      bool
+	expiresAt time.Time
+}
+
+type sessionCache struct {
+	sessionRepo domain.SessionRepository
+	ttl         time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cachedSession
+	// generation moves on every invalidation, so a lookup that raced one
+	// doesn't cache what it read
+	generation uint64
+	swept      time.Time
+}
+
+// NewSessionCache returns a SessionCache that keeps lookups for ttl.
+// Sessions ended by this process are refused at once; other instances
+// notice once their entry expires.
+func NewSessionCache(sessionRepo domain.SessionRepository, ttl time.Duration) domain.SessionCache {
+	return &sessionCache{sessionRepo: sessionRepo, ttl: ttl, entries: make(map[string]cachedSession)}
+}
+
+func (c *sessionCache) Active(ctx context.Context, userID, sessionID string) (bool, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[sessionID]
+	generation := c.generation
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.live && entry.userID == userID, nil
+	}
+
+	session, err := c.sessionRepo.FindByID(ctx, sessionID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrInvalidInput):
+		entry = cachedSession{expiresAt: now.Add(c.ttl)}
+	case err != nil:
+		return false, err
+	default:
+		entry = cachedSession{userID: session.UserID.Hex(), live: now.Before(session.ExpiresAt), expiresAt: now.Add(c.ttl)}
+		if entry.live && session.ExpiresAt.Before(entry.expiresAt) {
+			entry.expiresAt = session.ExpiresAt
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation == generation {
+		c.sweep(now)
+		c.entries[sessionID] = entry
+	}
+	return entry.live && entry.userID == userID, nil
+}
+
+func (c *sessionCache) Invalidate(sessionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	delete(c.entries, sessionID)
+}
+
+func (c *sessionCache) InvalidateUser(userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	for id, entry := range c.entries {
+		if entry.userID == userID {
+			delete(c.entries, id)
+		}
+	}
+}
+
+// sweep drops expired entries at most once per ttl. The caller must hold c.mu.
+func (c *sessionCache) sweep(now time.Time) {
+	if now.Sub(c.swept) < c.ttl {
+		return
+	}
+	c.swept = now
+	for id, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, id)
+		}
+	}
+}
\ No newline at end of file
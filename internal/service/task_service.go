@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/0DayMonxrch/project-management-system/internal/domain"
@@ -9,32 +11,40 @@ import (
 )
 
 type taskService struct {
-	taskRepo    domain.TaskRepository
-	projectRepo domain.ProjectRepository
+	taskRepo     domain.TaskRepository
+	projectRepo  domain.ProjectRepository
+	activityRepo domain.ActivityRepository
+	commentRepo  domain.CommentRepository
+	storage      domain.BlobStorage
+	events       domain.EventBus
+	log          *slog.Logger
 }
 
-func NewTaskService(taskRepo domain.TaskRepository, projectRepo domain.ProjectRepository) domain.TaskService {
-	return &taskService{taskRepo: taskRepo, projectRepo: projectRepo}
+func NewTaskService(taskRepo domain.TaskRepository, projectRepo domain.ProjectRepository, activityRepo domain.ActivityRepository, commentRepo domain.CommentRepository, storage domain.BlobStorage, events domain.EventBus, log *slog.Logger) domain.TaskService {
+	return &taskService{taskRepo: taskRepo, projectRepo: projectRepo, activityRepo: activityRepo, commentRepo: commentRepo, storage: storage, events: events, log: log}
 }
 
 func (s *taskService) CreateTask(ctx context.Context, projectID, requesterID, title, description, assigneeID string) (*domain.Task, error) {
-	project, err := s.projectRepo.FindByID(ctx, projectID)
+	project, err := authorizeProject(ctx, s.projectRepo, projectID, requesterID, domain.PermTaskCreate)
 	if err != nil {
 		return nil, err
 	}
-	if !hasAdminOrProjectAdmin(project, requesterID) {
-		return nil, domain.ErrForbidden
-	}
 
 	projectOID, _ := bson.ObjectIDFromHex(projectID)
 	requesterOID, _ := bson.ObjectIDFromHex(requesterID)
 	assigneeOID, _ := bson.ObjectIDFromHex(assigneeID)
 
+	// New tasks start in the first status of the project's workflow
+	status := domain.StatusTodo
+	if len(project.Workflow.Statuses) > 0 {
+		status = project.Workflow.Statuses[0]
+	}
+
 	task := &domain.Task{
 		ProjectID:   projectOID,
 		Title:       title,
 		Description: description,
-		Status:      domain.StatusTodo,
+		Status:      status,
 		AssignedTo:  assigneeOID,
 		CreatedBy:   requesterOID,
 		Attachments: []domain.Attachment{},
@@ -44,46 +54,64 @@ func (s *taskService) CreateTask(ctx context.Context, projectID, requesterID, ti
 	if err := s.taskRepo.Create(ctx, task); err != nil {
 		return nil, err
 	}
+	s.record(ctx, task, requesterID, domain.ActivityTaskCreated, bson.ObjectID{}, diffTask(&domain.Task{}, task))
 	return task, nil
 }
 
-func (s *taskService) GetTask(ctx context.Context, projectID, taskID string) (*domain.Task, error) {
-	task, err := s.taskRepo.FindByID(ctx, taskID)
+func (s *taskService) GetTask(ctx context.Context, projectID, taskID, requesterID string) (*domain.Task, error) {
+	_, task, err := findProjectTask(ctx, s.projectRepo, s.taskRepo, projectID, taskID, requesterID, domain.PermTaskView)
+	return task, err
+}
+
+func (s *taskService) ListTasks(ctx context.Context, projectID, requesterID string, query domain.TaskQuery, page domain.PageRequest) (*domain.Page[domain.Task], error) {
+	project, err := authorizeProject(ctx, s.projectRepo, projectID, requesterID, domain.PermTaskView)
 	if err != nil {
 		return nil, err
 	}
-	if task.ProjectID.Hex() != projectID {
-		return nil, domain.ErrNotFound
+	page, err = normalizePage(page, "created_at", "updated_at", "title", "status")
+	if err != nil {
+		return nil, err
 	}
-	return task, nil
-}
 
-func (s *taskService) ListTasks(ctx context.Context, projectID string) ([]domain.Task, error) {
-	return s.taskRepo.FindByProjectID(ctx, projectID)
-}
+	filter := domain.TaskFilter{
+		Status:     query.Status,
+		AssignedTo: query.AssignedTo,
+		CreatedBy:  query.CreatedBy,
+		DueFrom:    query.DueFrom,
+		DueBefore:  query.DueBefore,
+	}
 
-func (s *taskService) UpdateTask(ctx context.Context, taskID, requesterID string, updates map[string]any) (*domain.Task, error) {
-	task, err := s.taskRepo.FindByID(ctx, taskID)
-	if err != nil {
-		return nil, err
+	now := time.Now()
+	switch query.Due {
+	case "":
+	case domain.DueOverdue:
+		filter.DueBefore = earliest(filter.DueBefore, now)
+		filter.ExcludeStatuses = project.Workflow.Closed
+	case domain.DueThisWeek:
+		start := startOfWeek(now)
+		filter.DueFrom = latest(filter.DueFrom, start)
+		filter.DueBefore = earliest(filter.DueBefore, start.AddDate(0, 0, 7))
+	default:
+		return nil, fmt.Errorf("due must be one of: overdue, this_week: %w", domain.ErrInvalidInput)
 	}
 
-	project, err := s.projectRepo.FindByID(ctx, task.ProjectID.Hex())
-	if err != nil {
-		return nil, err
+	if !filter.DueFrom.IsZero() && !filter.DueBefore.IsZero() && filter.DueBefore.Before(filter.DueFrom) {
+		return nil, fmt.Errorf("due_to must not be before due_from: %w", domain.ErrInvalidInput)
 	}
+	return s.taskRepo.FindByProjectID(ctx, projectID, filter, page)
+}
 
-	// Members can only update status
-	if !hasAdminOrProjectAdmin(project, requesterID) {
-		if status, ok := updates["status"].(string); ok && len(updates) == 1 {
-			task.Status = domain.TaskStatus(status)
-			if err := s.taskRepo.Update(ctx, task); err != nil {
-				return nil, err
-			}
-			return task, nil
-		}
-		return nil, domain.ErrForbidden
+func (s *taskService) UpdateTask(ctx context.Context, projectID, taskID, requesterID string, updates map[string]any) (*domain.Task, error) {
+	// Moving a task along the workflow is a narrower permission than editing it
+	perm := domain.PermTaskUpdateAny
+	if _, ok := updates["status"].(string); ok && len(updates) == 1 {
+		perm = domain.PermTaskUpdateStatus
 	}
+	project, task, err := findProjectTask(ctx, s.projectRepo, s.taskRepo, projectID, taskID, requesterID, perm)
+	if err != nil {
+		return nil, err
+	}
+	before := *task
 
 	if title, ok := updates["title"].(string); ok {
 		task.Title = title
@@ -92,46 +120,58 @@ func (s *taskService) UpdateTask(ctx context.Context, taskID, requesterID string
 		task.Description = desc
 	}
 	if status, ok := updates["status"].(string); ok {
+		if err := checkTransition(project.Workflow, task.Status, domain.TaskStatus(status)); err != nil {
+			return nil, err
+		}
 		task.Status = domain.TaskStatus(status)
 	}
 	if assignee, ok := updates["assigned_to"].(string); ok {
 		oid, _ := bson.ObjectIDFromHex(assignee)
 		task.AssignedTo = oid
 	}
+	if err := applyDates(updates, &task.StartDate, &task.DueDate); err != nil {
+		return nil, err
+	}
 
+	changes := diffTask(&before, task)
+	if len(changes) == 0 {
+		return task, nil
+	}
 	if err := s.taskRepo.Update(ctx, task); err != nil {
 		return nil, err
 	}
+	s.record(ctx, task, requesterID, domain.ActivityTaskUpdated, bson.ObjectID{}, changes)
 	return task, nil
 }
 
-func (s *taskService) DeleteTask(ctx context.Context, taskID, requesterID string) error {
-	task, err := s.taskRepo.FindByID(ctx, taskID)
+func (s *taskService) DeleteTask(ctx context.Context, projectID, taskID, requesterID string) error {
+	_, task, err := findProjectTask(ctx, s.projectRepo, s.taskRepo, projectID, taskID, requesterID, domain.PermTaskDelete)
 	if err != nil {
 		return err
 	}
-	project, err := s.projectRepo.FindByID(ctx, task.ProjectID.Hex())
-	if err != nil {
+	// Comments go first so a failure leaves the task in place to retry
+	if err := s.commentRepo.DeleteByTaskID(ctx, taskID); err != nil {
 		return err
 	}
-	if !hasAdminOrProjectAdmin(project, requesterID) {
-		return domain.ErrForbidden
+	if err := s.taskRepo.Delete(ctx, taskID); err != nil {
+		return err
 	}
-	return s.taskRepo.Delete(ctx, taskID)
-}
+	s.record(ctx, task, requesterID, domain.ActivityTaskDeleted, bson.ObjectID{}, nil)
 
-func (s *taskService) CreateSubTask(ctx context.Context, taskID, requesterID, title string) (*domain.Task, error) {
-	task, err := s.taskRepo.FindByID(ctx, taskID)
-	if err != nil {
-		return nil, err
+	// The task is gone, so a blob that fails to delete is only logged
+	for _, a := range task.Attachments {
+		if err := s.storage.Delete(ctx, a.StorageKey); err != nil {
+			s.log.Error("failed to delete attachment", "task_id", taskID, "key", a.StorageKey, "error", err)
+		}
 	}
-	project, err := s.projectRepo.FindByID(ctx, task.ProjectID.Hex())
+	return nil
+}
+
+func (s *taskService) CreateSubTask(ctx context.Context, projectID, taskID, requesterID, title string) (*domain.Task, error) {
+	_, task, err := findProjectTask(ctx, s.projectRepo, s.taskRepo, projectID, taskID, requesterID, domain.PermTaskCreate)
 	if err != nil {
 		return nil, err
 	}
-	if !hasAdminOrProjectAdmin(project, requesterID) {
-		return nil, domain.ErrForbidden
-	}
 
 	subtask := domain.SubTask{
 		ID:        bson.NewObjectID(),
@@ -143,63 +183,180 @@ func (s *taskService) CreateSubTask(ctx context.Context, taskID, requesterID, ti
 	if err := s.taskRepo.Update(ctx, task); err != nil {
 		return nil, err
 	}
+	s.record(ctx, task, requesterID, domain.ActivitySubTaskCreated, subtask.ID, diffSubTask(domain.SubTask{}, subtask))
 	return task, nil
 }
 
-func (s *taskService) UpdateSubTask(ctx context.Context, taskID, subTaskID, requesterID string, isCompleted bool) (*domain.Task, error) {
-	task, err := s.taskRepo.FindByID(ctx, taskID)
-	if err != nil {
-		return nil, err
+func (s *taskService) UpdateSubTask(ctx context.Context, projectID, subTaskID, requesterID string, updates map[string]any) (*domain.Task, error) {
+	// task_id is optional since the parent is found from the subtask, but
+	// when a client sends it, it has to name that parent
+	taskID, hasTaskID := updates["task_id"]
+	delete(updates, "task_id")
+
+	perm := domain.PermTaskUpdateAny
+	if _, ok := updates["is_completed"]; ok && len(updates) == 1 {
+		perm = domain.PermTaskUpdateStatus
 	}
-	project, err := s.projectRepo.FindByID(ctx, task.ProjectID.Hex())
+	task, err := s.findSubTaskParent(ctx, projectID, subTaskID, requesterID, perm)
 	if err != nil {
 		return nil, err
 	}
-	if !isMember(project, requesterID) {
-		return nil, domain.ErrForbidden
+	if id, _ := taskID.(string); hasTaskID && id != task.ID.Hex() {
+		return nil, fmt.Errorf("task_id does not match the subtask's task: %w", domain.ErrInvalidInput)
 	}
 
 	for i, st := range task.SubTasks {
 		if st.ID.Hex() == subTaskID {
-			task.SubTasks[i].IsCompleted = isCompleted
+			if isCompleted, ok := updates["is_completed"].(bool); ok {
+				task.SubTasks[i].IsCompleted = isCompleted
+			}
+			if err := applyDates(updates, &task.SubTasks[i].StartDate, &task.SubTasks[i].DueDate); err != nil {
+				return nil, err
+			}
+
+			changes := diffSubTask(st, task.SubTasks[i])
+			if len(changes) == 0 {
+				return task, nil
+			}
 			if err := s.taskRepo.Update(ctx, task); err != nil {
 				return nil, err
 			}
+			s.record(ctx, task, requesterID, domain.ActivitySubTaskUpdated, st.ID, changes)
 			return task, nil
 		}
 	}
 	return nil, domain.ErrNotFound
 }
 
-func (s *taskService) DeleteSubTask(ctx context.Context, taskID, subTaskID, requesterID string) error {
-	task, err := s.taskRepo.FindByID(ctx, taskID)
+func (s *taskService) DeleteSubTask(ctx context.Context, projectID, subTaskID, requesterID string) error {
+	task, err := s.findSubTaskParent(ctx, projectID, subTaskID, requesterID, domain.PermTaskDelete)
 	if err != nil {
 		return err
 	}
-	project, err := s.projectRepo.FindByID(ctx, task.ProjectID.Hex())
-	if err != nil {
-		return err
-	}
-	if !hasAdminOrProjectAdmin(project, requesterID) {
-		return domain.ErrForbidden
-	}
 
 	for i, st := range task.SubTasks {
 		if st.ID.Hex() == subTaskID {
 			task.SubTasks = append(task.SubTasks[:i], task.SubTasks[i+1:]...)
-			return s.taskRepo.Update(ctx, task)
+			if err := s.taskRepo.Update(ctx, task); err != nil {
+				return err
+			}
+			s.record(ctx, task, requesterID, domain.ActivitySubTaskDeleted, st.ID, diffSubTask(st, domain.SubTask{}))
+			return nil
 		}
 	}
 	return domain.ErrNotFound
 }
 
+func (s *taskService) ListActivity(ctx context.Context, projectID, taskID, requesterID string) ([]domain.TaskActivity, error) {
+	if _, _, err := findProjectTask(ctx, s.projectRepo, s.taskRepo, projectID, taskID, requesterID, domain.PermTaskView); err != nil {
+		return nil, err
+	}
+	return s.activityRepo.FindByTaskID(ctx, taskID)
+}
+
 // --- helpers ---
 
-func hasAdminOrProjectAdmin(p *domain.Project, userID string) bool {
-	for _, m := range p.Members {
-		if m.UserID.Hex() == userID && (m.Role == domain.RoleAdmin || m.Role == domain.RoleProjectAdmin) {
-			return true
+// record appends to the task's change log and announces the change on the
+// project's event stream. The task write has already succeeded by then, so
+// a failed activity write is logged rather than reported to the client.
+func (s *taskService) record(ctx context.Context, task *domain.Task, actorID string, action domain.ActivityAction, targetID bson.ObjectID, changes []domain.FieldChange) {
+	if err := recordActivity(ctx, s.activityRepo, task, actorID, action, targetID, changes); err != nil {
+		s.log.Error("failed to record task activity", "task_id", task.ID.Hex(), "action", action, "error", err)
+	}
+
+	// Activity actions on tasks and subtasks double as event types
+	var payload any = *task
+	if action == domain.ActivityTaskDeleted {
+		payload = nil
+	}
+	if targetID.IsZero() {
+		targetID = task.ID
+	}
+	publishEvent(ctx, s.events, task.ProjectID, actorID, domain.EventType(action), targetID, changes, payload)
+}
+
+// findSubTaskParent loads the task in the project holding the subtask,
+// checking that the requester's role there grants perm.
+func (s *taskService) findSubTaskParent(ctx context.Context, projectID, subTaskID, requesterID string, perm domain.Permission) (*domain.Task, error) {
+	if _, err := authorizeProject(ctx, s.projectRepo, projectID, requesterID, perm); err != nil {
+		return nil, err
+	}
+	return s.taskRepo.FindBySubTaskID(ctx, projectID, subTaskID)
+}
+
+// findProjectTask loads a task and its project, checking that the task
+// belongs to the project and that the requester's role there grants perm.
+func findProjectTask(ctx context.Context, projectRepo domain.ProjectRepository, taskRepo domain.TaskRepository, projectID, taskID, requesterID string, perm domain.Permission) (*domain.Project, *domain.Task, error) {
+	project, err := authorizeProject(ctx, projectRepo, projectID, requesterID, perm)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	task, err := taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if task.ProjectID != project.ID {
+		return nil, nil, domain.ErrNotFound
+	}
+	return project, task, nil
+}
+
+// applyDates sets start_date/due_date from updates. A null value clears the
+// date; the resulting due date may not fall before the start date.
+func applyDates(updates map[string]any, start, due **time.Time) error {
+	for key, dst := range map[string]**time.Time{"start_date": start, "due_date": due} {
+		v, ok := updates[key]
+		if !ok {
+			continue
+		}
+		t, err := parseDate(v)
+		if err != nil {
+			return fmt.Errorf("%s must be a date (YYYY-MM-DD) or RFC 3339 timestamp: %w", key, domain.ErrInvalidInput)
+		}
+		*dst = t
+	}
+
+	if *start != nil && *due != nil && (*due).Before(**start) {
+		return fmt.Errorf("due_date must not be before start_date: %w", domain.ErrInvalidInput)
+	}
+	return nil
+}
+
+func parseDate(v any) (*time.Time, error) {
+	if v == nil {
+		return nil, nil
+	}
+	str, ok := v.(string)
+	if !ok {
+		return nil, domain.ErrInvalidInput
+	}
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if t, err := time.Parse(layout, str); err == nil {
+			t = t.UTC()
+			return &t, nil
 		}
 	}
-	return false
+	return nil, domain.ErrInvalidInput
+}
+
+// startOfWeek returns midnight on the Monday of t's week.
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	y, m, d := t.AddDate(0, 0, -offset).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.IsZero() || b.After(a) {
+		return b
+	}
+	return a
 }
\ No newline at end of file
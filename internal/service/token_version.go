@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+)
+
+type cachedVersion struct {
+	version   int
+	expiresAt time.Time
+}
+
+type tokenVersionCache struct {
+	userRepo domain.UserRepository
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cachedVersion
+	// generation moves on every invalidation, so a lookup that raced one
+	// doesn't cache the version it read from before the bump
+	generation uint64
+	swept      time.Time
+}
+
+// NewTokenVersionCache returns a TokenVersionCache that keeps versions for
+// ttl. Bumps made by this process take effect at once; other instances
+// notice them once their entry expires.
+func NewTokenVersionCache(userRepo domain.UserRepository, ttl time.Duration) domain.TokenVersionCache {
+	return &tokenVersionCache{userRepo: userRepo, ttl: ttl, entries: make(map[string]cachedVersion)}
+}
+
+func (c *tokenVersionCache) Version(ctx context.Context, userID string) (int, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[userID]
+	generation := c.generation
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.version, nil
+	}
+
+	user, err := c.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation == generation {
+		c.sweep(now)
+		c.entries[userID] = cachedVersion{version: user.TokenVersion, expiresAt: now.Add(c.ttl)}
+	}
+	return user.TokenVersion, nil
+}
+
+func (c *tokenVersionCache) Invalidate(userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	delete(c.entries, userID)
+}
+
+// sweep drops expired entries at most once per ttl, so users who stop
+// making requests don't stay in memory. The caller must hold c.mu.
+func (c *tokenVersionCache) sweep(now time.Time) {
+	if now.Sub(c.swept) < c.ttl {
+		return
+	}
+	c.swept = now
+	for id, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, id)
+		}
+	}
+}
\ No newline at end of file
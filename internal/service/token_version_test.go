@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/config"
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// slowUserRepo serves one user and, when read is set, holds each lookup
+// until the test lets it finish.
+type slowUserRepo struct {
+	domain.UserRepository
+	user *domain.User
+	read chan chan struct{}
+}
+
+func (r *slowUserRepo) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	version := r.user.TokenVersion
+	if r.read != nil {
+		done := make(chan struct{})
+		r.read <- done
+		<-done
+	}
+	return &domain.User{TokenVersion: version}, nil
+}
+
+func TestTokenVersionCacheServesBumpAfterInvalidate(t *testing.T) {
+	repo := &slowUserRepo{user: &domain.User{TokenVersion: 1}}
+	cache := NewTokenVersionCache(repo, time.Minute)
+	ctx := context.Background()
+
+	if v, _ := cache.Version(ctx, "u1"); v != 1 {
+		t.Fatalf("Version = %d, want 1", v)
+	}
+	repo.user.TokenVersion = 2
+	if v, _ := cache.Version(ctx, "u1"); v != 1 {
+		t.Fatalf("cached Version = %d, want 1 until invalidated", v)
+	}
+	cache.Invalidate("u1")
+	if v, _ := cache.Version(ctx, "u1"); v != 2 {
+		t.Fatalf("Version after Invalidate = %d, want 2", v)
+	}
+}
+
+func TestTokenVersionCacheDropsReadThatRacedInvalidate(t *testing.T) {
+	repo := &slowUserRepo{user: &domain.User{TokenVersion: 1}, read: make(chan chan struct{})}
+	cache := NewTokenVersionCache(repo, time.Minute)
+	ctx := context.Background()
+
+	// A lookup reads version 1, then the version is bumped and invalidated
+	// before the lookup stores what it read
+	result := make(chan int)
+	go func() {
+		v, _ := cache.Version(ctx, "u1")
+		result <- v
+	}()
+	done := <-repo.read
+	repo.user.TokenVersion = 2
+	cache.Invalidate("u1")
+	close(done)
+	<-result
+
+	go func() {
+		v, _ := cache.Version(ctx, "u1")
+		result <- v
+	}()
+	select {
+	case done := <-repo.read:
+		close(done)
+	case v := <-result:
+		t.Fatalf("served stale cached version %d", v)
+	}
+	if v := <-result; v != 2 {
+		t.Fatalf("Version = %d, want 2", v)
+	}
+}
+
+type versionedUserRepo struct {
+	*memUserRepo
+}
+
+func (r versionedUserRepo) IncrementTokenVersion(ctx context.Context, id string) error {
+	r.users[id].TokenVersion++
+	return nil
+}
+
+type otherSessionsRepo struct{ memSessionRepo }
+
+func (otherSessionsRepo) DeleteOthers(ctx context.Context, userID, keepSessionID string) error {
+	return nil
+}
+
+type invalidatedVersions struct {
+	domain.TokenVersionCache
+	users []string
+}
+
+func (v *invalidatedVersions) Invalidate(userID string) { v.users = append(v.users, userID) }
+
+func TestChangePasswordBumpsTokenVersion(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &domain.User{ID: bson.NewObjectID(), Email: "ada@example.com", Password: string(hash), TokenVersion: 3}
+	users := versionedUserRepo{&memUserRepo{users: map[string]*domain.User{user.ID.Hex(): user}}}
+	versions := &invalidatedVersions{}
+	svc := NewAuthService(users, otherSessionsRepo{}, nopAuditRepo{}, nil, versions, NewSessionCache(nil, 0), nil, nopInvitations{}, nil, nil, config.JWTConfig{}, config.AuthConfig{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if err := svc.ChangePassword(context.Background(), user.ID.Hex(), "session", "correct horse", "battery staple"); err != nil {
+		t.Fatal(err)
+	}
+	if got := users.users[user.ID.Hex()].TokenVersion; got != 4 {
+		t.Errorf("token version = %d, want 4 so earlier access tokens and MFA challenges stop working", got)
+	}
+	if !slices.Equal(versions.users, []string{user.ID.Hex()}) {
+		t.Errorf("invalidated %v, want the user's cached version dropped", versions.users)
+	}
+}
\ No newline at end of file
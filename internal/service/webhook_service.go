@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type webhookService struct {
+	webhookRepo  domain.WebhookRepository
+	deliveryRepo domain.WebhookDeliveryRepository
+	projectRepo  domain.ProjectRepository
+	auditRepo    domain.AuditRepository
+}
+
+func NewWebhookService(webhookRepo domain.WebhookRepository, deliveryRepo domain.WebhookDeliveryRepository, projectRepo domain.ProjectRepository, auditRepo domain.AuditRepository) domain.WebhookService {
+	return &webhookService{webhookRepo: webhookRepo, deliveryRepo: deliveryRepo, projectRepo: projectRepo, auditRepo: auditRepo}
+}
+
+func (s *webhookService) CreateWebhook(ctx context.Context, projectID, requesterID, url string, events []domain.EventType) (*domain.Webhook, string, error) {
+	project, err := s.findAdminProject(ctx, projectID, requesterID)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := validateWebhookEvents(events); err != nil {
+		return nil, "", err
+	}
+	if err := checkEventPermissions(project, requesterID, events); err != nil {
+		return nil, "", err
+	}
+
+	secret, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	requesterOID, _ := bson.ObjectIDFromHex(requesterID)
+	hook := &domain.Webhook{
+		ProjectID: project.ID,
+		URL:       url,
+		Events:    slices.Compact(slices.Sorted(slices.Values(events))),
+		Secret:    secret,
+		Active:    true,
+		CreatedBy: requesterOID,
+	}
+	if err := s.webhookRepo.Create(ctx, hook); err != nil {
+		return nil, "", err
+	}
+
+	changes := []domain.FieldChange{{Field: "url", To: url}, {Field: "events", To: formatEventTypes(hook.Events)}}
+	if err := recordAudit(ctx, s.auditRepo, project.ID, requesterID, domain.AuditWebhookCreated, "webhook", hook.ID.Hex(), changes); err != nil {
+		return nil, "", err
+	}
+	return hook, secret, nil
+}
+
+func (s *webhookService) ListWebhooks(ctx context.Context, projectID, requesterID string) ([]domain.Webhook, error) {
+	if _, err := s.findAdminProject(ctx, projectID, requesterID); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.FindByProjectID(ctx, projectID)
+}
+
+func (s *webhookService) GetWebhook(ctx context.Context, projectID, webhookID, requesterID string) (*domain.Webhook, error) {
+	if _, err := s.findAdminProject(ctx, projectID, requesterID); err != nil {
+		return nil, err
+	}
+	return s.findWebhook(ctx, projectID, webhookID)
+}
+
+func (s *webhookService) UpdateWebhook(ctx context.Context, projectID, webhookID, requesterID string, update domain.WebhookUpdate) (*domain.Webhook, error) {
+	project, err := s.findAdminProject(ctx, projectID, requesterID)
+	if err != nil {
+		return nil, err
+	}
+	hook, err := s.findWebhook(ctx, projectID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []domain.FieldChange
+	if update.URL != nil {
+		changes = appendChange(changes, "url", hook.URL, *update.URL)
+		hook.URL = *update.URL
+	}
+	if update.Events != nil {
+		if err := validateWebhookEvents(update.Events); err != nil {
+			return nil, err
+		}
+		if err := checkEventPermissions(project, requesterID, update.Events); err != nil {
+			return nil, err
+		}
+		events := slices.Compact(slices.Sorted(slices.Values(update.Events)))
+		changes = appendChange(changes, "events", formatEventTypes(hook.Events), formatEventTypes(events))
+		hook.Events = events
+	}
+	if update.Active != nil {
+		changes = appendChange(changes, "active", fmt.Sprint(hook.Active), fmt.Sprint(*update.Active))
+		hook.Active = *update.Active
+	}
+	if len(changes) == 0 {
+		return hook, nil
+	}
+
+	if err := s.webhookRepo.Update(ctx, hook); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.auditRepo, hook.ProjectID, requesterID, domain.AuditWebhookUpdated, "webhook", webhookID, changes); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, projectID, webhookID, requesterID string) error {
+	if _, err := s.findAdminProject(ctx, projectID, requesterID); err != nil {
+		return err
+	}
+	hook, err := s.findWebhook(ctx, projectID, webhookID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.webhookRepo.Delete(ctx, webhookID); err != nil {
+		return err
+	}
+	if err := s.deliveryRepo.DeleteByWebhookID(ctx, webhookID); err != nil {
+		return err
+	}
+	changes := []domain.FieldChange{{Field: "url", From: hook.URL}}
+	return recordAudit(ctx, s.auditRepo, hook.ProjectID, requesterID, domain.AuditWebhookDeleted, "webhook", webhookID, changes)
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, projectID, webhookID, requesterID string, page domain.PageRequest) (*domain.Page[domain.WebhookDelivery], error) {
+	if _, err := s.findAdminProject(ctx, projectID, requesterID); err != nil {
+		return nil, err
+	}
+	if _, err := s.findWebhook(ctx, projectID, webhookID); err != nil {
+		return nil, err
+	}
+
+	// Newest deliveries first unless asked otherwise
+	if page.Sort == "" {
+		page.Sort = "-created_at"
+	}
+	page, err := normalizePage(page, "created_at")
+	if err != nil {
+		return nil, err
+	}
+	return s.deliveryRepo.FindByWebhookID(ctx, webhookID, page)
+}
+
+func (s *webhookService) Redeliver(ctx context.Context, projectID, webhookID, deliveryID, requesterID string) (*domain.WebhookDelivery, error) {
+	if _, err := s.findAdminProject(ctx, projectID, requesterID); err != nil {
+		return nil, err
+	}
+	hook, err := s.findWebhook(ctx, projectID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if !hook.Active {
+		return nil, fmt.Errorf("webhook is disabled: %w", domain.ErrConflict)
+	}
+
+	original, err := s.deliveryRepo.FindByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original.WebhookID != hook.ID {
+		return nil, domain.ErrNotFound
+	}
+
+	// Queue a fresh copy so the original attempt stays in the log
+	delivery := &domain.WebhookDelivery{
+		WebhookID:     hook.ID,
+		ProjectID:     hook.ProjectID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        domain.DeliveryPending,
+		NextAttemptAt: time.Now(),
+		RedeliveryOf:  original.ID,
+	}
+	if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (s *webhookService) Enqueue(ctx context.Context, event domain.ProjectEvent) error {
+	// The change has already happened; don't lose its deliveries if the
+	// request that caused it goes away
+	ctx = context.WithoutCancel(ctx)
+
+	types := []domain.EventType{event.Type}
+	if event.Type == domain.EventTaskUpdated && slices.ContainsFunc(event.Changes, func(c domain.FieldChange) bool { return c.Field == "status" }) {
+		types = append(types, domain.EventTaskStatusChanged)
+	}
+
+	hooks, err := s.webhookRepo.FindSubscribed(ctx, event.ProjectID.Hex(), types)
+	if err != nil || len(hooks) == 0 {
+		return err
+	}
+	project, err := s.projectRepo.FindByID(ctx, event.ProjectID.Hex())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, eventType := range types {
+		event.ID = bson.NewObjectID().Hex()
+		event.Type = eventType
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		for _, hook := range hooks {
+			// A webhook sees no more than the member who created it can
+			if !slices.Contains(hook.Events, eventType) || !project.Can(hook.CreatedBy.Hex(), eventType.Permission()) {
+				continue
+			}
+			delivery := &domain.WebhookDelivery{
+				WebhookID:     hook.ID,
+				ProjectID:     hook.ProjectID,
+				EventID:       event.ID,
+				EventType:     eventType,
+				Payload:       payload,
+				Status:        domain.DeliveryPending,
+				NextAttemptAt: time.Now(),
+			}
+			if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// --- helpers ---
+
+func (s *webhookService) findAdminProject(ctx context.Context, projectID, requesterID string) (*domain.Project, error) {
+	return authorizeProject(ctx, s.projectRepo, projectID, requesterID, domain.PermWebhookManage)
+}
+
+func (s *webhookService) findWebhook(ctx context.Context, projectID, webhookID string) (*domain.Webhook, error) {
+	hook, err := s.webhookRepo.FindByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if hook.ProjectID.Hex() != projectID {
+		return nil, domain.ErrNotFound
+	}
+	return hook, nil
+}
+
+func validateWebhookEvents(events []domain.EventType) error {
+	if len(events) == 0 {
+		return fmt.Errorf("events must not be empty: %w", domain.ErrInvalidInput)
+	}
+	for _, e := range events {
+		if !slices.Contains(domain.WebhookEventTypes, e) {
+			return fmt.Errorf("unknown event type %q: %w", e, domain.ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// checkEventPermissions keeps members from subscribing a webhook to events
+// they couldn't see themselves.
+func checkEventPermissions(p *domain.Project, requesterID string, events []domain.EventType) error {
+	for _, e := range events {
+		if !p.Can(requesterID, e.Permission()) {
+			return fmt.Errorf("%q events need the %q permission: %w", e, e.Permission(), domain.ErrForbidden)
+		}
+	}
+	return nil
+}
+
+func formatEventTypes(events []domain.EventType) string {
+	names := make([]string, len(events))
+	for i, e := range events {
+		names[i] = string(e)
+	}
+	return strings.Join(names, ",")
+}
\ No newline at end of file
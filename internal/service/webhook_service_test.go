@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type subscribedWebhookRepo struct {
+	domain.WebhookRepository
+	hooks []domain.Webhook
+}
+
+func (r subscribedWebhookRepo) FindSubscribed(ctx context.Context, projectID string, eventTypes []domain.EventType) ([]domain.Webhook, error) {
+	return r.hooks, nil
+}
+
+type memDeliveryRepo struct {
+	domain.WebhookDeliveryRepository
+	deliveries []domain.WebhookDelivery
+}
+
+func (r *memDeliveryRepo) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	r.deliveries = append(r.deliveries, *delivery)
+	return nil
+}
+
+func TestEnqueueSkipsEventsTheCreatorCannotSee(t *testing.T) {
+	project, viewer := newTaskOnlyProject()
+	hook := domain.Webhook{ID: bson.NewObjectID(), ProjectID: project.ID, Events: domain.WebhookEventTypes, Active: true, CreatedBy: viewer}
+	deliveries := &memDeliveryRepo{}
+	svc := NewWebhookService(subscribedWebhookRepo{hooks: []domain.Webhook{hook}}, deliveries, newMemProjectRepo(project), nopAuditRepo{})
+	ctx := context.Background()
+
+	for _, eventType := range []domain.EventType{domain.EventNoteCreated, domain.EventTaskCreated, domain.EventMemberAdded} {
+		if err := svc.Enqueue(ctx, domain.ProjectEvent{ProjectID: project.ID, Type: eventType}); err != nil {
+			t.Fatalf("Enqueue %s: %v", eventType, err)
+		}
+	}
+	var got []domain.EventType
+	for _, d := range deliveries.deliveries {
+		got = append(got, d.EventType)
+	}
+	if len(got) != 2 || got[0] != domain.EventTaskCreated || got[1] != domain.EventMemberAdded {
+		t.Fatalf("delivered %v, want task.created and member.added", got)
+	}
+}
+
+func TestCreateWebhookRefusesEventsTheRequesterCannotSee(t *testing.T) {
+	project, viewer := newTaskOnlyProject()
+	svc := NewWebhookService(nil, nil, newMemProjectRepo(project), nopAuditRepo{})
+
+	_, _, err := svc.CreateWebhook(context.Background(), project.ID.Hex(), viewer.Hex(), "https://hooks.example.com", []domain.EventType{domain.EventNoteCreated})
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("CreateWebhook error = %v, want ErrForbidden", err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+)
+
+// validateWorkflow checks that a workflow is self-consistent: statuses are
+// non-empty and unique, and transitions and closed statuses only reference
+// statuses that exist.
+func validateWorkflow(w domain.Workflow) error {
+	if len(w.Statuses) == 0 {
+		return fmt.Errorf("workflow must define at least one status: %w", domain.ErrInvalidInput)
+	}
+
+	seen := make(map[domain.TaskStatus]bool, len(w.Statuses))
+	for _, st := range w.Statuses {
+		if strings.TrimSpace(string(st)) == "" {
+			return fmt.Errorf("workflow status names must not be empty: %w", domain.ErrInvalidInput)
+		}
+		if seen[st] {
+			return fmt.Errorf("workflow status %q is listed twice: %w", st, domain.ErrInvalidInput)
+		}
+		seen[st] = true
+	}
+
+	for from, targets := range w.Transitions {
+		if !seen[from] {
+			return fmt.Errorf("transition from unknown status %q: %w", from, domain.ErrInvalidInput)
+		}
+		for _, to := range targets {
+			if !seen[to] {
+				return fmt.Errorf("transition to unknown status %q: %w", to, domain.ErrInvalidInput)
+			}
+		}
+	}
+
+	for _, st := range w.Closed {
+		if !seen[st] {
+			return fmt.Errorf("closed status %q is not in the workflow: %w", st, domain.ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// checkTransition reports whether a task may move from one status to another
+// under the given workflow. Staying in the same status is always allowed.
+func checkTransition(w domain.Workflow, from, to domain.TaskStatus) error {
+	if !slices.Contains(w.Statuses, to) {
+		return fmt.Errorf("status %q is not part of this project's workflow: %w", to, domain.ErrInvalidInput)
+	}
+	if from == to {
+		return nil
+	}
+	if !slices.Contains(w.Transitions[from], to) {
+		return fmt.Errorf("%w: cannot move a task from %q to %q", domain.ErrInvalidTransition, from, to)
+	}
+	return nil
+}
+
+// formatWorkflow renders a workflow as JSON for audit records.
+func formatWorkflow(w domain.Workflow) string {
+	b, _ := json.Marshal(w)
+	return string(b)
+}
\ No newline at end of file
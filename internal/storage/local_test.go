@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+)
+
+func newTestStorage(t *testing.T) (domain.BlobStorage, string) {
+	t.Helper()
+	dir := t.TempDir()
+	s, err := NewLocalStorage(dir)
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	return s, dir
+}
+
+func TestLocalStorageRoundTrip(t *testing.T) {
+	s, dir := newTestStorage(t)
+	ctx := context.Background()
+
+	if err := s.Save(ctx, "p/t/a", strings.NewReader("hello")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "p", "t", "a")); err != nil {
+		t.Fatalf("file not written under root: %v", err)
+	}
+
+	rc, err := s.Open(ctx, "p/t/a")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	got, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(got) != "hello" {
+		t.Fatalf("Open returned %q, want %q", got, "hello")
+	}
+
+	if err := s.Save(ctx, "p/t/a", strings.NewReader("replaced")); err != nil {
+		t.Fatalf("Save over existing: %v", err)
+	}
+	rc, _ = s.Open(ctx, "p/t/a")
+	got, _ = io.ReadAll(rc)
+	rc.Close()
+	if string(got) != "replaced" {
+		t.Fatalf("Open after overwrite returned %q", got)
+	}
+
+	if err := s.Delete(ctx, "p/t/a"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Open(ctx, "p/t/a"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("Open after delete: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocalStorageMissingKey(t *testing.T) {
+	s, _ := newTestStorage(t)
+	ctx := context.Background()
+
+	if _, err := s.Open(ctx, "nope"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("Open: got %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(ctx, "nope"); err != nil {
+		t.Fatalf("Delete of a missing key should succeed, got %v", err)
+	}
+}
+
+func TestLocalStorageFailedSaveLeavesNothing(t *testing.T) {
+	s, dir := newTestStorage(t)
+
+	err := s.Save(context.Background(), "p/a", io.MultiReader(strings.NewReader("part"), errReader{}))
+	if err == nil {
+		t.Fatal("Save: expected the read error")
+	}
+	entries, _ := os.ReadDir(filepath.Join(dir, "p"))
+	if len(entries) != 0 {
+		t.Fatalf("failed save left %d files behind", len(entries))
+	}
+}
+
+func TestLocalStorageRejectsEscapingKeys(t *testing.T) {
+	s, _ := newTestStorage(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"", ".", "..", "../outside", "a/../../outside", "a/../.."} {
+		if err := s.Save(ctx, key, strings.NewReader("x")); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("Save(%q): got %v, want ErrInvalidInput", key, err)
+		}
+		if _, err := s.Open(ctx, key); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("Open(%q): got %v, want ErrInvalidInput", key, err)
+		}
+		if err := s.Delete(ctx, key); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("Delete(%q): got %v, want ErrInvalidInput", key, err)
+		}
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errors.New("read failed") }
\ No newline at end of file
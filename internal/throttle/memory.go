@@ -0,0 +1,112 @@
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+)
+
+// sweepInterval is how often expired records are dropped.
+const sweepInterval = time.Minute
+
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]domain.AttemptRecord
+	swept   time.Time
+}
+
+// NewMemoryStore returns an AttemptStore that lives in this process. Each
+// instance counts separately, so use the MongoDB store behind a load
+// balancer.
+func NewMemoryStore() domain.AttemptStore {
+	return &memoryStore{records: make(map[string]domain.AttemptRecord)}
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) (*domain.AttemptRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record := s.live(key, time.Now())
+	return &record, nil
+}
+
+func (s *memoryStore) Fail(ctx context.Context, key string, window time.Duration) (*domain.AttemptRecord, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	record := s.live(key, now)
+	if now.Sub(record.LastFailure) >= window {
+		record.Failures = 0
+	}
+	record.Failures++
+	record.LastFailure = now
+	record.ExpiresAt = now.Add(window)
+	if record.LockedUntil.After(record.ExpiresAt) {
+		record.ExpiresAt = record.LockedUntil
+	}
+	s.records[key] = record
+	return &record, nil
+}
+
+func (s *memoryStore) Refund(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record := s.live(key, time.Now())
+	if record.Failures == 0 {
+		return nil
+	}
+	record.Failures--
+	s.records[key] = record
+	return nil
+}
+
+func (s *memoryStore) Lock(ctx context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record := s.live(key, time.Now())
+	record.Failures = 0
+	record.LockedUntil = until
+	if until.After(record.ExpiresAt) {
+		record.ExpiresAt = until
+	}
+	s.records[key] = record
+	return nil
+}
+
+func (s *memoryStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+// live returns the key's record, or an empty one if it has expired. The
+// caller must hold s.mu.
+func (s *memoryStore) live(key string, now time.Time) domain.AttemptRecord {
+	record, ok := s.records[key]
+	if !ok || !now.Before(record.ExpiresAt) {
+		return domain.AttemptRecord{Key: key}
+	}
+	return record
+}
+
+// sweep drops expired records at most once per sweepInterval, so keys that
+// stop failing don't stay in memory. The caller must hold s.mu.
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.swept) < sweepInterval {
+		return
+	}
+	s.swept = now
+	for key, record := range s.records {
+		if !now.Before(record.ExpiresAt) {
+			delete(s.records, key)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/pkg/validator"
+)
+
+// newClient returns the HTTP client deliveries are sent with. allow is
+// asked about every address the client connects to, after DNS has been
+// resolved, so a webhook hostname can't be pointed at an internal service.
+// Redirects are never followed; a 3xx counts as a failed delivery.
+func newClient(timeout time.Duration, allow func(netip.Addr) error) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			return allow(addrPort.Addr())
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// Through a proxy the check would only ever see the proxy's address
+	transport.Proxy = nil
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func publicOnly(addr netip.Addr) error {
+	if !validator.IsPublicAddr(addr) {
+		return fmt.Errorf("refusing to connect to non-public address %s", addr)
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,167 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/config"
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+)
+
+const (
+	baseBackoff = 30 * time.Second
+	maxBackoff  = time.Hour
+)
+
+// Dispatcher sends queued webhook deliveries, retrying failures with
+// exponential backoff. Several dispatchers may share one queue.
+type Dispatcher struct {
+	webhookRepo  domain.WebhookRepository
+	deliveryRepo domain.WebhookDeliveryRepository
+	client       *http.Client
+	cfg          config.WebhookConfig
+	log          *slog.Logger
+}
+
+func NewDispatcher(webhookRepo domain.WebhookRepository, deliveryRepo domain.WebhookDeliveryRepository, cfg config.WebhookConfig, log *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		client:       newClient(time.Duration(cfg.TimeoutSeconds)*time.Second, publicOnly),
+		cfg:          cfg,
+		log:          log,
+	}
+}
+
+// Run polls the queue with cfg.Workers workers until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range max(d.cfg.Workers, 1) {
+		wg.Go(func() {
+			ticker := time.NewTicker(time.Duration(d.cfg.PollIntervalSeconds) * time.Second)
+			defer ticker.Stop()
+			for {
+				d.drain(ctx)
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// Sign returns the X-Webhook-Signature value for a delivery body sent at
+// timestamp (Unix seconds).
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// --- helpers ---
+
+// drain sends due deliveries until the queue has none left.
+func (d *Dispatcher) drain(ctx context.Context) {
+	// Hold the lease long enough to cover a request that times out
+	lease := 2 * d.client.Timeout
+	for ctx.Err() == nil {
+		delivery, err := d.deliveryRepo.ClaimDue(ctx, time.Now(), lease)
+		if errors.Is(err, domain.ErrNotFound) {
+			return
+		}
+		if err != nil {
+			d.log.Error("failed to claim webhook delivery", "error", err)
+			return
+		}
+		d.deliver(ctx, delivery)
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery *domain.WebhookDelivery) {
+	hook, err := d.webhookRepo.FindByID(ctx, delivery.WebhookID.Hex())
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		// Leave it locked; it is retried once the lease expires
+		d.log.Error("failed to load webhook", "webhook_id", delivery.WebhookID.Hex(), "error", err)
+		return
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.LockedUntil = time.Time{}
+
+	switch {
+	case hook == nil:
+		delivery.Status, delivery.LastError = domain.DeliveryFailed, "webhook was deleted"
+	case !hook.Active:
+		delivery.Status, delivery.LastError = domain.DeliveryFailed, "webhook is disabled"
+	default:
+		delivery.LastStatusCode, err = d.send(ctx, hook, delivery)
+		switch {
+		case err == nil:
+			delivery.Status, delivery.LastError = domain.DeliverySucceeded, ""
+		case delivery.Attempts >= d.cfg.MaxAttempts:
+			delivery.Status, delivery.LastError = domain.DeliveryFailed, err.Error()
+		default:
+			delivery.LastError = err.Error()
+			delivery.NextAttemptAt = now.Add(backoff(delivery.Attempts))
+		}
+	}
+
+	if err := d.deliveryRepo.Update(ctx, delivery); err != nil {
+		d.log.Error("failed to update webhook delivery", "delivery_id", delivery.ID.Hex(), "error", err)
+	}
+}
+
+// send posts the delivery and returns the response status. Anything but a
+// 2xx is an error.
+func (d *Dispatcher) send(ctx context.Context, hook *domain.Webhook, delivery *domain.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "project-camp-webhooks")
+	req.Header.Set("X-Webhook-Event", string(delivery.EventType))
+	req.Header.Set("X-Webhook-Delivery", delivery.ID.Hex())
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", Sign(hook.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain a little so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func backoff(attempts int) time.Duration {
+	wait := baseBackoff << (attempts - 1)
+	if wait <= 0 || wait > maxBackoff {
+		return maxBackoff
+	}
+	return wait
+}
\ No newline at end of file
@@ -0,0 +1,239 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/config"
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type fakeWebhookRepo struct {
+	domain.WebhookRepository
+	hooks map[string]*domain.Webhook
+}
+
+func (f *fakeWebhookRepo) FindByID(ctx context.Context, id string) (*domain.Webhook, error) {
+	if h, ok := f.hooks[id]; ok {
+		return h, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+type fakeDeliveryRepo struct {
+	domain.WebhookDeliveryRepository
+	updated []domain.WebhookDelivery
+}
+
+func (f *fakeDeliveryRepo) Update(ctx context.Context, d *domain.WebhookDelivery) error {
+	f.updated = append(f.updated, *d)
+	return nil
+}
+
+var testConfig = config.WebhookConfig{Workers: 1, TimeoutSeconds: 2, MaxAttempts: 3, PollIntervalSeconds: 1}
+
+// newTestDispatcher returns a dispatcher for hook and the repository its
+// delivery updates land in. The dispatcher may reach loopback addresses
+// unless strict is set.
+func newTestDispatcher(hook *domain.Webhook, strict bool) (*Dispatcher, *fakeDeliveryRepo) {
+	hooks := &fakeWebhookRepo{hooks: map[string]*domain.Webhook{}}
+	if hook != nil {
+		hooks.hooks[hook.ID.Hex()] = hook
+	}
+	deliveries := &fakeDeliveryRepo{}
+	d := NewDispatcher(hooks, deliveries, testConfig, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if !strict {
+		d.client = newClient(2*time.Second, func(netip.Addr) error { return nil })
+	}
+	return d, deliveries
+}
+
+func newHook(url string) *domain.Webhook {
+	return &domain.Webhook{ID: bson.NewObjectID(), URL: url, Secret: "s3cret", Active: true}
+}
+
+func newDelivery(hook *domain.Webhook, attempts int) *domain.WebhookDelivery {
+	return &domain.WebhookDelivery{
+		ID:        bson.NewObjectID(),
+		WebhookID: hook.ID,
+		EventType: domain.EventTaskCreated,
+		Payload:   []byte(`{"type":"task.created"}`),
+		Status:    domain.DeliveryPending,
+		Attempts:  attempts,
+	}
+}
+
+func TestDeliverSignsRequest(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	hook := newHook(srv.URL)
+	d, repo := newTestDispatcher(hook, false)
+	delivery := newDelivery(hook, 0)
+	d.deliver(context.Background(), delivery)
+
+	if got == nil {
+		t.Fatal("receiver was not called")
+	}
+	ts := got.Header.Get("X-Webhook-Timestamp")
+	if _, err := strconv.ParseInt(ts, 10, 64); err != nil {
+		t.Fatalf("timestamp %q is not Unix seconds", ts)
+	}
+	if want := Sign("s3cret", ts, body); got.Header.Get("X-Webhook-Signature") != want {
+		t.Errorf("signature = %q, want %q", got.Header.Get("X-Webhook-Signature"), want)
+	}
+	if string(body) != string(delivery.Payload) {
+		t.Errorf("body = %s, want the payload", body)
+	}
+	for header, want := range map[string]string{
+		"Content-Type":       "application/json",
+		"X-Webhook-Event":    "task.created",
+		"X-Webhook-Delivery": delivery.ID.Hex(),
+	} {
+		if got.Header.Get(header) != want {
+			t.Errorf("%s = %q, want %q", header, got.Header.Get(header), want)
+		}
+	}
+
+	last := repo.updated[len(repo.updated)-1]
+	if last.Status != domain.DeliverySucceeded || last.Attempts != 1 || last.LastStatusCode != 200 {
+		t.Errorf("delivery = %+v, want succeeded after 1 attempt", last)
+	}
+}
+
+func TestSign(t *testing.T) {
+	// HMAC-SHA256 of "1700000000.{}" keyed with "key"
+	const want = "sha256=9d713ed406bb7076d4123f0dc2c39d2df5c654ed4b0cd56b52c8b4c940bd63ae"
+	if got := Sign("key", "1700000000", []byte("{}")); got != want {
+		t.Errorf("Sign = %q, want %q", got, want)
+	}
+}
+
+func TestDeliverRetriesWithBackoff(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	hook := newHook(srv.URL)
+	d, repo := newTestDispatcher(hook, false)
+
+	for attempts := range testConfig.MaxAttempts {
+		before := time.Now()
+		d.deliver(context.Background(), newDelivery(hook, attempts))
+		got := repo.updated[len(repo.updated)-1]
+
+		if got.Attempts != attempts+1 || got.LastStatusCode != 500 || got.LastError == "" {
+			t.Fatalf("attempt %d: delivery = %+v", attempts+1, got)
+		}
+		if attempts+1 == testConfig.MaxAttempts {
+			if got.Status != domain.DeliveryFailed {
+				t.Errorf("last attempt: status = %s, want failed", got.Status)
+			}
+			continue
+		}
+		if got.Status != domain.DeliveryPending {
+			t.Errorf("attempt %d: status = %s, want pending", attempts+1, got.Status)
+		}
+		wait := got.NextAttemptAt.Sub(before)
+		if want := backoff(attempts + 1); wait < want || wait > want+time.Second {
+			t.Errorf("attempt %d: retried after %s, want %s", attempts+1, wait, want)
+		}
+	}
+}
+
+func TestDeliverWithoutActiveWebhook(t *testing.T) {
+	disabled := newHook("https://example.com/hook")
+	disabled.Active = false
+	d, repo := newTestDispatcher(disabled, false)
+
+	d.deliver(context.Background(), newDelivery(disabled, 0))
+	d.deliver(context.Background(), newDelivery(newHook("https://example.com/gone"), 0))
+
+	for i, want := range []string{"webhook is disabled", "webhook was deleted"} {
+		got := repo.updated[i]
+		if got.Status != domain.DeliveryFailed || got.LastError != want {
+			t.Errorf("delivery %d = %s %q, want failed %q", i, got.Status, got.LastError, want)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{100, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestDeliverRefusesPrivateAddresses(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer srv.Close()
+
+	// localhost resolves to loopback, so only the connect-time check catches it
+	url := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+	for _, u := range []string{srv.URL, url} {
+		hook := newHook(u)
+		d, repo := newTestDispatcher(hook, true)
+		d.deliver(context.Background(), newDelivery(hook, 0))
+
+		got := repo.updated[0]
+		if got.Status != domain.DeliveryPending || !strings.Contains(got.LastError, "non-public address") {
+			t.Errorf("%s: delivery = %s %q, want a refused connection", u, got.Status, got.LastError)
+		}
+	}
+	if hits.Load() != 0 {
+		t.Errorf("receiver was called %d times", hits.Load())
+	}
+}
+
+func TestDeliverDoesNotFollowRedirects(t *testing.T) {
+	var hits atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer target.Close()
+	redirect := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	defer redirect.Close()
+
+	hook := newHook(redirect.URL)
+	d, repo := newTestDispatcher(hook, false)
+	d.deliver(context.Background(), newDelivery(hook, 0))
+
+	got := repo.updated[0]
+	if got.Status != domain.DeliveryPending || got.LastStatusCode != http.StatusTemporaryRedirect {
+		t.Errorf("delivery = %s %d, want pending after a 307", got.Status, got.LastStatusCode)
+	}
+	if hits.Load() != 0 {
+		t.Error("redirect was followed")
+	}
+}
\ No newline at end of file
@@ -38,6 +38,27 @@ func RunIndexes(db *mongo.Database, log *slog.Logger) error {
 				Keys: bson.D{{Key: "reset_token", Value: 1}},
 			},
 		},
+		{
+			collection: "users",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "unlock_token", Value: 1}},
+			},
+		},
+		{
+			collection: "users",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "email_change_token", Value: 1}},
+			},
+		},
+		{
+			collection: "users",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "identities.provider", Value: 1}, {Key: "identities.subject", Value: 1}},
+				Options: options.Index().
+					SetUnique(true).
+					SetPartialFilterExpression(bson.M{"identities.subject": bson.M{"$exists": true}}),
+			},
+		},
 		// Projects
 		{
 			collection: "projects",
@@ -52,12 +73,45 @@ func RunIndexes(db *mongo.Database, log *slog.Logger) error {
 				Keys: bson.D{{Key: "project_id", Value: 1}},
 			},
 		},
+		{
+			collection: "tasks",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "subtasks._id", Value: 1}},
+			},
+		},
 		{
 			collection: "tasks",
 			model: mongo.IndexModel{
 				Keys: bson.D{{Key: "assigned_to", Value: 1}},
 			},
 		},
+		{
+			collection: "tasks",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "created_by", Value: 1}},
+			},
+		},
+		{
+			collection: "tasks",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "due_date", Value: 1}},
+			},
+		},
+		{
+			collection: "tasks",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "start_date", Value: 1}},
+			},
+		},
+		{
+			collection: "tasks",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}, {Key: "subtasks.title", Value: "text"}},
+				Options: options.Index().
+					SetName("tasks_text").
+					SetWeights(bson.D{{Key: "title", Value: 10}, {Key: "subtasks.title", Value: 5}, {Key: "description", Value: 1}}),
+			},
+		},
 		// Notes
 		{
 			collection: "notes",
@@ -65,6 +119,207 @@ func RunIndexes(db *mongo.Database, log *slog.Logger) error {
 				Keys: bson.D{{Key: "project_id", Value: 1}},
 			},
 		},
+		{
+			collection: "notes",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "created_by", Value: 1}},
+			},
+		},
+		{
+			collection: "notes",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "title", Value: "text"}, {Key: "content", Value: "text"}},
+				Options: options.Index().
+					SetName("notes_text").
+					SetWeights(bson.D{{Key: "title", Value: 10}, {Key: "content", Value: 1}}),
+			},
+		},
+		// Task activity
+		{
+			collection: "task_activity",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "created_at", Value: -1}},
+			},
+		},
+		// Audit log
+		{
+			collection: "audit_log",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "_id", Value: -1}},
+			},
+		},
+		{
+			collection: "audit_log",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "actor_id", Value: 1}, {Key: "_id", Value: -1}},
+			},
+		},
+		// Webhooks
+		{
+			collection: "webhooks",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "active", Value: 1}, {Key: "events", Value: 1}},
+			},
+		},
+		{
+			collection: "webhook_deliveries",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+			},
+		},
+		{
+			collection: "webhook_deliveries",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}},
+			},
+		},
+		// Sessions
+		{
+			collection: "sessions",
+			model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "token_hash", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
+		{
+			collection: "sessions",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "previous_hashes", Value: 1}},
+			},
+		},
+		{
+			collection: "sessions",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "last_seen_at", Value: -1}},
+			},
+		},
+		{
+			collection: "sessions",
+			model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "expires_at", Value: 1}},
+				Options: options.Index().SetExpireAfterSeconds(0),
+			},
+		},
+		// Login throttling
+		{
+			collection: "login_attempts",
+			model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "expires_at", Value: 1}},
+				Options: options.Index().SetExpireAfterSeconds(0),
+			},
+		},
+		// Personal access tokens
+		{
+			collection: "access_tokens",
+			model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "token_hash", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
+		{
+			collection: "access_tokens",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+			},
+		},
+		{
+			collection: "access_tokens",
+			model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "expires_at", Value: 1}},
+				Options: options.Index().SetExpireAfterSeconds(0),
+			},
+		},
+		// Comments
+		{
+			collection: "comments",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "created_at", Value: 1}},
+			},
+		},
+		{
+			collection: "comments",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "parent_id", Value: 1}},
+			},
+		},
+		{
+			collection: "comments",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "mentions", Value: 1}},
+			},
+		},
+		{
+			collection: "comments",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "created_by", Value: 1}},
+			},
+		},
+		// Invitations
+		{
+			collection: "invitations",
+			model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "token_hash", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
+		{
+			collection: "invitations",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "status", Value: 1}},
+			},
+		},
+		{
+			collection: "invitations",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "email", Value: 1}, {Key: "status", Value: 1}},
+			},
+		},
+		// Join links
+		{
+			collection: "join_links",
+			model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "token_hash", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
+		{
+			collection: "join_links",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "project_id", Value: 1}},
+			},
+		},
+		{
+			collection: "join_link_uses",
+			model: mongo.IndexModel{
+				Keys: bson.D{{Key: "link_id", Value: 1}, {Key: "created_at", Value: 1}},
+			},
+		},
+	}
+
+	// Paged listings sort on one field with _id as a tie-breaker inside a
+	// scope; these must match the sort fields the services allow
+	paged := []struct {
+		collection string
+		scope      string
+		fields     []string
+	}{
+		{collection: "tasks", scope: "project_id", fields: []string{"created_at", "updated_at", "title", "status"}},
+		{collection: "notes", scope: "project_id", fields: []string{"created_at", "updated_at", "title"}},
+		{collection: "projects", scope: "members.user_id", fields: []string{"created_at", "updated_at", "name"}},
+		{collection: "webhook_deliveries", scope: "webhook_id", fields: []string{"created_at"}},
+	}
+	for _, p := range paged {
+		for _, field := range p.fields {
+			indexes = append(indexes, struct {
+				collection string
+				model      mongo.IndexModel
+			}{
+				collection: p.collection,
+				model: mongo.IndexModel{
+					Keys: bson.D{{Key: p.scope, Value: 1}, {Key: field, Value: 1}, {Key: "_id", Value: 1}},
+				},
+			})
+		}
 	}
 
 	for _, idx := range indexes {
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// BackfillRoles gives projects created before custom roles an empty list
+// of them.
+func BackfillRoles(db *mongo.Database, log *slog.Logger) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	res, err := db.Collection("projects").UpdateMany(ctx,
+		bson.M{"roles": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"roles": bson.A{}}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.ModifiedCount > 0 {
+		log.Info("roles backfilled", "projects", res.ModifiedCount)
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/0DayMonxrch/project-management-system/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// BackfillWorkflows gives projects created before per-project workflows the
+// default todo/in_progress/done workflow.
+func BackfillWorkflows(db *mongo.Database, log *slog.Logger) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	res, err := db.Collection("projects").UpdateMany(ctx,
+		bson.M{"workflow.statuses.0": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"workflow": domain.DefaultWorkflow()}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.ModifiedCount > 0 {
+		log.Info("workflows backfilled", "projects", res.ModifiedCount)
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,109 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 seed from RFC 6238 Appendix B.
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+// RFC 6238 Appendix B lists 8-digit SHA-1 values; 6-digit codes are their
+// last six digits.
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},          // 94287082
+	{1111111109, "081804"},  // 07081804
+	{1111111111, "050471"},  // 14050471
+	{1234567890, "005924"},  // 89005924
+	{2000000000, "279037"},  // 69279037
+	{20000000000, "353130"}, // 65353130
+}
+
+func TestCodeRFC6238Vectors(t *testing.T) {
+	for _, v := range rfcVectors {
+		got, err := Code(rfcSecret, time.Unix(v.unix, 0))
+		if err != nil {
+			t.Fatalf("Code at %d: %v", v.unix, err)
+		}
+		if got != v.code {
+			t.Errorf("Code at %d = %s, want %s", v.unix, got, v.code)
+		}
+	}
+}
+
+func TestValidateRFC6238Vectors(t *testing.T) {
+	for _, v := range rfcVectors {
+		at := time.Unix(v.unix, 0)
+		step, ok := Validate(rfcSecret, v.code, at, 0)
+		if !ok || step != Step(at) {
+			t.Errorf("Validate at %d = %d, %v; want %d, true", v.unix, step, ok, Step(at))
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	at := time.Unix(1111111111, 0)
+	code, _ := Code(rfcSecret, at)
+
+	// The code is still accepted one step later with a skew of 1, and
+	// reports the step it was issued for
+	if step, ok := Validate(rfcSecret, code, at.Add(Period), 1); !ok || step != Step(at) {
+		t.Errorf("one step late: got %d, %v; want %d, true", step, ok, Step(at))
+	}
+	if _, ok := Validate(rfcSecret, code, at.Add(Period), 0); ok {
+		t.Error("one step late without skew: accepted")
+	}
+	if _, ok := Validate(rfcSecret, code, at.Add(2*Period), 1); ok {
+		t.Error("two steps late with skew 1: accepted")
+	}
+}
+
+func TestValidateRejects(t *testing.T) {
+	at := time.Unix(59, 0)
+	for name, tc := range map[string]struct{ secret, code string }{
+		"wrong code":   {rfcSecret, "000000"},
+		"8 digits":     {rfcSecret, "94287082"},
+		"empty code":   {rfcSecret, ""},
+		"bad secret":   {"not base32!", "287082"},
+		"empty secret": {"", "287082"},
+	} {
+		if _, ok := Validate(tc.secret, tc.code, at, 1); ok {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := GenerateSecret()
+	if a == b {
+		t.Error("two secrets are equal")
+	}
+	if _, err := Code(a, time.Now()); err != nil {
+		t.Errorf("generated secret doesn't decode: %v", err)
+	}
+}
+
+func TestURI(t *testing.T) {
+	u, err := url.Parse(URI("Project Camp", "ada@example.com", "JBSWY3DPEHPK3PXP"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" || u.Path != "/Project Camp:ada@example.com" {
+		t.Errorf("URI = %s", u)
+	}
+	q := u.Query()
+	for key, want := range map[string]string{"secret": "JBSWY3DPEHPK3PXP", "issuer": "Project Camp", "algorithm": "SHA1", "digits": "6", "period": "30"} {
+		if q.Get(key) != want {
+			t.Errorf("%s = %q, want %q", key, q.Get(key), want)
+		}
+	}
+}
\ No newline at end of file
@@ -3,6 +3,8 @@ package validator
 import (
 	"fmt"
 	"net/mail"
+	"net/netip"
+	"net/url"
 	"strings"
 )
 
@@ -71,6 +73,59 @@ func (v *Validator) Email(field, value string) *Validator {
 	return v
 }
 
+func (v *Validator) URL(field, value string) *Validator {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.errors = append(v.errors, ValidationError{Field: field, Message: "is not a valid http(s) URL"})
+	}
+	return v
+}
+
+// PublicURL is URL, but also rejects hosts that plainly point inside the
+// network: localhost and IP literals that aren't public. Hostnames can
+// still resolve anywhere, so whatever connects has to check again.
+func (v *Validator) PublicURL(field, value string) *Validator {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.errors = append(v.errors, ValidationError{Field: field, Message: "is not a valid http(s) URL"})
+		return v
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		v.errors = append(v.errors, ValidationError{Field: field, Message: "must not point to a private address"})
+		return v
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !IsPublicAddr(addr) {
+		v.errors = append(v.errors, ValidationError{Field: field, Message: "must not point to a private address"})
+	}
+	return v
+}
+
+// nonPublic lists special-purpose ranges that netip doesn't classify.
+var nonPublic = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// IsPublicAddr reports whether addr is a globally routable unicast address,
+// ruling out loopback, private, link-local and other special ranges.
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, p := range nonPublic {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
 func (v *Validator) OneOf(field, value string, allowed ...string) *Validator {
 	for _, a := range allowed {
 		if value == a {
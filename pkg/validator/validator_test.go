@@ -0,0 +1,36 @@
+package validator
+
+import "testing"
+
+func TestPublicURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://example.com/hook", true},
+		{"http://93.184.216.34:8080/hook", true},
+		{"https://[2606:4700::1111]/hook", true},
+		{"ftp://example.com/hook", false},
+		{"https:///hook", false},
+		{"http://localhost:8080/hook", false},
+		{"http://LOCALHOST./hook", false},
+		{"http://api.localhost/hook", false},
+		{"http://127.0.0.1/hook", false},
+		{"http://10.1.2.3/hook", false},
+		{"http://172.16.0.1/hook", false},
+		{"http://192.168.1.1/hook", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://100.64.0.1/hook", false},
+		{"http://0.0.0.0/hook", false},
+		{"http://[::1]/hook", false},
+		{"http://[fe80::1]/hook", false},
+		{"http://[fd00::1]/hook", false},
+		{"http://[::ffff:127.0.0.1]/hook", false},
+	}
+	for _, tt := range tests {
+		err := New().PublicURL("url", tt.url).Validate()
+		if got := err == nil; got != tt.want {
+			t.Errorf("PublicURL(%q) error = %v, want valid %v", tt.url, err, tt.want)
+		}
+	}
+}
\ No newline at end of file